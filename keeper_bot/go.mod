@@ -6,6 +6,8 @@ require (
 	github.com/ethereum/go-ethereum v1.13.8
 	github.com/robfig/cron/v3 v3.0.1
 	github.com/sirupsen/logrus v1.9.3
+	github.com/yalue/onnxruntime_go v1.27.0
+	go.uber.org/goleak v1.3.0
 )
 
 require (
@@ -0,0 +1,127 @@
+package keeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// Methods each contract ABI must define because the keeper calls them on
+// every cycle; the sub-account methods are optional and probed at runtime
+var (
+	requiredStrategyMethods = []string{
+		"emergencyDeleverage", "harvestRwaYield", "totalAITHoldings",
+		"totalCollateral", "totalBorrowed", "getLeverageMetrics",
+		"mETH", "usdc", "ait",
+	}
+	requiredInvoiceTokenMethods = []string{
+		"updateNav", "navPerToken", "lastNavUpdate", "totalSupply", "pool",
+		"decimals",
+	}
+)
+
+// contractABIs are the ABIs used to pack and unpack Veritas contract calls
+type contractABIs struct {
+	strategy     abi.ABI
+	invoiceToken abi.ABI
+	// kyc is loaded only from KYCABIPath; the built-in keeper makes no
+	// on-chain KYC calls
+	kyc abi.ABI
+}
+
+// loadContractABIs reads the ABI files configured in config, falling back to
+// the built-in minimal ABIs for any path left empty
+func loadContractABIs(config *Config) (contractABIs, error) {
+	abis := contractABIs{strategy: strategyABI, invoiceToken: invoiceTokenABI}
+
+	var err error
+	if config.StrategyABIPath != "" {
+		if abis.strategy, err = loadABIFile(config.StrategyABIPath, requiredStrategyMethods, strategyABI); err != nil {
+			return abis, fmt.Errorf("strategy ABI: %w", err)
+		}
+	}
+	if config.InvoiceABIPath != "" {
+		if abis.invoiceToken, err = loadABIFile(config.InvoiceABIPath, requiredInvoiceTokenMethods, invoiceTokenABI); err != nil {
+			return abis, fmt.Errorf("invoice token ABI: %w", err)
+		}
+	}
+	if config.KYCABIPath != "" {
+		if abis.kyc, err = loadABIFile(config.KYCABIPath, nil, abi.ABI{}); err != nil {
+			return abis, fmt.Errorf("KYC ABI: %w", err)
+		}
+	}
+	return abis, nil
+}
+
+// loadABIFile parses a JSON ABI file and checks it defines every required
+// method, and that every method it shares with builtin returns the same
+// values, since the keeper decodes their results by position and type. Both
+// a bare ABI array and a compiler artifact with an "abi" field are accepted.
+func loadABIFile(path string, required []string, builtin abi.ABI) (abi.ABI, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	definition := data
+	var artifact struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		if err := json.Unmarshal(data, &artifact); err != nil {
+			return abi.ABI{}, fmt.Errorf("malformed ABI file %s: %w", path, err)
+		}
+		if len(artifact.ABI) == 0 {
+			return abi.ABI{}, fmt.Errorf("malformed ABI file %s: no abi field", path)
+		}
+		definition = artifact.ABI
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(string(definition)))
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("malformed ABI file %s: %w", path, err)
+	}
+
+	var missing []string
+	for _, method := range required {
+		if _, ok := parsed.Methods[method]; !ok {
+			missing = append(missing, method)
+		}
+	}
+	if len(missing) > 0 {
+		return abi.ABI{}, fmt.Errorf("ABI file %s is missing methods: %s", path, strings.Join(missing, ", "))
+	}
+
+	var mismatched []string
+	for name, want := range builtin.Methods {
+		if got, ok := parsed.Methods[name]; ok && !sameOutputs(got.Outputs, want.Outputs) {
+			mismatched = append(mismatched, fmt.Sprintf("%s returns %s, want %s", name, outputTypes(got.Outputs), outputTypes(want.Outputs)))
+		}
+	}
+	if len(mismatched) > 0 {
+		slices.Sort(mismatched)
+		return abi.ABI{}, fmt.Errorf("ABI file %s has incompatible methods: %s", path, strings.Join(mismatched, "; "))
+	}
+	return parsed, nil
+}
+
+// sameOutputs reports whether two methods return the same value types in
+// the same order
+func sameOutputs(a, b abi.Arguments) bool {
+	return slices.EqualFunc(a, b, func(x, y abi.Argument) bool {
+		return x.Type.String() == y.Type.String()
+	})
+}
+
+// outputTypes formats a method's return types, e.g. (uint256,address)
+func outputTypes(outputs abi.Arguments) string {
+	types := make([]string, len(outputs))
+	for i, output := range outputs {
+		types[i] = output.Type.String()
+	}
+	return "(" + strings.Join(types, ",") + ")"
+}
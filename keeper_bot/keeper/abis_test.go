@@ -0,0 +1,77 @@
+package keeper
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeABIFile writes an ABI definition to a temporary file
+func writeABIFile(t *testing.T, definition string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "abi.json")
+	if err := os.WriteFile(path, []byte(definition), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadABIFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		wantErr    string
+	}{
+		{
+			name:       "built-in ABI",
+			definition: strategyABIJSON,
+		},
+		{
+			name:       "compiler artifact",
+			definition: `{"contractName":"Strategy","abi":` + strategyABIJSON + `}`,
+		},
+		{
+			name: "missing method",
+			definition: strings.Replace(strategyABIJSON,
+				`"name":"totalBorrowed"`, `"name":"totalDebt"`, 1),
+			wantErr: "missing methods: totalBorrowed",
+		},
+		{
+			name: "required method returns another type",
+			definition: strings.Replace(strategyABIJSON,
+				`"name":"totalCollateral","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]`,
+				`"name":"totalCollateral","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bool"}]`, 1),
+			wantErr: "totalCollateral returns (bool), want (uint256)",
+		},
+		{
+			name: "required method returns fewer values",
+			definition: strings.Replace(strategyABIJSON,
+				`,{"name":"aitValue","type":"uint256"},{"name":"netExposure","type":"uint256"}`, ``, 1),
+			wantErr: "getLeverageMetrics returns (uint256,uint256), want (uint256,uint256,uint256,uint256)",
+		},
+		{
+			name: "optional method returns another type",
+			definition: strings.Replace(strategyABIJSON,
+				`"outputs":[{"name":"","type":"address[]"}]`, `"outputs":[{"name":"","type":"uint256[]"}]`, 1),
+			wantErr: "getSubAccounts returns (uint256[]), want (address[])",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := loadABIFile(writeABIFile(t, tt.definition), requiredStrategyMethods, strategyABI)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("loadABIFile: %v", err)
+				}
+				if len(parsed.Methods) != len(strategyABI.Methods) {
+					t.Errorf("parsed %d methods, want %d", len(parsed.Methods), len(strategyABI.Methods))
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadABIFile error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ML recommendations handled by the default risk actions
+const (
+	RecEmergencyDeleverage = "EMERGENCY_DELEVERAGE"
+	RecReduceLeverage      = "REDUCE_LEVERAGE"
+	RecPauseNewPositions   = "PAUSE_NEW_POSITIONS"
+)
+
+// Severity of the default risk actions. When the ML engine returns several
+// recommendations only one is executed per cycle, chosen by
+// RecommendationPolicy.
+const (
+	SeverityReduce    = 100
+	SeverityPause     = 200
+	SeverityEmergency = 300
+)
+
+// RiskAction is the keeper's response to an ML recommendation
+type RiskAction struct {
+	// Handler performs the action against the assessed strategy, returning
+	// the transaction it sent, if any
+	Handler func(ctx context.Context, strategy common.Address) (*types.Transaction, error)
+	// Severity ranks conflicting recommendations for RecommendationPolicy
+	Severity int
+}
+
+// RegisterRiskAction registers or replaces the action for a recommendation
+func (b *Bot) RegisterRiskAction(recommendation string, action RiskAction) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.riskActions[recommendation] = action
+}
+
+// riskAction looks up the registered action for a recommendation
+func (b *Bot) riskAction(recommendation string) (RiskAction, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	action, ok := b.riskActions[recommendation]
+	return action, ok
+}
+
+// registerDefaultRiskActions installs the built-in recommendation handlers
+func (b *Bot) registerDefaultRiskActions() {
+	b.RegisterRiskAction(RecEmergencyDeleverage, RiskAction{
+		Severity: SeverityEmergency,
+		Handler: func(ctx context.Context, strategy common.Address) (*types.Transaction, error) {
+			b.logger.WithField("strategy", strategy.Hex()).Warn("EMERGENCY DELEVERAGING TRIGGERED")
+			return b.emergencyDeleverage(ctx, strategy)
+		},
+	})
+
+	b.RegisterRiskAction(RecReduceLeverage, RiskAction{
+		Severity: SeverityReduce,
+		Handler: func(ctx context.Context, strategy common.Address) (*types.Transaction, error) {
+			b.logger.WithField("strategy", strategy.Hex()).Info("Reducing leverage position")
+			return b.reduceLeverage(ctx, strategy)
+		},
+	})
+
+	b.RegisterRiskAction(RecPauseNewPositions, RiskAction{
+		Severity: SeverityPause,
+		Handler: func(ctx context.Context, strategy common.Address) (*types.Transaction, error) {
+			// The strategy contract exposes no pause method yet, so nothing is
+			// sent; escalateToPause alerts rather than reporting a pause
+			b.logger.WithField("strategy", strategy.Hex()).Warn("Pausing new positions is not supported by the strategy contract")
+			return nil, nil
+		},
+	})
+}
+
+// Policies for choosing among several applicable recommendations
+const (
+	// RecommendationPolicyMostAggressive acts on the most severe recommendation
+	RecommendationPolicyMostAggressive = "most_aggressive"
+	// RecommendationPolicyLeastAggressive acts on the least severe recommendation
+	RecommendationPolicyLeastAggressive = "least_aggressive"
+	// RecommendationPolicyMLOrder acts on the first registered
+	// recommendation in the order the ML engine returned them
+	RecommendationPolicyMLOrder = "ml_order"
+)
+
+// validateRecommendationPolicy rejects unknown recommendation policies
+func validateRecommendationPolicy(policy string) error {
+	switch policy {
+	case RecommendationPolicyMostAggressive, RecommendationPolicyLeastAggressive, RecommendationPolicyMLOrder:
+		return nil
+	default:
+		return fmt.Errorf("unknown recommendation policy %q (want %s, %s or %s)",
+			policy, RecommendationPolicyMostAggressive, RecommendationPolicyLeastAggressive, RecommendationPolicyMLOrder)
+	}
+}
+
+// policyPrefers reports whether policy picks candidate over the current choice;
+// ties keep the earlier recommendation
+func policyPrefers(policy string, candidate, current RiskAction) bool {
+	switch policy {
+	case RecommendationPolicyLeastAggressive:
+		return candidate.Severity < current.Severity
+	case RecommendationPolicyMLOrder:
+		return false
+	default:
+		return candidate.Severity > current.Severity
+	}
+}
+
+// selectRiskAction picks the registered recommendation RecommendationPolicy
+// prefers. It returns the chosen recommendation, the recommendations it
+// overrides, and those with no registered action. ok is false when nothing
+// actionable was recommended.
+func (b *Bot) selectRiskAction(recommendations []string) (chosen string, action RiskAction, skipped, unknown []string, ok bool) {
+	var known []string
+	for _, recommendation := range recommendations {
+		candidate, registered := b.riskAction(recommendation)
+		if !registered {
+			unknown = append(unknown, recommendation)
+			continue
+		}
+
+		known = append(known, recommendation)
+		if !ok || policyPrefers(b.cfg().RecommendationPolicy, candidate, action) {
+			chosen, action, ok = recommendation, candidate, true
+		}
+	}
+
+	for _, recommendation := range known {
+		if recommendation != chosen {
+			skipped = append(skipped, recommendation)
+		}
+	}
+	return chosen, action, skipped, unknown, ok
+}
@@ -0,0 +1,59 @@
+package keeper
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRecommendationPolicies(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	position := &StrategyPosition{TotalBorrowed: 100, HealthFactor: 2, LTV: 0.4}
+	// One response recommending every default action, in neither severity
+	// order, behind a recommendation no action is registered for
+	response := fmt.Appendf(nil, `{"composite_risk_score":0.8,"risk_level":"HIGH","action_required":true,"confidence":0.9,"timestamp":%d,
+		"recommendations":["REBALANCE_HEDGE","PAUSE_NEW_POSITIONS","REDUCE_LEVERAGE","EMERGENCY_DELEVERAGE"]}`, now.Unix())
+
+	tests := []struct {
+		policy      string
+		wantChosen  string
+		wantSkipped []string
+	}{
+		{RecommendationPolicyMostAggressive, RecEmergencyDeleverage, []string{RecPauseNewPositions, RecReduceLeverage}},
+		{RecommendationPolicyLeastAggressive, RecReduceLeverage, []string{RecPauseNewPositions, RecEmergencyDeleverage}},
+		{RecommendationPolicyMLOrder, RecPauseNewPositions, []string{RecReduceLeverage, RecEmergencyDeleverage}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			if err := validateRecommendationPolicy(tt.policy); err != nil {
+				t.Fatal(err)
+			}
+			config := testConfig(t)
+			config.RecommendationPolicy = tt.policy
+			bot, _ := newTestBot(t, config, nil)
+
+			decision, err := bot.decideRiskAction(now, position, response)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !decision.ok || decision.chosen != tt.wantChosen {
+				t.Fatalf("chosen = %q (ok %t), want %q", decision.chosen, decision.ok, tt.wantChosen)
+			}
+			if !slices.Equal(decision.skipped, tt.wantSkipped) {
+				t.Fatalf("skipped = %q, want %q", decision.skipped, tt.wantSkipped)
+			}
+			if want := []string{"REBALANCE_HEDGE"}; !slices.Equal(decision.unknown, want) {
+				t.Fatalf("unknown = %q, want %q", decision.unknown, want)
+			}
+		})
+	}
+}
+
+func TestValidateRecommendationPolicyRejectsUnknown(t *testing.T) {
+	for _, policy := range []string{"", "random", "MOST_AGGRESSIVE"} {
+		if err := validateRecommendationPolicy(policy); err == nil {
+			t.Errorf("validateRecommendationPolicy(%q) accepted an unknown policy", policy)
+		}
+	}
+}
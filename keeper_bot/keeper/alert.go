@@ -0,0 +1,160 @@
+package keeper
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Alert severities
+const (
+	AlertWarning  = "warning"
+	AlertCritical = "critical"
+)
+
+// severityRank orders severities for routing; unknown severities rank lowest
+func severityRank(severity string) int {
+	switch severity {
+	case AlertCritical:
+		return 2
+	case AlertWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Alert is an operator notification raised by the bot
+type Alert struct {
+	Severity string                 `json:"severity"`
+	Title    string                 `json:"title"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	Time     time.Time              `json:"time"`
+}
+
+// text renders an alert as a plain chat message
+func (a Alert) text() string {
+	text := fmt.Sprintf("[%s] %s", a.Severity, a.Title)
+
+	keys := make([]string, 0, len(a.Fields))
+	for key := range a.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		text += fmt.Sprintf("\n• %s: %v", key, a.Fields[key])
+	}
+	return text
+}
+
+// alertRoutes holds the lowest severity each sink receives; it is shared by
+// every view of an Alerter and replaced on config reload
+type alertRoutes struct {
+	mu          sync.RWMutex
+	minSeverity map[string]string
+}
+
+// accepts reports whether the named sink receives alerts of severity;
+// unrouted sinks receive every alert
+func (r *alertRoutes) accepts(sink, severity string) bool {
+	r.mu.RLock()
+	minSeverity := r.minSeverity[sink]
+	r.mu.RUnlock()
+	if minSeverity == "" {
+		minSeverity = AlertWarning
+	}
+	return severityRank(severity) >= severityRank(minSeverity)
+}
+
+// Alerter logs alerts and fans them out to every configured sink whose
+// severity route accepts them
+type Alerter struct {
+	sinks   []AlertSink
+	routes  *alertRoutes
+	logger  *logrus.Logger
+	pending *sync.WaitGroup
+	// chain labels every alert when the keeper runs several chains
+	chain string
+}
+
+// NewAlerter creates an alerter delivering to the sinks enabled in config;
+// with none enabled alerts are logged only
+func NewAlerter(config *Config, logger *logrus.Logger) *Alerter {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return &Alerter{
+		sinks:   newAlertSinks(config, client),
+		routes:  &alertRoutes{minSeverity: config.AlertRouting},
+		logger:  logger,
+		pending: new(sync.WaitGroup),
+	}
+}
+
+// SetRouting replaces the severity routes of every sink
+func (a *Alerter) SetRouting(routing map[string]string) {
+	a.routes.mu.Lock()
+	a.routes.minSeverity = routing
+	a.routes.mu.Unlock()
+}
+
+// validateAlertRouting checks every route names a known severity
+func validateAlertRouting(routing map[string]string) error {
+	for sink, severity := range routing {
+		if severityRank(severity) == 0 {
+			return fmt.Errorf("invalid alert routing severity %q for sink %s", severity, sink)
+		}
+	}
+	return nil
+}
+
+// forChain returns an alerter delivering to the same sinks that labels
+// alerts with chain and logs them to logger; an empty chain adds no label
+func (a *Alerter) forChain(chain string, logger *logrus.Logger) *Alerter {
+	labeled := *a
+	labeled.chain, labeled.logger = chain, logger
+	return &labeled
+}
+
+// Send logs an alert and delivers it to each routed sink in the background.
+// Sinks are independent: a slow or failing one never delays the others.
+func (a *Alerter) Send(alert Alert) {
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+	if a.chain != "" {
+		fields := make(map[string]interface{}, len(alert.Fields)+1)
+		for key, value := range alert.Fields {
+			fields[key] = value
+		}
+		fields["chain"] = a.chain
+		alert.Fields = fields
+	}
+
+	entry := a.logger.WithFields(logrus.Fields(alert.Fields)).WithField("alert_severity", alert.Severity)
+	if alert.Severity == AlertCritical {
+		entry.Error("ALERT: " + alert.Title)
+	} else {
+		entry.Warn("ALERT: " + alert.Title)
+	}
+
+	for _, sink := range a.sinks {
+		if !a.routes.accepts(sink.Name(), alert.Severity) {
+			continue
+		}
+		a.pending.Add(1)
+		go func() {
+			defer a.pending.Done()
+			if err := sink.Send(alert); err != nil {
+				a.logger.WithError(err).WithField("sink", sink.Name()).Error("Failed to deliver alert")
+			}
+		}()
+	}
+}
+
+// Wait blocks until in-progress alert deliveries finish
+func (a *Alerter) Wait() {
+	a.pending.Wait()
+}
@@ -0,0 +1,91 @@
+package keeper
+
+import (
+	"maps"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sendDeduped sends an alert about a condition that can persist across
+// cycles, identified by key, unless the same key was alerted within
+// AlertDedupRetention. Sent keys are saved to the Store so a restart in
+// the middle of a condition does not alert it again.
+func (b *Bot) sendDeduped(key string, alert Alert) {
+	retention := b.cfg().AlertDedupRetention
+	if retention <= 0 {
+		b.alerter.Send(alert)
+		return
+	}
+
+	now := time.Now()
+	b.mutex.Lock()
+	if last, ok := b.alertsSent[key]; ok && now.Sub(last) < retention {
+		b.mutex.Unlock()
+		b.logger.WithFields(logrus.Fields{
+			"alert":     alert.Title,
+			"dedup_key": key,
+			"last_sent": last,
+		}).Debug("Alert already sent, suppressing duplicate")
+		return
+	}
+	b.alertsSent[key] = now
+	pruneAlertsSent(b.alertsSent, now, retention)
+	b.mutex.Unlock()
+
+	b.saveAlertDedup()
+	b.alerter.Send(alert)
+}
+
+// clearDeduped re-arms the alert for key once its condition has cleared,
+// so a recurrence alerts again straight away
+func (b *Bot) clearDeduped(key string) {
+	b.mutex.Lock()
+	_, ok := b.alertsSent[key]
+	delete(b.alertsSent, key)
+	b.mutex.Unlock()
+	if ok {
+		b.saveAlertDedup()
+	}
+}
+
+// saveAlertDedup persists the sent alert keys; a failure only risks a
+// duplicate alert after a restart. Saves are serialized and each takes its
+// snapshot once it holds alertsSaveMu, so the last save is the latest state.
+func (b *Bot) saveAlertDedup() {
+	b.alertsSaveMu.Lock()
+	defer b.alertsSaveMu.Unlock()
+
+	b.mutex.Lock()
+	sent := maps.Clone(b.alertsSent)
+	b.mutex.Unlock()
+	if err := Save(b.store, keyAlertsSent, sent); err != nil {
+		b.logger.WithError(err).Warn("Failed to persist alert dedup state")
+	}
+}
+
+// restoreAlertDedup loads the alert keys sent by a previous run that are
+// still within AlertDedupRetention
+func (b *Bot) restoreAlertDedup() error {
+	sent, ok, err := Load(b.store, keyAlertsSent)
+	if err != nil || !ok {
+		return err
+	}
+	pruneAlertsSent(sent, time.Now(), b.cfg().AlertDedupRetention)
+	if len(sent) == 0 {
+		return nil
+	}
+
+	b.mutex.Lock()
+	maps.Copy(b.alertsSent, sent)
+	b.mutex.Unlock()
+	b.logger.WithField("alerts", len(sent)).Info("Restored alert dedup state, suppressing recently sent alerts")
+	return nil
+}
+
+// pruneAlertsSent drops keys sent longer than retention ago
+func pruneAlertsSent(sent map[string]time.Time, now time.Time, retention time.Duration) {
+	maps.DeleteFunc(sent, func(_ string, last time.Time) bool {
+		return now.Sub(last) >= retention
+	})
+}
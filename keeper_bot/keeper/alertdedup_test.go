@@ -0,0 +1,70 @@
+package keeper
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// restartBot starts a new test bot on the state file at path, restoring its
+// state as newBot does
+func restartBot(t *testing.T, config *Config, path string) (*Bot, *recordingSink) {
+	t.Helper()
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot, sink := newTestBot(t, config, store)
+	if err := bot.restoreState(); err != nil {
+		t.Fatalf("restoreState: %v", err)
+	}
+	return bot, sink
+}
+
+func TestAlertDedupSurvivesRestart(t *testing.T) {
+	config := testConfig(t)
+	config.AlertDedupRetention = 4 * time.Hour
+	path := filepath.Join(t.TempDir(), "state.json")
+	alert := func(title string) Alert { return Alert{Severity: AlertWarning, Title: title} }
+
+	before, sink := restartBot(t, config, path)
+	before.sendDeduped("low_balance:keeper", alert("Low balance"))
+	before.sendDeduped("clock_skew:ml", alert("Clock skew"))
+	before.clearDeduped("clock_skew:ml")
+	before.alerter.Wait()
+	if got := sink.titles(); !slices.Equal(got, []string{"Clock skew", "Low balance"}) {
+		t.Fatalf("alerts before restart = %q", got)
+	}
+
+	after, sink := restartBot(t, config, path)
+	after.sendDeduped("low_balance:keeper", alert("Low balance"))
+	after.sendDeduped("clock_skew:ml", alert("Clock skew"))
+	after.alerter.Wait()
+	// The persisting condition stays quiet; the cleared one alerts again
+	if got := sink.titles(); !slices.Equal(got, []string{"Clock skew"}) {
+		t.Errorf("alerts after restart = %q, want only the re-armed one", got)
+	}
+}
+
+func TestAlertDedupExpiresAcrossRestart(t *testing.T) {
+	config := testConfig(t)
+	config.AlertDedupRetention = 4 * time.Hour
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := map[string]time.Time{"low_balance:keeper": time.Now().Add(-5 * time.Hour)}
+	if err := Save(store, keyAlertsSent, sent); err != nil {
+		t.Fatal(err)
+	}
+
+	bot, sink := restartBot(t, config, path)
+	bot.sendDeduped("low_balance:keeper", Alert{Severity: AlertWarning, Title: "Low balance"})
+	bot.alerter.Wait()
+	if got := sink.titles(); !slices.Equal(got, []string{"Low balance"}) {
+		t.Errorf("alerts = %q, want the expired alert sent again", got)
+	}
+}
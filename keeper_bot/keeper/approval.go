@@ -0,0 +1,83 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/sirupsen/logrus"
+)
+
+const erc20ApprovalABIJSON = `[
+	{"type":"function","name":"allowance","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+var erc20ApprovalABI = mustParseABI(erc20ApprovalABIJSON)
+
+// approvalSuffix names the approval sent ahead of an action, e.g.
+// emergency_deleverage_approval, which shares the action's urgency
+const approvalSuffix = "_approval"
+
+// approvalKey identifies an allowance the keeper grants
+type approvalKey struct {
+	token   common.Address
+	spender common.Address
+}
+
+// pendingApproval is an approval broadcast but possibly not yet mined
+type pendingApproval struct {
+	amount *big.Int
+	sentAt time.Time
+}
+
+// ensureAllowance makes sure spender may pull amount of token from the
+// keeper before action runs, sending an approval first when the on-chain
+// allowance is short. The approval is for exactly amount unless
+// UseInfiniteApproval is set. An approval already sent for at least amount
+// and younger than TxConfirmTimeout counts as granted, so the action is not
+// preceded by a duplicate. It reports whether an approval was sent.
+func (b *Bot) ensureAllowance(ctx context.Context, action string, token, spender common.Address, amount *big.Int) (bool, error) {
+	config := b.cfg()
+	out, err := b.callContract(ctx, nil, erc20ApprovalABI, token, "allowance", b.keeperAddress(), spender)
+	if err != nil {
+		return false, fmt.Errorf("failed to read allowance: %w", err)
+	}
+	allowance := out[0].(*big.Int)
+	if allowance.Cmp(amount) >= 0 {
+		return false, nil
+	}
+
+	key := approvalKey{token: token, spender: spender}
+	b.mutex.Lock()
+	pending, ok := b.pendingApprovals[key]
+	b.mutex.Unlock()
+	if ok && pending.amount.Cmp(amount) >= 0 && time.Since(pending.sentAt) < config.TxConfirmTimeout {
+		return false, nil
+	}
+
+	approve := amount
+	if config.UseInfiniteApproval {
+		approve = math.MaxBig256
+	}
+	tx, err := b.sendTx(ctx, action+approvalSuffix, erc20ApprovalABI, token, "approve", spender, approve)
+	if err != nil {
+		return false, err
+	}
+
+	b.mutex.Lock()
+	b.pendingApprovals[key] = pendingApproval{amount: approve, sentAt: time.Now()}
+	b.mutex.Unlock()
+
+	b.logger.WithFields(logrus.Fields{
+		"token":     token.Hex(),
+		"spender":   spender.Hex(),
+		"allowance": allowance.String(),
+		"amount":    approve.String(),
+		"tx":        tx.Hash().Hex(),
+	}).Info("Allowance short, approval sent")
+	return true, nil
+}
@@ -0,0 +1,331 @@
+package keeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/sirupsen/logrus"
+)
+
+// NAV submission modes selectable via Config.NAVSubmitMode
+const (
+	// NAVSubmitSend sends updateNav transactions from the keeper account
+	NAVSubmitSend = "send"
+	// NAVSubmitAttest signs an EIP-712 attestation for a relayer to submit
+	NAVSubmitAttest = "attest"
+)
+
+// EIP-712 domain of keeper attestations
+const (
+	attestationDomainName    = "Veritas Keeper"
+	attestationDomainVersion = "1"
+)
+
+// Attestation is a keeper-signed EIP-712 message for an off-chain relayer
+type Attestation struct {
+	TypedData apitypes.TypedData `json:"typed_data"`
+	Digest    common.Hash        `json:"digest"`
+	Signature hexutil.Bytes      `json:"signature"`
+	Signer    common.Address     `json:"signer"`
+}
+
+// validateNAVSubmitMode rejects unknown NAV submission modes
+func validateNAVSubmitMode(mode string) error {
+	switch mode {
+	case "", NAVSubmitSend, NAVSubmitAttest:
+		return nil
+	default:
+		return fmt.Errorf("unknown NAV submit mode %q (want %s or %s)", mode, NAVSubmitSend, NAVSubmitAttest)
+	}
+}
+
+// SignTypedData signs EIP-712 typed data with the keeper key, returning the
+// digest and a 65-byte signature with V in {27, 28}
+func (b *Bot) SignTypedData(typedData apitypes.TypedData) (*Attestation, error) {
+	digest, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+
+	privateKey, address := b.signer()
+	signature, err := crypto.Sign(digest, privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+
+	return &Attestation{
+		TypedData: typedData,
+		Digest:    common.BytesToHash(digest),
+		Signature: signature,
+		Signer:    address,
+	}, nil
+}
+
+// recoverAttestationSigner returns the address that signed an attestation
+func recoverAttestationSigner(attestation *Attestation) (common.Address, error) {
+	if len(attestation.Signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length %d", len(attestation.Signature))
+	}
+	signature := bytes.Clone(attestation.Signature)
+	signature[crypto.RecoveryIDOffset] -= 27
+
+	publicKey, err := crypto.SigToPub(attestation.Digest.Bytes(), signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// navAttestation builds the typed data attesting a token's NAV at block,
+// bound to the token contract and chain
+func (b *Bot) navAttestation(token common.Address, navWei *big.Int, confidence float64, block *big.Int, timestamp int64) apitypes.TypedData {
+	confidenceBps := int64(confidence*1e4 + 0.5)
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"NAVUpdate": {
+				{Name: "token", Type: "address"},
+				{Name: "nav", Type: "uint256"},
+				{Name: "confidenceBps", Type: "uint256"},
+				{Name: "blockNumber", Type: "uint256"},
+				{Name: "timestamp", Type: "uint256"},
+			},
+		},
+		PrimaryType: "NAVUpdate",
+		Domain: apitypes.TypedDataDomain{
+			Name:              attestationDomainName,
+			Version:           attestationDomainVersion,
+			ChainId:           (*math.HexOrDecimal256)(b.chainID),
+			VerifyingContract: token.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"token":         token.Hex(),
+			"nav":           navWei.String(),
+			"confidenceBps": big.NewInt(confidenceBps).String(),
+			"blockNumber":   block.String(),
+			"timestamp":     big.NewInt(timestamp).String(),
+		},
+	}
+}
+
+// NAVInputAttestation is a keeper-signed record of what a published NAV was
+// derived from, with the pool data its hash commits to so consumers can
+// recompute it
+type NAVInputAttestation struct {
+	*Attestation
+	Pool NAVRequest `json:"pool"`
+}
+
+// navInputAttestation builds the typed data attesting the inputs and
+// prediction behind a published NAV. The pool data is committed to as the
+// keccak256 hash of its JSON encoding.
+func (b *Bot) navInputAttestation(token common.Address, poolHash common.Hash, predictedWei, publishedWei *big.Int, confidence float64, modelVersion string, block *big.Int, timestamp int64) apitypes.TypedData {
+	confidenceBps := int64(confidence*1e4 + 0.5)
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"NAVInputs": {
+				{Name: "token", Type: "address"},
+				{Name: "poolDataHash", Type: "bytes32"},
+				{Name: "predictedNav", Type: "uint256"},
+				{Name: "publishedNav", Type: "uint256"},
+				{Name: "confidenceBps", Type: "uint256"},
+				{Name: "modelVersion", Type: "string"},
+				{Name: "blockNumber", Type: "uint256"},
+				{Name: "timestamp", Type: "uint256"},
+			},
+		},
+		PrimaryType: "NAVInputs",
+		Domain: apitypes.TypedDataDomain{
+			Name:              attestationDomainName,
+			Version:           attestationDomainVersion,
+			ChainId:           (*math.HexOrDecimal256)(b.chainID),
+			VerifyingContract: token.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"token":         token.Hex(),
+			"poolDataHash":  poolHash.Hex(),
+			"predictedNav":  predictedWei.String(),
+			"publishedNav":  publishedWei.String(),
+			"confidenceBps": big.NewInt(confidenceBps).String(),
+			"modelVersion":  modelVersion,
+			"blockNumber":   block.String(),
+			"timestamp":     big.NewInt(timestamp).String(),
+		},
+	}
+}
+
+// attestNAVInputs signs and audits an attestation of the inputs behind a
+// published NAV and POSTs it to AttestationSinkURL when set. The NAV is
+// already published, so a failure is returned for logging only. In dry-run
+// mode and during the startup grace period nothing is posted.
+func (b *Bot) attestNAVInputs(ctx context.Context, token common.Address, pool NAVRequest, predicted, published *big.Rat, confidence float64, block *big.Int) (*NAVInputAttestation, error) {
+	config := b.cfg()
+	inGrace := b.InStartupGrace()
+	record := AuditRecord{
+		Action:   "nav_input_attestation",
+		DryRun:   config.DryRun || inGrace,
+		Contract: token.Hex(),
+
+		IdempotencyKey: idempotencyKeyFrom(ctx),
+	}
+
+	poolJSON, err := json.Marshal(pool)
+	if err != nil {
+		return nil, err
+	}
+	poolHash := crypto.Keccak256Hash(poolJSON)
+	predictedWei := toFixedPoint(predicted, navDecimals)
+	publishedWei := toFixedPoint(published, navDecimals)
+
+	b.mutex.Lock()
+	var modelVersion string
+	if b.modelInfo != nil {
+		modelVersion = b.modelInfo.Version
+	}
+	b.mutex.Unlock()
+
+	record.Inputs = auditInputs([]interface{}{poolHash.Hex(), predictedWei, publishedWei, b.score(confidence), modelVersion})
+	attestation, err := b.SignTypedData(b.navInputAttestation(token, poolHash, predictedWei, publishedWei, confidence, modelVersion, block, time.Now().Unix()))
+	if err == nil {
+		record.Signature = attestation.Signature.String()
+		record.Digest = attestation.Digest.Hex()
+	}
+	signed := &NAVInputAttestation{Attestation: attestation, Pool: pool}
+	if err == nil && !record.DryRun && config.AttestationSinkURL != "" {
+		err = b.postJSON(ctx, config.AttestationSinkURL, signed)
+	}
+	if err != nil {
+		record.Outcome, record.Error = AuditFailed, err.Error()
+		b.audit(record)
+		return nil, fmt.Errorf("NAV input attestation failed: %w", err)
+	}
+
+	record.Outcome = AuditAttested
+	if record.DryRun {
+		record.Outcome = AuditDryRun
+	}
+	b.audit(record)
+
+	b.logger.WithFields(logrus.Fields{
+		"token":          token.Hex(),
+		"pool_data_hash": poolHash.Hex(),
+		"digest":         attestation.Digest.Hex(),
+		"published":      !record.DryRun && config.AttestationSinkURL != "",
+	}).Info("NAV input attestation signed")
+	return signed, nil
+}
+
+// attestNAV signs a NAV attestation and hands it to the relayer. In dry-run
+// mode and during the startup grace period it is signed and audited only.
+func (b *Bot) attestNAV(ctx context.Context, token common.Address, navWei *big.Int, confidence float64, block *big.Int) (*Attestation, error) {
+	config := b.cfg()
+	inGrace := b.InStartupGrace()
+	record := AuditRecord{
+		Action:   "nav_attestation",
+		DryRun:   config.DryRun || inGrace,
+		Contract: token.Hex(),
+		Method:   "updateNav",
+		Inputs:   auditInputs([]interface{}{navWei}),
+
+		IdempotencyKey: idempotencyKeyFrom(ctx),
+	}
+
+	attestation, err := b.SignTypedData(b.navAttestation(token, navWei, confidence, block, time.Now().Unix()))
+	if err == nil {
+		// Never hand a relayer a signature the contract would reject
+		var signer common.Address
+		signer, err = recoverAttestationSigner(attestation)
+		if err == nil && signer != attestation.Signer {
+			err = fmt.Errorf("attestation recovers to %s, not keeper %s", signer.Hex(), attestation.Signer.Hex())
+		}
+	}
+	if err == nil {
+		record.Signature = attestation.Signature.String()
+		if !record.DryRun {
+			err = b.postAttestation(ctx, attestation)
+		}
+	}
+	if err != nil {
+		record.Outcome, record.Error = AuditFailed, err.Error()
+		b.audit(record)
+		return nil, fmt.Errorf("NAV attestation failed: %w", err)
+	}
+
+	record.Outcome = AuditAttested
+	if record.DryRun {
+		record.Outcome = AuditDryRun
+	}
+	b.audit(record)
+
+	b.logger.WithFields(logrus.Fields{
+		"token":     token.Hex(),
+		"nav_wei":   navWei.String(),
+		"digest":    attestation.Digest.Hex(),
+		"submitted": !record.DryRun && config.AttestationRelayURL != "",
+	}).Info("NAV attestation signed")
+	return attestation, nil
+}
+
+// postAttestation delivers an attestation to AttestationRelayURL; without a
+// relay URL attestations are only recorded in the audit log
+func (b *Bot) postAttestation(ctx context.Context, attestation *Attestation) error {
+	config := b.cfg()
+	if config.AttestationRelayURL == "" {
+		return nil
+	}
+	return b.postJSON(ctx, config.AttestationRelayURL, attestation)
+}
+
+// postJSON POSTs an attestation body to an attestation endpoint, bounded by
+// MLRequestTimeout and keyed by ctx's idempotency key
+func (b *Bot) postJSON(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, b.cfg().MLRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if key := idempotencyKeyFrom(ctx); key != "" {
+		req.Header.Set(idempotencyHeader, key)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("attestation endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package keeper
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+func TestSignTypedDataRecoversToKeeper(t *testing.T) {
+	bot, _ := newTestBot(t, testConfig(t), nil)
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	typedData := bot.navAttestation(token, big.NewInt(1_020_000), 0.93, big.NewInt(123), 1_700_000_000)
+
+	attestation, err := bot.SignTypedData(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attestation.Signer != bot.address {
+		t.Fatalf("Signer = %s, want %s", attestation.Signer.Hex(), bot.address.Hex())
+	}
+	digest, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attestation.Digest != common.BytesToHash(digest) {
+		t.Fatalf("Digest = %s, want EIP-712 hash %x", attestation.Digest.Hex(), digest)
+	}
+	if v := attestation.Signature[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Fatalf("V = %d, want 27 or 28", v)
+	}
+
+	signer, err := recoverAttestationSigner(attestation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer != bot.address {
+		t.Fatalf("signature recovers to %s, want keeper %s", signer.Hex(), bot.address.Hex())
+	}
+
+	// A signature over different data must not recover to the keeper
+	tampered := *attestation
+	tampered.Digest = crypto.Keccak256Hash([]byte("tampered"))
+	if signer, err := recoverAttestationSigner(&tampered); err == nil && signer == bot.address {
+		t.Fatal("tampered attestation still recovers to the keeper")
+	}
+}
+
+func TestAttestNAVInputsRecoversToKeeper(t *testing.T) {
+	bot, _ := newTestBot(t, testConfig(t), nil)
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	pool := NAVRequest{
+		TotalFaceValue:   1_000_000,
+		NumberOfInvoices: 12,
+		WeightedMaturity: 45,
+		ExpectedYield:    0.08,
+		DefaultRate:      0.01,
+		TotalSupply:      980_000,
+	}
+
+	signed, err := bot.attestNAVInputs(context.Background(), token, pool, big.NewRat(102, 100), big.NewRat(101, 100), 0.9, big.NewInt(123))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := recoverAttestationSigner(signed.Attestation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer != bot.address {
+		t.Fatalf("NAV input attestation recovers to %s, want keeper %s", signer.Hex(), bot.address.Hex())
+	}
+
+	// The digest commits to the pool data a consumer can rehash
+	poolJSON, err := json.Marshal(signed.Pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := signed.TypedData.Message["poolDataHash"], crypto.Keccak256Hash(poolJSON).Hex(); got != want {
+		t.Fatalf("poolDataHash = %v, want %s", got, want)
+	}
+	digest, _, err := apitypes.TypedDataAndHash(signed.TypedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed.Digest != common.BytesToHash(digest) {
+		t.Fatalf("Digest = %s, want EIP-712 hash %x", signed.Digest.Hex(), digest)
+	}
+}
@@ -0,0 +1,139 @@
+package keeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Audit outcomes. A live transaction gets a sent (or failed) record when
+// broadcast and a second record once its fate is known.
+const (
+	AuditDryRun      = "dry_run"
+	AuditSent        = "sent"
+	AuditFailed      = "failed"
+	AuditConfirmed   = "confirmed"
+	AuditReverted    = "reverted"
+	AuditUnconfirmed = "unconfirmed"
+	AuditAttested    = "attested"
+)
+
+// AuditRecord is one line of the audit log. Fields are only ever added to
+// this schema, never renamed or removed.
+type AuditRecord struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"`
+	Outcome  string    `json:"outcome"`
+	DryRun   bool      `json:"dry_run"`
+	Contract string    `json:"contract"`
+	Method   string    `json:"method"`
+	Inputs   []string  `json:"inputs"`
+	Nonce    uint64    `json:"nonce"`
+	GasLimit uint64    `json:"gas_limit"`
+	GasPrice string    `json:"gas_price_wei"`
+	TxHash   string    `json:"tx_hash"`
+	Block    uint64    `json:"block,omitempty"`
+	GasUsed  uint64    `json:"gas_used,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	// RevertReason is the decoded reason a transaction reverted, in
+	// simulation, on send or once mined
+	RevertReason string `json:"revert_reason,omitempty"`
+
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	Signature      string `json:"signature,omitempty"`
+	Digest         string `json:"digest,omitempty"`
+}
+
+// withTx fills in the transaction fields of a record
+func (r AuditRecord) withTx(tx *types.Transaction) AuditRecord {
+	r.Nonce = tx.Nonce()
+	r.GasLimit = tx.Gas()
+	r.GasPrice = tx.GasPrice().String()
+	r.TxHash = tx.Hash().Hex()
+	return r
+}
+
+// withReceipt fills in the mined outcome of a record
+func (r AuditRecord) withReceipt(receipt *types.Receipt) AuditRecord {
+	r.Outcome = AuditConfirmed
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		r.Outcome = AuditReverted
+	}
+	r.Block = receipt.BlockNumber.Uint64()
+	r.GasUsed = receipt.GasUsed
+	return r
+}
+
+// auditInputs renders contract call arguments for the audit log
+func auditInputs(args []interface{}) []string {
+	inputs := make([]string, 0, len(args))
+	for _, arg := range args {
+		if amount, ok := arg.(*big.Int); ok {
+			inputs = append(inputs, amount.String())
+			continue
+		}
+		inputs = append(inputs, fmt.Sprint(arg))
+	}
+	return inputs
+}
+
+// AuditLog appends AuditRecords as JSON lines to a dedicated file,
+// independent of the operational logger and its level
+type AuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenAuditLog opens path for appending; an empty path disables auditing
+func OpenAuditLog(path string) (*AuditLog, error) {
+	if path == "" {
+		return &AuditLog{}, nil
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &AuditLog{file: file}, nil
+}
+
+// Write appends a record and syncs it to disk
+func (a *AuditLog) Write(record AuditRecord) error {
+	if a.file == nil {
+		return nil
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return a.file.Sync()
+}
+
+// Close closes the audit file
+func (a *AuditLog) Close() error {
+	if a.file == nil {
+		return nil
+	}
+	return a.file.Close()
+}
+
+// audit writes a record, reporting failures on the operational log
+func (b *Bot) audit(record AuditRecord) {
+	if err := b.audits.Write(record); err != nil {
+		b.logger.WithError(err).WithField("action", record.Action).Error("Failed to write audit record")
+	}
+}
@@ -0,0 +1,135 @@
+package keeper
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// recordingSink is an AlertSink that keeps every alert it receives
+type recordingSink struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Send(alert Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alerts = append(s.alerts, alert)
+	return nil
+}
+
+// titles lists the titles of the alerts received so far, sorted since
+// sinks deliver concurrently
+func (s *recordingSink) titles() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	titles := make([]string, len(s.alerts))
+	for i, alert := range s.alerts {
+		titles[i] = alert.Title
+	}
+	slices.Sort(titles)
+	return titles
+}
+
+// testConfig returns the local profile defaults, which need no chain
+func testConfig(t *testing.T) *Config {
+	t.Helper()
+	config, err := ProfileDefaults(ProfileLocal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+// newTestBot builds a Bot without a chain client for exercising decision,
+// alerting and signing logic. State lives in store, a fresh MemoryStore when
+// nil, and alerts are recorded by the returned sink.
+func newTestBot(t *testing.T, config *Config, store Store) (*Bot, *recordingSink) {
+	t.Helper()
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	sink := &recordingSink{}
+	audits, err := OpenAuditLog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	decisions, err := OpenDecisionLog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	t.Cleanup(bgCancel)
+
+	bot := &Bot{
+		privateKey: privateKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		chainID:    big.NewInt(config.ChainID),
+		logger:     logger,
+		httpClient: &http.Client{},
+		cron:       cron.New(),
+		cronJobs:   make(map[string]*cronJob),
+
+		pendingEmergencies: make(map[common.Hash]PendingEmergency),
+		createdAt:          time.Now(),
+		bgCtx:              bgCtx,
+		bgCancel:           bgCancel,
+		metrics:            NewMetrics(),
+		txSlots:            make(chan struct{}, 1),
+		mlSlots:            make(chan struct{}, 4),
+		mlQueue:            make(chan struct{}, 64),
+		riskActions:        make(map[string]RiskAction),
+		noSubAccounts:      make(map[common.Address]bool),
+		cooldowns:          make(map[cooldownKey]time.Time),
+		kycCache:           make(map[string]kycCacheEntry),
+		flaggedInvoices:    make(map[invoiceKey]bool),
+		clockSkewed:        make(map[string]bool),
+		pendingApprovals:   make(map[approvalKey]pendingApproval),
+		warnSamples:        make(map[string]*warnSample),
+		alertsSent:         make(map[string]time.Time),
+		revokedRoles:       make(map[common.Address]string),
+		roleChecks:         make(map[common.Address]roleCheck),
+
+		pausedMonitors: make(map[string]bool),
+		halt:           make(chan error, 1),
+
+		strategyDecimals: make(map[common.Address]strategyTokens),
+		tokenDecimals:    make(map[common.Address]int),
+
+		alerter: &Alerter{
+			sinks:   []AlertSink{sink},
+			routes:  &alertRoutes{minSeverity: config.AlertRouting},
+			logger:  logger,
+			pending: new(sync.WaitGroup),
+		},
+		audits:           audits,
+		decisions:        decisions,
+		store:            store,
+		gasSpent:         new(big.Int),
+		gasSpentByAction: make(map[string]*big.Int),
+		abis:             contractABIs{strategy: strategyABI, invoiceToken: invoiceTokenABI},
+	}
+	bot.config.Store(config)
+	bot.registerDefaultRiskActions()
+	t.Cleanup(bot.alerter.Wait)
+	return bot, sink
+}
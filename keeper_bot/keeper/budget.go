@@ -0,0 +1,115 @@
+package keeper
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// budgetWindow is the rolling window the daily transaction budget covers
+const budgetWindow = 24 * time.Hour
+
+// ErrDailyBudgetExceeded is returned when a send would exceed the daily transaction budget
+var ErrDailyBudgetExceeded = errors.New("daily transaction budget exceeded")
+
+// sentTx is a transaction counted against the daily budget
+type sentTx struct {
+	At     time.Time `json:"at"`
+	FeeCap *big.Int  `json:"fee_cap_wei"` // gas limit * gas price, an upper bound on the fee
+}
+
+// txBudget tracks transactions sent in the rolling budget window. Emergency
+// deleverages are tracked separately so safety actions never compete with
+// routine spending.
+type txBudget struct {
+	Routine   []sentTx `json:"routine"`
+	Emergency []sentTx `json:"emergency"`
+}
+
+// isEmergencyAction reports whether an action is exempt from the routine
+// budget. An approval sent ahead of an emergency action is part of it.
+func isEmergencyAction(action string) bool {
+	return strings.TrimSuffix(action, approvalSuffix) == "emergency_deleverage"
+}
+
+// checkBudget returns ErrDailyBudgetExceeded if sending a transaction for
+// action with the given fee cap would exceed its cap. Routine actions are
+// limited by MaxDailyTx and MaxDailyGasWei; emergency actions only by
+// MaxDailyEmergencyTx (0 exempts them entirely). A zero or nil limit disables
+// that cap.
+func (b *Bot) checkBudget(action string, feeCap *big.Int) error {
+	config := b.cfg()
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.pruneBudget(time.Now())
+
+	if isEmergencyAction(action) {
+		if limit := config.MaxDailyEmergencyTx; limit > 0 && len(b.budget.Emergency) >= limit {
+			return ErrDailyBudgetExceeded
+		}
+		return nil
+	}
+
+	if limit := config.MaxDailyTx; limit > 0 && len(b.budget.Routine) >= limit {
+		return ErrDailyBudgetExceeded
+	}
+
+	if limit := config.MaxDailyGasWei; limit != nil && limit.Sign() > 0 {
+		spent := new(big.Int).Set(feeCap)
+		for _, sent := range b.budget.Routine {
+			spent.Add(spent, sent.FeeCap)
+		}
+		if spent.Cmp(limit) > 0 {
+			return ErrDailyBudgetExceeded
+		}
+	}
+	return nil
+}
+
+// txFeeCap is a transaction's gas limit times its gas price, an upper bound
+// on the fee it can cost
+func txFeeCap(tx *types.Transaction) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice())
+}
+
+// recordBudget counts a broadcast transaction against its budget
+func (b *Bot) recordBudget(action string, tx *types.Transaction) {
+	sent := sentTx{
+		At:     time.Now(),
+		FeeCap: txFeeCap(tx),
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if isEmergencyAction(action) {
+		b.budget.Emergency = append(b.budget.Emergency, sent)
+	} else {
+		b.budget.Routine = append(b.budget.Routine, sent)
+	}
+
+	// Persist so a restart cannot reset the circuit breaker
+	if err := Save(b.store, keyTxBudget, b.budget); err != nil {
+		b.logger.WithError(err).Warn("Failed to persist transaction budget")
+	}
+}
+
+// pruneBudget drops transactions older than the budget window; caller holds mutex
+func (b *Bot) pruneBudget(now time.Time) {
+	cutoff := now.Add(-budgetWindow)
+	b.budget.Routine = pruneSent(b.budget.Routine, cutoff)
+	b.budget.Emergency = pruneSent(b.budget.Emergency, cutoff)
+}
+
+// pruneSent removes entries sent before cutoff from a time-ordered slice
+func pruneSent(sent []sentTx, cutoff time.Time) []sentTx {
+	i := 0
+	for i < len(sent) && sent[i].At.Before(cutoff) {
+		i++
+	}
+	return sent[i:]
+}
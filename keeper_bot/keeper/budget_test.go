@@ -0,0 +1,36 @@
+package keeper
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestCheckBudgetIncludesCandidateFee(t *testing.T) {
+	config := testConfig(t)
+	config.MaxDailyTx = 0
+	config.MaxDailyGasWei = big.NewInt(100)
+	bot, _ := newTestBot(t, config, nil)
+	bot.budget.Routine = []sentTx{{At: time.Now(), FeeCap: big.NewInt(60)}}
+
+	tests := []struct {
+		name   string
+		action string
+		feeCap int64
+		want   error
+	}{
+		{"fits under cap", "rebalance", 30, nil},
+		{"reaches cap exactly", "rebalance", 40, nil},
+		{"overshoots cap", "rebalance", 41, ErrDailyBudgetExceeded},
+		{"emergency exempt", "emergency_deleverage", 1000, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := bot.checkBudget(tt.action, big.NewInt(tt.feeCap))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("checkBudget(%s, %d) = %v, want %v", tt.action, tt.feeCap, err, tt.want)
+			}
+		})
+	}
+}
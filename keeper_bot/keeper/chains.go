@@ -0,0 +1,266 @@
+package keeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ChainConfig is one chain of a multi-chain keeper, read from ChainsPath.
+// Settings not listed here are shared by every chain.
+type ChainConfig struct {
+	// Name labels the chain's logs, metrics and alerts and suffixes its
+	// state, audit and decision log files
+	Name                   string   `json:"name"`
+	RPC                    string   `json:"rpc"`
+	ChainID                int64    `json:"chain_id"`
+	LeveragedStrategyAddrs []string `json:"leveraged_strategy_addrs"`
+	InvoiceTokenAddrs      []string `json:"invoice_token_addrs"`
+	KYCVerifierAddr        string   `json:"kyc_verifier_addr"`
+	// Optional signer for this chain; empty uses the shared signer
+	KeystorePath         string `json:"keystore_path"`
+	KeystorePasswordFile string `json:"keystore_password_file"`
+}
+
+// loadChains reads the chain list from a JSON array file; an empty path
+// means a single-chain keeper
+func loadChains(path string) ([]ChainConfig, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chains: %w", err)
+	}
+	var chains []ChainConfig
+	if err := json.Unmarshal(data, &chains); err != nil {
+		return nil, fmt.Errorf("malformed chains file %s: %w", path, err)
+	}
+	if len(chains) == 0 {
+		return nil, fmt.Errorf("chains file %s lists no chains", path)
+	}
+
+	seen := make(map[string]bool, len(chains))
+	for i, chain := range chains {
+		switch {
+		case chain.Name == "":
+			return nil, fmt.Errorf("chain %d: name is required", i)
+		case seen[chain.Name]:
+			return nil, fmt.Errorf("chain %s: duplicate name", chain.Name)
+		case chain.RPC == "" || chain.ChainID == 0:
+			return nil, fmt.Errorf("chain %s: rpc and chain_id are required", chain.Name)
+		}
+		seen[chain.Name] = true
+
+		errs := normalizeAddresses("chain "+chain.Name+" leveraged_strategy_addrs", chain.LeveragedStrategyAddrs)
+		errs = append(errs, normalizeAddresses("chain "+chain.Name+" invoice_token_addrs", chain.InvoiceTokenAddrs)...)
+		errs = append(errs, normalizeAddress("chain "+chain.Name+" kyc_verifier_addr", &chains[i].KYCVerifierAddr))
+		if err := errors.Join(errs...); err != nil {
+			return nil, err
+		}
+	}
+	return chains, nil
+}
+
+// forChain derives the config of one chain from the shared config
+func (c *Config) forChain(chain ChainConfig) *Config {
+	config := *c
+	config.ChainName = chain.Name
+	config.MantleRPC = chain.RPC
+	config.ChainID = chain.ChainID
+	config.LeveragedStrategyAddrs = chain.LeveragedStrategyAddrs
+	config.InvoiceTokenAddrs = chain.InvoiceTokenAddrs
+	config.KYCVerifierAddr = chain.KYCVerifierAddr
+	if chain.KeystorePath != "" {
+		config.PrivateKey = ""
+		config.KeystorePath = chain.KeystorePath
+		config.KeystorePassword = ""
+		config.KeystorePasswordFile = chain.KeystorePasswordFile
+	}
+
+	// Chains must not share state or interleave their audit records
+	config.StatePath = chainPath(c.StatePath, chain.Name)
+	config.AuditLogPath = chainPath(c.AuditLogPath, chain.Name)
+	config.DecisionLogPath = chainPath(c.DecisionLogPath, chain.Name)
+	return &config
+}
+
+// chainPath inserts a chain name before a file path's extension, e.g.
+// state.json becomes state.mantle.json. An empty path or chain leaves the
+// path unchanged.
+func chainPath(path, chain string) string {
+	if path == "" || chain == "" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + chain + ext
+}
+
+// chainHook labels every log entry with the chain it concerns
+type chainHook string
+
+// Levels implements logrus.Hook
+func (h chainHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook
+func (h chainHook) Fire(entry *logrus.Entry) error {
+	entry.Data["chain"] = string(h)
+	return nil
+}
+
+// Fleet runs one bot per configured chain, each with its own chain client,
+// signer, state and monitor schedule, sharing the ML client, metrics and
+// alerting. Without ChainsPath it holds a single unlabeled bot.
+type Fleet struct {
+	bots   []*Bot
+	statsd *StatsDSink
+}
+
+// NewFleet creates the bots of every chain in config
+func NewFleet(config *Config) (*Fleet, error) {
+	chains, err := loadChains(config.ChainsPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(chains) == 0 {
+		bot, err := New(config)
+		if err != nil {
+			return nil, err
+		}
+		return &Fleet{bots: []*Bot{bot}}, nil
+	}
+
+	shared, err := newSharedResources(config)
+	if err != nil {
+		return nil, err
+	}
+	fleet := &Fleet{statsd: shared.statsd}
+	for _, chain := range chains {
+		bot, err := newBot(config.forChain(chain), shared)
+		if err != nil {
+			fleet.Close()
+			return nil, fmt.Errorf("chain %s: %w", chain.Name, err)
+		}
+		fleet.bots = append(fleet.bots, bot)
+	}
+	return fleet, nil
+}
+
+// Bots returns the fleet's bots in configuration order
+func (f *Fleet) Bots() []*Bot {
+	return f.bots
+}
+
+// Bot returns the bot of the named chain, or the first bot for an empty name
+func (f *Fleet) Bot(chain string) (*Bot, bool) {
+	if chain == "" {
+		return f.bots[0], true
+	}
+	for _, bot := range f.bots {
+		if bot.Chain() == chain {
+			return bot, true
+		}
+	}
+	return nil, false
+}
+
+// Start runs every bot's scheduler until ctx is cancelled, returning the
+// joined errors of bots that stopped for another reason. A bot that stops
+// on its own, e.g. failing RequireHealthyStart or reaching
+// MaxConsecutiveFailures, stops the whole fleet.
+func (f *Fleet) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(f.bots))
+	var wg sync.WaitGroup
+	for i, bot := range f.bots {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := bot.Start(runCtx)
+			if !errors.Is(err, context.Canceled) {
+				errs[i] = f.label(bot, err)
+			}
+			cancel()
+		}()
+	}
+	wg.Wait()
+	f.closeShared()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return errors.Join(errs...)
+}
+
+// RunOnce runs every bot's monitors once, chain after chain
+func (f *Fleet) RunOnce(ctx context.Context) error {
+	var errs []error
+	for _, bot := range f.bots {
+		if err := bot.RunOnce(ctx); err != nil {
+			errs = append(errs, f.label(bot, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Verify runs every bot's self-test
+func (f *Fleet) Verify(ctx context.Context) error {
+	var errs []error
+	for _, bot := range f.bots {
+		if err := bot.Verify(ctx); err != nil {
+			errs = append(errs, f.label(bot, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WriteReports writes each bot's report to path, suffixed by chain when the
+// fleet runs several
+func (f *Fleet) WriteReports(path string) error {
+	var errs []error
+	for _, bot := range f.bots {
+		if err := WriteReport(chainPath(path, bot.Chain()), bot.Report()); err != nil {
+			errs = append(errs, f.label(bot, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops every bot and releases the shared resources
+func (f *Fleet) Close() {
+	for _, bot := range f.bots {
+		bot.Close()
+	}
+	f.closeShared()
+}
+
+// closeShared closes the shared StatsD sink once every bot has stopped
+func (f *Fleet) closeShared() {
+	if f.statsd != nil {
+		f.statsd.Close()
+		f.statsd = nil
+	}
+}
+
+// label prefixes a bot's error with its chain name when it has one
+func (f *Fleet) label(bot *Bot, err error) error {
+	if err == nil || bot.Chain() == "" {
+		return err
+	}
+	return fmt.Errorf("chain %s: %w", bot.Chain(), err)
+}
+
+// Chain returns the name of the bot's chain, empty for a single-chain keeper
+func (b *Bot) Chain() string {
+	return b.cfg().ChainName
+}
@@ -4,98 +4,381 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/sirupsen/logrus"
 )
 
-// callMLAPI makes HTTP calls to the ML engine
-func (b *Bot) callMLAPI(endpoint string, data interface{}) ([]byte, error) {
-	jsonData, err := json.Marshal(data)
+// callMLAPI validates and sends a request to the ML engine, retrying
+// transient failures. endpoint is relative to Config.MLAPIBasePath, e.g.
+// "leverage-health".
+func (b *Bot) callMLAPI(ctx context.Context, endpoint string, request MLRequest) ([]byte, error) {
+	return b.callMLAPIAt(ctx, b.mlBaseURL(endpoint), endpoint, request)
+}
+
+// mlRoutedEndpoints are the ML endpoints MLEndpoints may route
+var mlRoutedEndpoints = []string{"leverage-health", "kyc-risk-assessment", "invoice-nav-prediction", "invoice-default-prediction"}
+
+// validateMLEndpoints checks every MLEndpoints route names a known endpoint
+// and an absolute URL
+func validateMLEndpoints(routes map[string]string) error {
+	for endpoint, baseURL := range routes {
+		if !slices.Contains(mlRoutedEndpoints, endpoint) {
+			return fmt.Errorf("ML endpoint route for unknown endpoint %q (want one of %s)", endpoint, strings.Join(mlRoutedEndpoints, ", "))
+		}
+		if parsed, err := url.Parse(baseURL); err != nil || !parsed.IsAbs() {
+			return fmt.Errorf("invalid ML endpoint URL %q for %s", baseURL, endpoint)
+		}
+	}
+	return nil
+}
+
+// mlBaseURL returns the engine serving endpoint: its MLEndpoints route,
+// else MLAPIEndpoint
+func (b *Bot) mlBaseURL(endpoint string) string {
+	config := b.cfg()
+	if baseURL, ok := config.MLEndpoints[endpoint]; ok {
+		return baseURL
+	}
+	return config.MLAPIEndpoint
+}
+
+// mlBaseURLs lists every distinct engine the monitors call, MLAPIEndpoint
+// first
+func (b *Bot) mlBaseURLs() []string {
+	baseURLs := []string{b.cfg().MLAPIEndpoint}
+	for _, endpoint := range mlRoutedEndpoints {
+		if baseURL := b.mlBaseURL(endpoint); !slices.Contains(baseURLs, baseURL) {
+			baseURLs = append(baseURLs, baseURL)
+		}
+	}
+	return baseURLs
+}
+
+// callMLAPIAt is callMLAPI against the ML engine at baseURL
+func (b *Bot) callMLAPIAt(ctx context.Context, baseURL, endpoint string, request MLRequest) ([]byte, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(request)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := b.httpClient.Post(
-		b.config.MLAPIEndpoint+endpoint,
-		"application/json",
-		bytes.NewBuffer(jsonData),
-	)
+	// Every monitor shares MLMaxConcurrency slots so bursts such as a KYC
+	// backlog cannot overwhelm the engine
+	release, err := b.acquireMLSlot(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	response, err := retryValue(ctx, b, "ml_"+endpoint, func(ctx context.Context) ([]byte, error) {
+		return b.postMLAPI(ctx, baseURL, endpoint, jsonData)
+	})
+	entry := b.logger.WithFields(logrus.Fields{
+		"endpoint": endpoint,
+		"ml_url":   redactURL(baseURL),
+	})
+	if err != nil {
+		entry.WithError(err).Debug("ML API call failed")
+		return nil, err
+	}
+	entry.Debug("ML API call served")
+	return response, nil
+}
+
+// postMLAPI performs a single ML engine request.
+//
+// Each attempt is bounded by MLRequestTimeout through a per-request context
+// derived from ctx, so the caller's deadline always wins: every attempt gets
+// min(MLRequestTimeout, time left) rather than a fresh full timeout.
+func (b *Bot) postMLAPI(ctx context.Context, baseURL, endpoint string, jsonData []byte) ([]byte, error) {
+	config := b.cfg()
+	apiURL, err := url.JoinPath(baseURL, config.MLAPIBasePath, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ML API URL: %w", err)
+	}
+
+	if config.DebugMLPayloads {
+		b.logger.WithFields(logrus.Fields{
+			"endpoint": endpoint,
+			"request":  redactPayload(jsonData),
+		}).Debug("ML API request")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.MLRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	b.setMLHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+	if key := idempotencyKeyFrom(ctx); key != "" {
+		req.Header.Set(idempotencyHeader, key)
+	}
+
+	resp, err := b.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, &httpStatusError{StatusCode: resp.StatusCode}
 	}
 
+	body, err := readLimited(resp.Body, config.MaxResponseBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s response: %w", endpoint, err)
+	}
 	var result json.RawMessage
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, err
 	}
 
+	if config.DebugMLPayloads {
+		b.logger.WithFields(logrus.Fields{
+			"endpoint": endpoint,
+			"response": redactPayload(result),
+		}).Debug("ML API response")
+	}
+
 	return result, nil
 }
 
-// getTransactOpts creates transaction options
-func (b *Bot) getTransactOpts(ctx context.Context) (*bind.TransactOpts, error) {
-	nonce, err := b.client.PendingNonceAt(ctx, b.address)
+// ErrResponseTooLarge is returned when an ML response exceeds MaxResponseBytes
+var ErrResponseTooLarge = errors.New("response exceeds size limit")
+
+// readLimited reads r to EOF, failing with ErrResponseTooLarge past limit
+// bytes so a misbehaving server cannot exhaust memory (0 disables the limit)
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
 	if err != nil {
 		return nil, err
 	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w of %d bytes", ErrResponseTooLarge, limit)
+	}
+	return data, nil
+}
+
+// getTransactOpts creates transaction options, pricing gas by urgency
+func (b *Bot) getTransactOpts(ctx context.Context, urgency TxUrgency) (*bind.TransactOpts, error) {
+	privateKey, address := b.signer()
 
-	gasPrice, err := b.client.SuggestGasPrice(ctx)
+	nonce, err := retryValue(ctx, b, "pending_nonce", func(ctx context.Context) (uint64, error) {
+		return b.txClient().PendingNonceAt(ctx, address)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(b.privateKey, b.chainID)
+	gasPrice, err := retryValue(ctx, b, "suggest_gas_price", func(ctx context.Context) (*big.Int, error) {
+		return b.txClient().SuggestGasPrice(ctx)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	auth.Nonce = big.NewInt(int64(nonce))
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, b.chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	auth.Nonce = new(big.Int).SetUint64(b.nextNonce(nonce))
 	auth.Value = big.NewInt(0)
-	auth.GasLimit = b.config.GasLimit
-	auth.GasPrice = gasPrice
+	auth.GasLimit = b.cfg().GasLimit
+	auth.GasPrice = b.gasPrice(gasPrice, urgency)
 
 	return auth, nil
 }
 
-// HealthCheck performs system health check
+// HealthCheck performs system health check. The sub-checks run
+// concurrently under one HealthCheckTimeout deadline, so the report is a
+// single point-in-time snapshot; follow-ups such as a balance refill run
+// after it under ctx.
 func (b *Bot) HealthCheck(ctx context.Context) error {
+	config := b.cfg()
+	report := &HealthReport{CheckedAt: time.Now()}
+
+	probeCtx, cancel := context.WithTimeout(ctx, config.HealthCheckTimeout)
+	defer cancel()
+
+	var (
+		wg          sync.WaitGroup
+		mlErr       error
+		latestBlock uint64
+		chainErr    error
+		clockErr    error
+		balance     *big.Int
+		balanceErr  error
+		roleErrs    []error
+	)
+	probes := []func(){
+		func() { mlErr = b.probeMLHealth(probeCtx) },
+		func() {
+			latestBlock, chainErr = retryValue(probeCtx, b, "block_number", func(ctx context.Context) (uint64, error) {
+				return b.eth().BlockNumber(ctx)
+			})
+		},
+		func() { clockErr = b.observeChainClock(probeCtx) },
+		func() {
+			balance, balanceErr = retryValue(probeCtx, b, "balance", func(ctx context.Context) (*big.Int, error) {
+				return b.eth().BalanceAt(ctx, b.keeperAddress(), nil)
+			})
+		},
+		// Check the keeper is authorized on every contract it acts on
+		func() { roleErrs = b.refreshRoles(probeCtx, false) },
+	}
+	for _, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probe()
+		}()
+	}
+	wg.Wait()
+
 	// Check ML engine health
-	resp, err := b.httpClient.Get(b.config.MLAPIEndpoint + "/health")
-	if err != nil {
-		b.logger.WithError(err).Error("ML engine health check failed")
+	if mlErr != nil {
+		b.logger.WithError(mlErr).Error("ML engine health check failed")
 	} else {
-		resp.Body.Close()
 		b.logger.Info("ML engine health check: OK")
 	}
+	report.add("ml_engine", mlErr)
 
 	// Check blockchain connection
-	latestBlock, err := b.client.BlockNumber(ctx)
-	if err != nil {
-		b.logger.WithError(err).Error("Blockchain connection failed")
+	if chainErr != nil {
+		b.logger.WithError(chainErr).Error("Blockchain connection failed")
 	} else {
 		b.logger.WithField("block", latestBlock).Info("Blockchain connection: OK")
 	}
+	report.add("chain", chainErr)
+
+	if clockErr != nil {
+		b.logger.WithError(clockErr).Warn("Failed to read latest block timestamp")
+	}
 
 	// Check account balance
-	balance, err := b.client.BalanceAt(ctx, b.address, nil)
-	if err != nil {
-		b.logger.WithError(err).Error("Failed to get account balance")
+	var lowBalance bool
+	if balanceErr != nil {
+		b.logger.WithError(balanceErr).Error("Failed to get account balance")
 	} else {
 		ethBalance := new(big.Float).Quo(new(big.Float).SetInt(balance), big.NewFloat(1e18))
 		b.logger.WithField("balance", ethBalance).Info("Account balance checked")
 
-		if balance.Cmp(big.NewInt(1e17)) < 0 { // Less than 0.1 ETH
-			b.logger.Warn("LOW KEEPER ACCOUNT BALANCE - REFILL NEEDED")
+		if lowBalance = balance.Cmp(config.MinKeeperBalanceWei) < 0; lowBalance {
+			b.warnSampled("low_balance", b.logger.WithField("balance", ethBalance), "LOW KEEPER ACCOUNT BALANCE - REFILL NEEDED")
 		}
 	}
+	report.add("balance", balanceErr)
+
+	required := b.requiredRoles()
+	for i, err := range roleErrs {
+		report.add("role_"+required[i].name, err)
+	}
+
+	b.mutex.Lock()
+	b.lastHealth = report
+	b.mutex.Unlock()
+	b.updateDegraded()
+
+	if mlErr == nil {
+		b.checkModelInfo(ctx)
+	}
+	if lowBalance {
+		b.handleLowBalance(ctx, balance)
+	}
 
+	if !report.Healthy() {
+		return fmt.Errorf("unhealthy components: %s", strings.Join(report.Failed(), ", "))
+	}
 	return nil
 }
+
+// probeMLHealth pings the /health endpoint of every ML engine the monitors
+// call, each bounded by HealthCheckTimeout
+func (b *Bot) probeMLHealth(ctx context.Context) error {
+	baseURLs := b.mlBaseURLs()
+	if len(baseURLs) == 1 {
+		return b.probeMLHealthAt(ctx, baseURLs[0])
+	}
+	var errs []error
+	for _, baseURL := range baseURLs {
+		if err := b.probeMLHealthAt(ctx, baseURL); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", redactURL(baseURL), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// probeMLHealthAt pings the /health endpoint of the ML engine at baseURL
+func (b *Bot) probeMLHealthAt(ctx context.Context, baseURL string) error {
+	healthURL, err := url.JoinPath(baseURL, "health")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, b.cfg().HealthCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return err
+	}
+	b.setMLHeaders(req)
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ML engine returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// newMLHTTPClient builds the ML engine client, routed through MLProxyURL when set
+func newMLHTTPClient(config *Config) (*http.Client, error) {
+	if config.MLProxyURL == "" {
+		return &http.Client{}, nil
+	}
+	proxyURL, err := url.Parse(config.MLProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ML proxy URL: %w", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	return &http.Client{Transport: transport}, nil
+}
+
+// setMLHeaders adds the configured gateway headers and the schema version
+// headers to an ML request. Callers set Content-Type and the idempotency key
+// afterwards so these can never override them.
+func (b *Bot) setMLHeaders(req *http.Request) {
+	for key, value := range b.cfg().MLHeaders {
+		req.Header.Set(key, value)
+	}
+	major, _, _ := strings.Cut(MLSchemaVersion, ".")
+	req.Header.Set(acceptVersionHeader, major)
+	req.Header.Set(clientVersionHeader, MLSchemaVersion)
+}
@@ -0,0 +1,106 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeMLBot is a test Bot whose ML engine is a fresh fakeMLServer, retrying
+// quickly so fault tests stay fast
+func fakeMLBot(t *testing.T, responses map[string]interface{}) (*Bot, *fakeMLServer, *recordingSink) {
+	t.Helper()
+	ml := newFakeMLServer(t, responses)
+	config := testConfig(t)
+	config.MLAPIEndpoint = ml.URL
+	config.RetryAttempts = 2
+	config.RetryBaseDelay = time.Millisecond
+	config.RetryMaxDelay = time.Millisecond
+	bot, sink := newTestBot(t, config, nil)
+	return bot, ml, sink
+}
+
+// leverageRequest is a valid leverage-health request
+var leverageRequest = LeverageHealthRequest{TotalCollateral: 1000, TotalBorrowed: 500, CurrentHealthFactor: 1.8, AITValue: 900}
+
+func TestCallMLAPIRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		fault        fakeMLFault
+		wantErr      bool
+		wantRequests int
+	}{
+		{"healthy", fakeMLFault{}, false, 1},
+		{"recovers from transient errors", fakeMLFault{Status: http.StatusServiceUnavailable, Times: 2}, false, 3},
+		{"gives up after RetryAttempts", fakeMLFault{Status: http.StatusServiceUnavailable}, true, 3},
+		{"client error is not retried", fakeMLFault{Status: http.StatusBadRequest}, true, 1},
+		{"malformed body is not retried", fakeMLFault{BadJSON: true}, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, ml, _ := fakeMLBot(t, nil)
+			if tt.fault != (fakeMLFault{}) {
+				ml.injectFault(fakeMLLeverageHealth, tt.fault)
+			}
+
+			_, err := bot.callMLAPI(context.Background(), fakeMLLeverageHealth, leverageRequest)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("callMLAPI err = %v, want error %t", err, tt.wantErr)
+			}
+			requests := ml.requestsTo(fakeMLLeverageHealth)
+			if len(requests) != tt.wantRequests {
+				t.Fatalf("ML engine got %d requests, want %d", len(requests), tt.wantRequests)
+			}
+			var sent LeverageHealthRequest
+			if err := requests[0].decode(&sent); err != nil || sent != leverageRequest {
+				t.Fatalf("ML engine got request %+v (%v), want %+v", sent, err, leverageRequest)
+			}
+		})
+	}
+}
+
+func TestCallMLAPITimesOutSlowEngine(t *testing.T) {
+	bot, ml, _ := fakeMLBot(t, nil)
+	config := *bot.cfg()
+	config.MLRequestTimeout = 20 * time.Millisecond
+	config.RetryAttempts = 0
+	bot.config.Store(&config)
+	ml.injectFault(fakeMLLeverageHealth, fakeMLFault{Delay: time.Second})
+
+	_, err := bot.callMLAPI(context.Background(), fakeMLLeverageHealth, leverageRequest)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("callMLAPI err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestStaleMLAssessmentIsNotActionable(t *testing.T) {
+	bot, ml, _ := fakeMLBot(t, nil)
+	position := &StrategyPosition{TotalBorrowed: 100, HealthFactor: 2, LTV: 0.4}
+	ml.setResponse(fakeMLLeverageHealth, fakeMLResponse(func(*http.Request) interface{} {
+		return map[string]interface{}{
+			"composite_risk_score": 0.9,
+			"risk_level":           "CRITICAL",
+			"action_required":      true,
+			"recommendations":      []string{RecEmergencyDeleverage},
+			"confidence":           0.95,
+			"timestamp":            time.Now().Add(-time.Hour).Unix(),
+		}
+	}))
+
+	response, err := bot.assessLeverage(context.Background(), leverageRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decision, err := bot.decideRiskAction(time.Now(), position, response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(decision.notActionable, ErrStaleAssessment) {
+		t.Fatalf("notActionable = %v, want %v", decision.notActionable, ErrStaleAssessment)
+	}
+	if decision.ok {
+		t.Fatalf("stale assessment acted on with %s", decision.chosen)
+	}
+}
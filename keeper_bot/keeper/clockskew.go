@@ -0,0 +1,75 @@
+package keeper
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+// Clock skew sources, each measured against the local clock
+const (
+	skewSourceML    = "ml"
+	skewSourceChain = "chain"
+)
+
+// observeMLClock records the skew between an ML response timestamp and the
+// local clock; responses without a timestamp are ignored
+func (b *Bot) observeMLClock(timestamp int64) {
+	if timestamp == 0 {
+		return
+	}
+	b.observeClockSkew(skewSourceML, time.Until(time.Unix(timestamp, 0)))
+}
+
+// observeChainClock records the skew between the latest block timestamp and
+// the local clock
+func (b *Bot) observeChainClock(ctx context.Context) error {
+	header, err := retryValue(ctx, b, "header", func(ctx context.Context) (*types.Header, error) {
+		return b.eth().HeaderByNumber(ctx, nil)
+	})
+	if err != nil {
+		return err
+	}
+	b.observeClockSkew(skewSourceChain, time.Until(time.Unix(int64(header.Time), 0)))
+	return nil
+}
+
+// observeClockSkew exports a source's skew and warns when it crosses
+// MaxClockSkew. The alert fires once when a source becomes skewed and is
+// re-armed when it recovers.
+func (b *Bot) observeClockSkew(source string, skew time.Duration) {
+	b.metrics.SetGauge(metricClockSkew, skew.Seconds(), "source", source)
+
+	limit := b.cfg().MaxClockSkew
+	skewed := limit > 0 && math.Abs(skew.Seconds()) > limit.Seconds()
+
+	b.mutex.Lock()
+	wasSkewed := b.clockSkewed[source]
+	b.clockSkewed[source] = skewed
+	b.mutex.Unlock()
+	if skewed != wasSkewed {
+		b.updateDegraded()
+	}
+
+	fields := logrus.Fields{
+		"source":   source,
+		"skew":     skew.Round(time.Second).String(),
+		"max_skew": limit.String(),
+	}
+	switch {
+	case skewed && !wasSkewed:
+		b.sendDeduped("clock_skew:"+source, Alert{
+			Severity: AlertWarning,
+			Title:    "Clock skew exceeds limit",
+			Fields:   fields,
+		})
+	case skewed:
+		b.logger.WithFields(fields).Warn("Clock skew exceeds limit")
+	case wasSkewed:
+		b.logger.WithFields(fields).Info("Clock skew back within limit")
+		b.clearDeduped("clock_skew:" + source)
+	}
+}
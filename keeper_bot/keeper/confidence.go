@@ -0,0 +1,38 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Reasons an ML assessment is not acted on
+var (
+	ErrStaleAssessment = errors.New("ML assessment is stale")
+	ErrLowConfidence   = errors.New("ML assessment confidence below floor")
+)
+
+// checkAssessment decides whether an ML assessment is fit to act on.
+//
+// Staleness is checked first: an assessment older than maxAge is rejected
+// with ErrStaleAssessment whatever its confidence. Only a fresh assessment
+// is then held to the confidence floor, so a fresh-but-low-confidence
+// response always yields ErrLowConfidence and never a stale error. A zero
+// timestamp (not reported) or maxAge of zero skips the staleness check.
+//
+// Callers pass the floor for the action at stake, so one response can be
+// fit for a deleverage (MinDeleverageConfidence) but not a NAV write
+// (MinNAVConfidence). Either rejection only discards the ML assessment:
+// NAV updates are skipped, while leverage decisions still act on positions
+// breaching MinHealthFactor or MaxLTV on-chain (see overrideNotActionable).
+func checkAssessment(now time.Time, timestamp int64, confidence, floor float64, maxAge time.Duration) error {
+	if maxAge > 0 && timestamp > 0 {
+		if age := now.Sub(time.Unix(timestamp, 0)); age > maxAge {
+			return fmt.Errorf("%w: %s old, max %s", ErrStaleAssessment, age.Round(time.Second), maxAge)
+		}
+	}
+	if confidence < floor {
+		return fmt.Errorf("%w: %.2f < %.2f", ErrLowConfidence, confidence, floor)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckAssessment(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	fresh := now.Add(-time.Minute).Unix()
+	stale := now.Add(-time.Hour).Unix()
+	const maxAge = 10 * time.Minute
+	const navFloor, deleverageFloor = 0.8, 0.5
+
+	tests := []struct {
+		name       string
+		timestamp  int64
+		confidence float64
+		floor      float64
+		maxAge     time.Duration
+		want       error
+	}{
+		{"fresh and confident", fresh, 0.9, navFloor, maxAge, nil},
+		{"fresh but low confidence", fresh, 0.3, navFloor, maxAge, ErrLowConfidence},
+		{"stale and confident", stale, 0.9, navFloor, maxAge, ErrStaleAssessment},
+		{"stale takes precedence over low confidence", stale, 0.3, navFloor, maxAge, ErrStaleAssessment},
+		{"no timestamp skips staleness", 0, 0.3, navFloor, maxAge, ErrLowConfidence},
+		{"zero max age skips staleness", stale, 0.9, navFloor, 0, nil},
+		{"confidence at the floor passes", fresh, navFloor, navFloor, maxAge, nil},
+		// One response, two floors: unfit for a NAV write, fit to deleverage
+		{"below NAV floor", fresh, 0.6, navFloor, maxAge, ErrLowConfidence},
+		{"above deleverage floor", fresh, 0.6, deleverageFloor, maxAge, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAssessment(now, tt.timestamp, tt.confidence, tt.floor, tt.maxAge)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("checkAssessment = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,648 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Deployment profiles selectable via KEEPER_PROFILE
+const (
+	ProfileMainnet = "mainnet"
+	ProfileTestnet = "testnet"
+	ProfileLocal   = "local"
+)
+
+// ProfileDefaults returns the baseline configuration for a deployment profile.
+// Environment variables applied by LoadConfig override these values.
+func ProfileDefaults(profile string) (*Config, error) {
+	config := &Config{
+		Profile:       profile,
+		MLAPIEndpoint: "http://localhost:5000",
+		MLAPIBasePath: "/api/v1",
+
+		HealthCheckTimeout: 5 * time.Second,
+		MLRequestTimeout:   30 * time.Second,
+		MaxModelAge:        30 * 24 * time.Hour,
+		MLMaxConcurrency:   4,
+		MLQueueDepth:       64,
+		MaxResponseBytes:   1 << 20, // 1 MiB
+		KYCConcurrency:     4,
+		RetryAttempts:      3,
+		RetryBaseDelay:     500 * time.Millisecond,
+		RetryMaxDelay:      5 * time.Second,
+		RetryBudget:        time.Minute,
+
+		KYCAllowedReassessInterval: 24 * time.Hour,
+		KYCRuleViolationSeverity:   AlertCritical,
+
+		LogLevel:           "info",
+		ScorePrecision:     4,
+		NAVPrecision:       navDecimals,
+		WarnSampleInterval: time.Hour,
+
+		MaxGasPrice: big.NewInt(5000000000), // 5 Gwei
+		GasLimit:    500000,
+
+		RoutineGasMultiplier:   1.0,
+		EmergencyGasMultiplier: 1.5,
+		EmergencyMaxGasPrice:   big.NewInt(20000000000), // 20 Gwei
+
+		MaxInFlightTx:      1,
+		TxConfirmTimeout:   5 * time.Minute,
+		TxConfirmations:    1,
+		DeleverageFraction: 0.25,
+		SimulateBeforeSend: true,
+		RecoverPendingTx:   true,
+		MaxDailyTx:         50,
+
+		CriticalRisk:    0.8,
+		HighRisk:        0.6,
+		MaxLTV:          0.65,
+		MinHealthFactor: 1.3,
+		MinLiquidity:    0.3,
+
+		ThresholdOverride: true,
+
+		RecommendationPolicy: RecommendationPolicyMostAggressive,
+		UnknownRiskLevelAs:   RiskLevelHigh,
+
+		AlwaysActRecommendations: []string{RecEmergencyDeleverage},
+
+		// Emergency deleverage is never delayed by a cooldown
+		ReduceLeverageCooldown:    30 * time.Minute,
+		PauseNewPositionsCooldown: 30 * time.Minute,
+
+		MinNAVConfidence:        0.7,
+		MinDeleverageConfidence: 0.5,
+		MaxAssessmentAge:        5 * time.Minute,
+		MaxClockSkew:            2 * time.Minute,
+
+		TriggerEvents: DefaultTriggerEvents,
+		EventDebounce: 10 * time.Second,
+
+		ReorgSafetyBlocks:   10,
+		ReorgLookbackBlocks: 64,
+
+		// Act on the freshest position data, but only publish NAV from
+		// state that can no longer be reorged away
+		BlockTag:    BlockTagLatest,
+		NAVBlockTag: BlockTagFinalized,
+
+		Multicall3Addr: defaultMulticall3Addr,
+
+		HealthListenAddr: ":8080",
+		MetricsEnabled:   true,
+
+		// Just under the 30 minute NAV schedule
+		MinNAVUpdateInterval:   25 * time.Minute,
+		ForcedNAVMinConfidence: 0.5,
+		NAVSubmitMode:          NAVSubmitSend,
+
+		InvoiceDefaultPageSize:    100,
+		InvoiceDefaultConcurrency: 4,
+
+		ReadinessMaxAge: 90 * time.Minute,
+
+		// Longer than the slowest (30 minute NAV) schedule
+		MaxCycleAge: 45 * time.Minute,
+
+		// Shorter than the fastest (5 minute) schedule
+		CycleTimeout: 4 * time.Minute,
+
+		LeverageMonitorInterval: 5,
+		NAVUpdateInterval:       30,
+		KYCMonitorInterval:      15,
+		HealthCheckInterval:     60,
+		RoleCheckInterval:       5,
+
+		MinKeeperBalanceWei: big.NewInt(1e17), // 0.1 MNT
+		RefillCooldown:      time.Hour,
+
+		PrivateTxActions: []string{"emergency_deleverage"},
+
+		// Alert dedup, the transaction budget and emergency mode must survive
+		// a restart
+		StateBackend: StateBackendFile,
+		AuditLogPath: "keeper-audit.jsonl",
+		StatePath:    "keeper-state.json",
+
+		// Two leverage cycles of observation before acting
+		StartupGracePeriod: 10 * time.Minute,
+
+		StartupHealthTimeout: 5 * time.Minute,
+
+		DegradedConfidenceMargin:    0.1,
+		DegradedNAVChangeMultiplier: 2,
+		DegradedBlockRoutineTx:      true,
+
+		// Page only for critical alerts
+		AlertRouting: map[string]string{SinkPagerDuty: AlertCritical},
+
+		AlertDedupRetention: 4 * time.Hour,
+
+		FailureAction: FailureActionShutdown,
+	}
+
+	switch profile {
+	case ProfileMainnet:
+		config.MantleRPC = "https://rpc.mantle.xyz"
+		config.ChainID = 5000
+
+	case ProfileTestnet:
+		config.MantleRPC = "https://rpc.sepolia.mantle.xyz"
+		config.ChainID = 5003
+
+	case ProfileLocal:
+		// Anvil/Hardhat fork with relaxed thresholds and a local fake ML server
+		config.MantleRPC = "http://127.0.0.1:8545"
+		config.ChainID = 31337
+		config.LogLevel = "debug"
+		config.CriticalRisk = 0.9
+		config.HighRisk = 0.75
+		config.MaxLTV = 0.8
+		config.MinHealthFactor = 1.1
+		config.MinLiquidity = 0.1
+		config.TxConfirmTimeout = time.Minute
+		config.NAVBlockTag = BlockTagLatest
+		config.StateBackend = StateBackendMemory
+		config.StartupGracePeriod = 0
+
+	default:
+		return nil, fmt.Errorf("unknown profile %q (want %s, %s or %s)", profile, ProfileMainnet, ProfileTestnet, ProfileLocal)
+	}
+
+	return config, nil
+}
+
+// LoadConfig builds a Config from the KEEPER_PROFILE baseline (default
+// mainnet) overridden by any environment variables that are set, or else
+// set in the KEEPER_ENV_FILE file
+func LoadConfig() (*Config, error) {
+	envFile := os.Getenv("KEEPER_ENV_FILE")
+	file, err := readEnvFile(envFile)
+	if err != nil {
+		return nil, err
+	}
+	env := &envReader{file: file}
+
+	config, err := ProfileDefaults(env.str("KEEPER_PROFILE", ProfileMainnet))
+	if err != nil {
+		return nil, err
+	}
+	config.EnvFile = envFile
+
+	config.MantleRPC = env.str("MANTLE_RPC", config.MantleRPC)
+	config.ChainID = env.int64("CHAIN_ID", config.ChainID)
+	config.LeveragedStrategyAddrs = env.list("LEVERAGED_STRATEGY_ADDR", ",", config.LeveragedStrategyAddrs)
+	config.InvoiceTokenAddrs = env.list("INVOICE_TOKEN_ADDR", ",", config.InvoiceTokenAddrs)
+	config.KYCVerifierAddr = env.str("KYC_VERIFIER_ADDR", config.KYCVerifierAddr)
+	config.ChainsPath = env.str("CHAINS_PATH", config.ChainsPath)
+	config.StrategyABIPath = env.str("STRATEGY_ABI_PATH", config.StrategyABIPath)
+	config.InvoiceABIPath = env.str("INVOICE_ABI_PATH", config.InvoiceABIPath)
+	config.KYCABIPath = env.str("KYC_ABI_PATH", config.KYCABIPath)
+	config.PrivateKey = env.str("KEEPER_PRIVATE_KEY", config.PrivateKey)
+	config.KeystorePath = env.str("KEYSTORE_PATH", config.KeystorePath)
+	config.KeystorePassword = env.str("KEYSTORE_PASSWORD", config.KeystorePassword)
+	config.KeystorePasswordFile = env.str("KEYSTORE_PASSWORD_FILE", config.KeystorePasswordFile)
+	config.ExpectedKeeperAddress = env.str("EXPECTED_KEEPER_ADDRESS", config.ExpectedKeeperAddress)
+	config.IncomingPrivateKey = env.str("INCOMING_PRIVATE_KEY", config.IncomingPrivateKey)
+	config.IncomingKeystorePath = env.str("INCOMING_KEYSTORE_PATH", config.IncomingKeystorePath)
+	config.IncomingKeystorePassword = env.str("INCOMING_KEYSTORE_PASSWORD", config.IncomingKeystorePassword)
+	config.IncomingKeystorePasswordFile = env.str("INCOMING_KEYSTORE_PASSWORD_FILE", config.IncomingKeystorePasswordFile)
+	config.AdminToken = env.str("ADMIN_TOKEN", config.AdminToken)
+	config.EnablePprof = env.boolean("ENABLE_PPROF", config.EnablePprof)
+
+	config.MLAPIEndpoint = env.str("ML_API_ENDPOINT", config.MLAPIEndpoint)
+	config.MLEndpoints = env.mapping("ML_ENDPOINTS", config.MLEndpoints)
+	config.MLAPIBasePath = env.str("ML_API_BASE_PATH", config.MLAPIBasePath)
+	config.AllowLocalMLEndpoint = env.boolean("ALLOW_LOCAL_ML_ENDPOINT", config.AllowLocalMLEndpoint)
+	config.HealthCheckTimeout = env.duration("HEALTH_CHECK_TIMEOUT", config.HealthCheckTimeout)
+	config.MLRequestTimeout = env.duration("ML_REQUEST_TIMEOUT", config.MLRequestTimeout)
+	config.MaxModelAge = env.duration("MAX_MODEL_AGE", config.MaxModelAge)
+	config.MLHeaders = env.mapping("ML_HEADERS", config.MLHeaders)
+	config.MLEnsembleEndpoints = env.mapping("ML_ENSEMBLE_ENDPOINTS", config.MLEnsembleEndpoints)
+	config.MLEnsembleWeights = env.floatMapping("ML_ENSEMBLE_WEIGHTS", config.MLEnsembleWeights)
+	config.MLEnsembleQuorum = env.int("ML_ENSEMBLE_QUORUM", config.MLEnsembleQuorum)
+	config.MLProxyURL = env.str("ML_PROXY_URL", config.MLProxyURL)
+	config.MaxResponseBytes = env.int64("ML_MAX_RESPONSE_BYTES", config.MaxResponseBytes)
+	config.MLMaxConcurrency = env.int("ML_MAX_CONCURRENCY", config.MLMaxConcurrency)
+	config.MLQueueDepth = env.int("ML_QUEUE_DEPTH", config.MLQueueDepth)
+	config.KYCConcurrency = env.int("KYC_CONCURRENCY", config.KYCConcurrency)
+	config.KYCAllowedJurisdictions = env.list("KYC_ALLOWED_JURISDICTIONS", ",", config.KYCAllowedJurisdictions)
+	config.KYCBlockedJurisdictions = env.list("KYC_BLOCKED_JURISDICTIONS", ",", config.KYCBlockedJurisdictions)
+	config.KYCAllowedReassessInterval = env.duration("KYC_ALLOWED_REASSESS_INTERVAL", config.KYCAllowedReassessInterval)
+	config.KYCRulesPath = env.str("KYC_RULES_PATH", config.KYCRulesPath)
+	config.KYCRuleViolationSeverity = env.str("KYC_RULE_VIOLATION_SEVERITY", config.KYCRuleViolationSeverity)
+	config.RetryAttempts = env.int("RETRY_ATTEMPTS", config.RetryAttempts)
+	config.RetryBaseDelay = env.duration("RETRY_BASE_DELAY", config.RetryBaseDelay)
+	config.RetryMaxDelay = env.duration("RETRY_MAX_DELAY", config.RetryMaxDelay)
+	config.RetryBudget = env.duration("RETRY_BUDGET", config.RetryBudget)
+	config.DebugMLPayloads = env.boolean("DEBUG_ML_PAYLOADS", config.DebugMLPayloads)
+	config.LogLevel = env.str("LOG_LEVEL", config.LogLevel)
+	config.ScorePrecision = env.int("SCORE_PRECISION", config.ScorePrecision)
+	config.NAVPrecision = env.int("NAV_PRECISION", config.NAVPrecision)
+	config.WarnSampleInterval = env.duration("WARN_SAMPLE_INTERVAL", config.WarnSampleInterval)
+
+	config.MaxInFlightTx = env.int("MAX_IN_FLIGHT_TX", config.MaxInFlightTx)
+	config.TxConfirmTimeout = env.duration("TX_CONFIRM_TIMEOUT", config.TxConfirmTimeout)
+	config.TxConfirmations = uint64(env.int("TX_CONFIRMATIONS", int(config.TxConfirmations)))
+	config.SimulateBeforeSend = env.boolean("SIMULATE_BEFORE_SEND", config.SimulateBeforeSend)
+	config.MaxDeleverageSlippageBps = env.int("MAX_DELEVERAGE_SLIPPAGE_BPS", config.MaxDeleverageSlippageBps)
+	config.DeleverageQuoterAddr = env.str("DELEVERAGE_QUOTER_ADDR", config.DeleverageQuoterAddr)
+	config.DeleverageApprovalSpender = env.str("DELEVERAGE_APPROVAL_SPENDER", config.DeleverageApprovalSpender)
+	config.UseInfiniteApproval = env.boolean("USE_INFINITE_APPROVAL", config.UseInfiniteApproval)
+	config.RecoverPendingTx = env.boolean("RECOVER_PENDING_TX", config.RecoverPendingTx)
+	config.MaxGasPrice = env.bigInt("MAX_GAS_PRICE_WEI", config.MaxGasPrice)
+	config.RoutineGasMultiplier = env.float("ROUTINE_GAS_MULTIPLIER", config.RoutineGasMultiplier)
+	config.EmergencyGasMultiplier = env.float("EMERGENCY_GAS_MULTIPLIER", config.EmergencyGasMultiplier)
+	config.EmergencyMaxGasPrice = env.bigInt("EMERGENCY_MAX_GAS_PRICE_WEI", config.EmergencyMaxGasPrice)
+	config.MaxDailyTx = env.int("MAX_DAILY_TX", config.MaxDailyTx)
+	config.MaxDailyGasWei = env.bigInt("MAX_DAILY_GAS_WEI", config.MaxDailyGasWei)
+	config.MaxDailyEmergencyTx = env.int("MAX_DAILY_EMERGENCY_TX", config.MaxDailyEmergencyTx)
+
+	config.StateBackend = env.str("STATE_BACKEND", config.StateBackend)
+	config.StatePath = env.str("STATE_PATH", config.StatePath)
+	config.AuditLogPath = env.str("AUDIT_LOG_PATH", config.AuditLogPath)
+	config.DryRun = env.boolean("DRY_RUN", config.DryRun)
+	config.DecisionLogPath = env.str("DECISION_LOG_PATH", config.DecisionLogPath)
+	config.StartupGracePeriod = env.duration("STARTUP_GRACE_PERIOD", config.StartupGracePeriod)
+	config.RequireHealthyStart = env.boolean("REQUIRE_HEALTHY_START", config.RequireHealthyStart)
+	config.StartupHealthTimeout = env.duration("STARTUP_HEALTH_TIMEOUT", config.StartupHealthTimeout)
+
+	config.MinKeeperBalanceWei = env.bigInt("MIN_KEEPER_BALANCE_WEI", config.MinKeeperBalanceWei)
+	config.FundingURL = env.str("FUNDING_URL", config.FundingURL)
+	config.FundingContractAddr = env.str("FUNDING_CONTRACT_ADDR", config.FundingContractAddr)
+	config.RefillCooldown = env.duration("REFILL_COOLDOWN", config.RefillCooldown)
+
+	config.PrivateTxRelayURL = env.str("PRIVATE_TX_RELAY_URL", config.PrivateTxRelayURL)
+	config.PrivateTxActions = env.list("PRIVATE_TX_ACTIONS", ",", config.PrivateTxActions)
+
+	config.SlackWebhookURL = env.str("SLACK_WEBHOOK_URL", config.SlackWebhookURL)
+	config.DiscordWebhookURL = env.str("DISCORD_WEBHOOK_URL", config.DiscordWebhookURL)
+	config.TelegramBotToken = env.str("TELEGRAM_BOT_TOKEN", config.TelegramBotToken)
+	config.TelegramChatID = env.str("TELEGRAM_CHAT_ID", config.TelegramChatID)
+	config.PagerDutyRoutingKey = env.str("PAGERDUTY_ROUTING_KEY", config.PagerDutyRoutingKey)
+	config.AlertWebhookURL = env.str("ALERT_WEBHOOK_URL", config.AlertWebhookURL)
+	config.AlertRouting = env.mapping("ALERT_ROUTING", config.AlertRouting)
+	config.AlertDedupRetention = env.duration("ALERT_DEDUP_RETENTION", config.AlertDedupRetention)
+
+	config.CriticalRisk = env.float("CRITICAL_RISK_THRESHOLD", config.CriticalRisk)
+	config.HighRisk = env.float("HIGH_RISK_THRESHOLD", config.HighRisk)
+	config.MaxLTV = env.float("MAX_LTV_THRESHOLD", config.MaxLTV)
+	config.MinHealthFactor = env.float("MIN_HEALTH_FACTOR", config.MinHealthFactor)
+	config.MinLiquidity = env.float("MIN_LIQUIDITY_SCORE", config.MinLiquidity)
+	config.ThresholdOverride = env.boolean("THRESHOLD_OVERRIDE", config.ThresholdOverride)
+	config.RecommendationPolicy = env.str("RECOMMENDATION_POLICY", config.RecommendationPolicy)
+	config.UnknownRiskLevelAs = env.str("UNKNOWN_RISK_LEVEL_AS", config.UnknownRiskLevelAs)
+	config.EnableFallbackPolicy = env.boolean("ENABLE_FALLBACK_POLICY", config.EnableFallbackPolicy)
+	config.LocalModelPath = env.str("LOCAL_MODEL_PATH", config.LocalModelPath)
+	config.ONNXRuntimeLibPath = env.str("ONNXRUNTIME_LIB_PATH", config.ONNXRuntimeLibPath)
+	config.AlwaysActRecommendations = env.list("ALWAYS_ACT_RECOMMENDATIONS", ",", config.AlwaysActRecommendations)
+	config.SubAccountMonitoring = env.boolean("SUB_ACCOUNT_MONITORING", config.SubAccountMonitoring)
+	config.ReduceLeverageCooldown = env.duration("REDUCE_LEVERAGE_COOLDOWN", config.ReduceLeverageCooldown)
+	config.PauseNewPositionsCooldown = env.duration("PAUSE_NEW_POSITIONS_COOLDOWN", config.PauseNewPositionsCooldown)
+	config.EmergencyDeleverageCooldown = env.duration("EMERGENCY_DELEVERAGE_COOLDOWN", config.EmergencyDeleverageCooldown)
+
+	config.MinNAVConfidence = env.float("MIN_NAV_CONFIDENCE", config.MinNAVConfidence)
+	config.MinDeleverageConfidence = env.float("MIN_DELEVERAGE_CONFIDENCE", config.MinDeleverageConfidence)
+	config.MaxAssessmentAge = env.duration("MAX_ASSESSMENT_AGE", config.MaxAssessmentAge)
+	config.MaxClockSkew = env.duration("MAX_CLOCK_SKEW", config.MaxClockSkew)
+
+	config.NAVSmoothingAlpha = env.float("NAV_SMOOTHING_ALPHA", config.NAVSmoothingAlpha)
+	config.MinNAVChange = env.float("MIN_NAV_CHANGE", config.MinNAVChange)
+	config.MinNAVUpdateInterval = env.duration("MIN_NAV_UPDATE_INTERVAL", config.MinNAVUpdateInterval)
+	config.MaxNAVAge = env.duration("MAX_NAV_AGE", config.MaxNAVAge)
+	config.ForcedNAVMinConfidence = env.float("FORCED_NAV_MIN_CONFIDENCE", config.ForcedNAVMinConfidence)
+	config.DegradedConfidenceMargin = env.float("DEGRADED_CONFIDENCE_MARGIN", config.DegradedConfidenceMargin)
+	config.DegradedNAVChangeMultiplier = env.float("DEGRADED_NAV_CHANGE_MULTIPLIER", config.DegradedNAVChangeMultiplier)
+	config.DegradedBlockRoutineTx = env.boolean("DEGRADED_BLOCK_ROUTINE_TX", config.DegradedBlockRoutineTx)
+	config.NAVSubmitMode = env.str("NAV_SUBMIT_MODE", config.NAVSubmitMode)
+	config.AttestationRelayURL = env.str("ATTESTATION_RELAY_URL", config.AttestationRelayURL)
+	config.AttestationSinkURL = env.str("ATTESTATION_SINK_URL", config.AttestationSinkURL)
+	config.InvoiceDefaultThreshold = env.float("INVOICE_DEFAULT_THRESHOLD", config.InvoiceDefaultThreshold)
+	config.InvoiceDefaultPageSize = env.int("INVOICE_DEFAULT_PAGE_SIZE", config.InvoiceDefaultPageSize)
+	config.InvoiceDefaultConcurrency = env.int("INVOICE_DEFAULT_CONCURRENCY", config.InvoiceDefaultConcurrency)
+	config.InvoiceImpairMethod = env.str("INVOICE_IMPAIR_METHOD", config.InvoiceImpairMethod)
+
+	config.EventTriggerEnabled = env.boolean("EVENT_TRIGGER_ENABLED", config.EventTriggerEnabled)
+	config.TriggerEvents = env.list("TRIGGER_EVENTS", ";", config.TriggerEvents)
+	config.EventDebounce = env.duration("EVENT_DEBOUNCE", config.EventDebounce)
+	config.ReorgSafetyBlocks = uint64(env.int("REORG_SAFETY_BLOCKS", int(config.ReorgSafetyBlocks)))
+	config.ReorgLookbackBlocks = uint64(env.int("REORG_LOOKBACK_BLOCKS", int(config.ReorgLookbackBlocks)))
+
+	config.BlockTag = env.str("BLOCK_TAG", config.BlockTag)
+	config.LeverageBlockTag = env.str("LEVERAGE_BLOCK_TAG", config.LeverageBlockTag)
+	config.NAVBlockTag = env.str("NAV_BLOCK_TAG", config.NAVBlockTag)
+	config.Multicall3Addr = env.str("MULTICALL3_ADDR", config.Multicall3Addr)
+
+	config.HealthListenAddr = env.str("HEALTH_LISTEN_ADDR", config.HealthListenAddr)
+	config.MetricsListenAddr = env.str("METRICS_LISTEN_ADDR", config.MetricsListenAddr)
+	config.MetricsEnabled = env.boolean("METRICS_ENABLED", config.MetricsEnabled)
+	config.StatsDAddr = env.str("STATSD_ADDR", config.StatsDAddr)
+
+	config.ReadinessMaxAge = env.duration("READINESS_MAX_AGE", config.ReadinessMaxAge)
+	config.MaxCycleAge = env.duration("MAX_CYCLE_AGE", config.MaxCycleAge)
+	config.HeartbeatURL = env.str("HEARTBEAT_URL", config.HeartbeatURL)
+	config.CycleTimeout = env.duration("CYCLE_TIMEOUT", config.CycleTimeout)
+	config.MaxConsecutiveFailures = env.int("MAX_CONSECUTIVE_FAILURES", config.MaxConsecutiveFailures)
+	config.FailureAction = env.str("FAILURE_ACTION", config.FailureAction)
+	config.LeverageMonitorInterval = env.int("LEVERAGE_MONITOR_INTERVAL", config.LeverageMonitorInterval)
+	config.NAVUpdateInterval = env.int("NAV_UPDATE_INTERVAL", config.NAVUpdateInterval)
+	config.KYCMonitorInterval = env.int("KYC_MONITOR_INTERVAL", config.KYCMonitorInterval)
+	config.HealthCheckInterval = env.int("HEALTH_CHECK_INTERVAL", config.HealthCheckInterval)
+	config.RoleCheckInterval = env.int("ROLE_CHECK_INTERVAL", config.RoleCheckInterval)
+
+	if err := env.err(); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// addressPlaceholder is the stand-in config.env.example uses for contract
+// addresses that must be filled in after deployment
+const addressPlaceholder = "0x..."
+
+// Validate normalizes the contract addresses and ML engine URLs of c and
+// reports every one that is unusable: a leftover 0x... placeholder, an
+// address that is malformed or fails its EIP-55 checksum, or a local ML
+// engine on the mainnet profile without AllowLocalMLEndpoint
+func (c *Config) Validate() error {
+	errs := normalizeAddresses("LEVERAGED_STRATEGY_ADDR", c.LeveragedStrategyAddrs)
+	errs = append(errs, normalizeAddresses("INVOICE_TOKEN_ADDR", c.InvoiceTokenAddrs)...)
+	for _, setting := range []struct {
+		name    string
+		address *string
+	}{
+		{"KYC_VERIFIER_ADDR", &c.KYCVerifierAddr},
+		{"FUNDING_CONTRACT_ADDR", &c.FundingContractAddr},
+		{"DELEVERAGE_QUOTER_ADDR", &c.DeleverageQuoterAddr},
+		{"DELEVERAGE_APPROVAL_SPENDER", &c.DeleverageApprovalSpender},
+		{"MULTICALL3_ADDR", &c.Multicall3Addr},
+		{"EXPECTED_KEEPER_ADDRESS", &c.ExpectedKeeperAddress},
+	} {
+		errs = append(errs, normalizeAddress(setting.name, setting.address))
+	}
+
+	errs = append(errs, c.normalizeMLURL("ML_API_ENDPOINT", &c.MLAPIEndpoint))
+	for _, routes := range []struct {
+		name string
+		urls map[string]string
+	}{
+		{"ML_ENDPOINTS", c.MLEndpoints},
+		{"ML_ENSEMBLE_ENDPOINTS", c.MLEnsembleEndpoints},
+	} {
+		for _, key := range slices.Sorted(maps.Keys(routes.urls)) {
+			raw := routes.urls[key]
+			errs = append(errs, c.normalizeMLURL(routes.name+" "+key, &raw))
+			routes.urls[key] = raw
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// normalizeAddresses normalizes each address of a list setting in place
+func normalizeAddresses(name string, addresses []string) []error {
+	var errs []error
+	for i := range addresses {
+		errs = append(errs, normalizeAddress(name, &addresses[i]))
+	}
+	return errs
+}
+
+// normalizeAddress trims a contract address setting and checks it; an empty
+// address is left unset
+func normalizeAddress(name string, address *string) error {
+	raw := strings.TrimSpace(*address)
+	*address = raw
+	switch {
+	case raw == "":
+		return nil
+	case raw == addressPlaceholder:
+		return fmt.Errorf("%s is still the %s placeholder: set the deployed address or leave it empty", name, addressPlaceholder)
+	case !common.IsHexAddress(raw):
+		return fmt.Errorf("%s %q is not a hex address", name, raw)
+	}
+
+	// Single-case addresses carry no checksum; mixed case must match EIP-55
+	digits := raw[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) {
+		if want := common.HexToAddress(raw).Hex(); raw != want {
+			return fmt.Errorf("%s %s fails its checksum (expected %s): check for a typo", name, raw, want)
+		}
+	}
+	return nil
+}
+
+// normalizeMLURL trims trailing slashes from an ML engine base URL and
+// checks it is an absolute http(s) URL, refusing a local engine on the
+// mainnet profile unless AllowLocalMLEndpoint is set
+func (c *Config) normalizeMLURL(name string, raw *string) error {
+	*raw = strings.TrimRight(strings.TrimSpace(*raw), "/")
+	parsed, err := url.Parse(*raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%s %q is not an http(s) URL", name, *raw)
+	}
+	if c.Profile == ProfileMainnet && !c.AllowLocalMLEndpoint && isLocalHost(parsed.Hostname()) {
+		return fmt.Errorf("%s %s is local on the %s profile: point it at the production ML engine or set ALLOW_LOCAL_ML_ENDPOINT=true", name, *raw, ProfileMainnet)
+	}
+	return nil
+}
+
+// isLocalHost reports whether host names this machine
+func isLocalHost(host string) bool {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
+// readEnvFile parses a KEY=VALUE file in the config.env.example format,
+// skipping blank lines and # comments; an empty path yields no settings
+func readEnvFile(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	values := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, val, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("env file %s line %d: want KEY=VALUE", path, i+1)
+		}
+		val = strings.TrimSpace(val)
+		if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+			val = val[1 : len(val)-1]
+		}
+		values[strings.TrimSpace(key)] = val
+	}
+	return values, nil
+}
+
+// envReader reads typed environment variables, collecting parse errors.
+// Variables unset in the process environment fall back to file.
+type envReader struct {
+	file map[string]string
+	errs []error
+}
+
+// get returns a variable from the process environment, else from the file
+func (e *envReader) get(key string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return e.file[key]
+}
+
+func (e *envReader) str(key, defaultVal string) string {
+	if val := e.get(key); val != "" {
+		return val
+	}
+	return defaultVal
+}
+
+func (e *envReader) int(key string, defaultVal int) int {
+	return int(e.int64(key, int64(defaultVal)))
+}
+
+func (e *envReader) int64(key string, defaultVal int64) int64 {
+	val := e.get(key)
+	if val == "" {
+		return defaultVal
+	}
+	n, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("invalid integer for %s: %w", key, err))
+		return defaultVal
+	}
+	return n
+}
+
+func (e *envReader) float(key string, defaultVal float64) float64 {
+	val := e.get(key)
+	if val == "" {
+		return defaultVal
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("invalid number for %s: %w", key, err))
+		return defaultVal
+	}
+	return f
+}
+
+func (e *envReader) boolean(key string, defaultVal bool) bool {
+	val := e.get(key)
+	if val == "" {
+		return defaultVal
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("invalid boolean for %s: %w", key, err))
+		return defaultVal
+	}
+	return b
+}
+
+func (e *envReader) duration(key string, defaultVal time.Duration) time.Duration {
+	val := e.get(key)
+	if val == "" {
+		return defaultVal
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("invalid duration for %s: %w", key, err))
+		return defaultVal
+	}
+	return d
+}
+
+func (e *envReader) bigInt(key string, defaultVal *big.Int) *big.Int {
+	val := e.get(key)
+	if val == "" {
+		return defaultVal
+	}
+	n, ok := new(big.Int).SetString(val, 10)
+	if !ok {
+		e.errs = append(e.errs, fmt.Errorf("invalid integer for %s: %q", key, val))
+		return defaultVal
+	}
+	return n
+}
+
+// list splits a sep-separated variable, trimming blanks
+func (e *envReader) list(key, sep string, defaultVal []string) []string {
+	val := e.get(key)
+	if val == "" {
+		return defaultVal
+	}
+	var items []string
+	for _, item := range strings.Split(val, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// mapping parses a semicolon-separated list of key=value pairs
+func (e *envReader) mapping(key string, defaultVal map[string]string) map[string]string {
+	items := e.list(key, ";", nil)
+	if items == nil {
+		return defaultVal
+	}
+	m := make(map[string]string, len(items))
+	for _, item := range items {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			e.errs = append(e.errs, fmt.Errorf("invalid key=value pair for %s: %q", key, item))
+			continue
+		}
+		m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return m
+}
+
+// floatMapping parses a semicolon-separated list of key=number pairs
+func (e *envReader) floatMapping(key string, defaultVal map[string]float64) map[string]float64 {
+	raw := e.mapping(key, nil)
+	if raw == nil {
+		return defaultVal
+	}
+	m := make(map[string]float64, len(raw))
+	for k, v := range raw {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("invalid number for %s key %s: %w", key, k, err))
+			continue
+		}
+		m[k] = f
+	}
+	return m
+}
+
+func (e *envReader) err() error {
+	return errors.Join(e.errs...)
+}
@@ -0,0 +1,111 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Minimal built-in ABIs for the Veritas contract methods the keeper calls;
+// Config.*ABIPath files replace them at startup
+const (
+	strategyABIJSON = `[
+		{"type":"function","name":"emergencyDeleverage","stateMutability":"nonpayable","inputs":[{"name":"aitToSell","type":"uint256"}],"outputs":[]},
+		{"type":"function","name":"harvestRwaYield","stateMutability":"nonpayable","inputs":[],"outputs":[{"name":"yieldAmount","type":"uint256"}]},
+		{"type":"function","name":"repayDebt","stateMutability":"nonpayable","inputs":[{"name":"amount","type":"uint256"}],"outputs":[]},
+		{"type":"function","name":"totalAITHoldings","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"totalCollateral","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"totalBorrowed","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"mETH","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+		{"type":"function","name":"usdc","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+		{"type":"function","name":"ait","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+		{"type":"function","name":"getLeverageMetrics","stateMutability":"view","inputs":[],"outputs":[{"name":"ltv","type":"uint256"},{"name":"healthFactor","type":"uint256"},{"name":"aitValue","type":"uint256"},{"name":"netExposure","type":"uint256"}]},
+		{"type":"function","name":"getSubAccounts","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address[]"}]},
+		{"type":"function","name":"getSubAccountMetrics","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"collateral","type":"uint256"},{"name":"borrowed","type":"uint256"},{"name":"ltv","type":"uint256"},{"name":"healthFactor","type":"uint256"},{"name":"aitValue","type":"uint256"}]},
+		{"type":"function","name":"subAccountAITHoldings","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"emergencyDeleverageSubAccount","stateMutability":"nonpayable","inputs":[{"name":"account","type":"address"},{"name":"aitToSell","type":"uint256"}],"outputs":[]},
+		{"type":"function","name":"reduceSubAccountLeverage","stateMutability":"nonpayable","inputs":[{"name":"account","type":"address"}],"outputs":[]}
+	]`
+
+	invoiceTokenABIJSON = `[
+		{"type":"function","name":"updateNav","stateMutability":"nonpayable","inputs":[{"name":"newNav","type":"uint256"}],"outputs":[]},
+		{"type":"function","name":"navPerToken","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"lastNavUpdate","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"totalSupply","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+		{"type":"function","name":"pool","stateMutability":"view","inputs":[],"outputs":[{"name":"poolId","type":"bytes32"},{"name":"totalFaceValue","type":"uint256"},{"name":"numberOfInvoices","type":"uint256"},{"name":"weightedMaturity","type":"uint256"},{"name":"expectedYield","type":"uint256"},{"name":"realizedYield","type":"uint256"},{"name":"defaultRate","type":"uint256"}]}
+	]`
+)
+
+// Fixed-point scales of on-chain values that are not token amounts; token
+// amounts use the decimals read from each token, see loadTokenDecimals
+const (
+	navDecimals = 6 // navPerToken, in USDC scaled by 1e6
+	bpsDecimals = 4 // basis-point ratios (LTV, health factor)
+)
+
+var (
+	strategyABI     = mustParseABI(strategyABIJSON)
+	invoiceTokenABI = mustParseABI(invoiceTokenABIJSON)
+)
+
+// mustParseABI parses a built-in ABI definition
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in ABI: %v", err))
+	}
+	return parsed
+}
+
+// Block tags accepted by Config.BlockTag and the per-monitor overrides
+const (
+	BlockTagLatest    = "latest"
+	BlockTagSafe      = "safe"
+	BlockTagFinalized = "finalized"
+)
+
+// parseBlockTag converts a block tag to the block argument of CallContract;
+// latest is nil, the others are the negative rpc.BlockNumber sentinels
+func parseBlockTag(tag string) (*big.Int, error) {
+	switch tag {
+	case "", BlockTagLatest:
+		return nil, nil
+	case BlockTagSafe:
+		return big.NewInt(int64(rpc.SafeBlockNumber)), nil
+	case BlockTagFinalized:
+		return big.NewInt(int64(rpc.FinalizedBlockNumber)), nil
+	default:
+		return nil, fmt.Errorf("unknown block tag %q (want latest, safe or finalized)", tag)
+	}
+}
+
+// callContract executes a read-only contract call at block (nil for latest)
+// and unpacks its outputs
+func (b *Bot) callContract(ctx context.Context, block *big.Int, contractABI abi.ABI, contract common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+
+	output, err := b.eth().CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, block)
+	if err != nil {
+		b.noteRPCError(err)
+		return nil, fmt.Errorf("%s call failed: %w", method, err)
+	}
+
+	return contractABI.Unpack(method, output)
+}
+
+// scaleAmount converts a fixed-point on-chain amount to a float
+func scaleAmount(amount *big.Int, decimals int) float64 {
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), scale).Float64()
+	return value
+}
@@ -0,0 +1,87 @@
+package keeper
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// cooldownKey identifies a risk action against one strategy position; a zero
+// account is the aggregate position
+type cooldownKey struct {
+	action   string
+	strategy common.Address
+	account  common.Address
+}
+
+// ActiveCooldown is a risk action suppressed for a position, as shown on /status
+type ActiveCooldown struct {
+	Action   string    `json:"action"`
+	Strategy string    `json:"strategy"`
+	Account  string    `json:"account,omitempty"`
+	Until    time.Time `json:"until"`
+}
+
+// actionCooldown is how long a recommendation stays suppressed for a
+// position after its action fires
+func (b *Bot) actionCooldown(recommendation string) time.Duration {
+	config := b.cfg()
+	switch recommendation {
+	case RecEmergencyDeleverage:
+		return config.EmergencyDeleverageCooldown
+	case RecReduceLeverage:
+		return config.ReduceLeverageCooldown
+	case RecPauseNewPositions:
+		return config.PauseNewPositionsCooldown
+	default:
+		return 0
+	}
+}
+
+// cooldownUntil returns when the cooldown for key ends, if one is active
+func (b *Bot) cooldownUntil(key cooldownKey) (time.Time, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	until, ok := b.cooldowns[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	if !time.Now().Before(until) {
+		delete(b.cooldowns, key)
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// startCooldown suppresses key for its action's cooldown window
+func (b *Bot) startCooldown(key cooldownKey) {
+	cooldown := b.actionCooldown(key.action)
+	if cooldown <= 0 {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.cooldowns[key] = time.Now().Add(cooldown)
+}
+
+// activeCooldowns lists unexpired cooldowns, soonest to end first; caller
+// holds mutex
+func (b *Bot) activeCooldowns() []ActiveCooldown {
+	now := time.Now()
+	active := make([]ActiveCooldown, 0, len(b.cooldowns))
+	for key, until := range b.cooldowns {
+		if !now.Before(until) {
+			continue
+		}
+		cooldown := ActiveCooldown{Action: key.action, Strategy: key.strategy.Hex(), Until: until}
+		if key.account != (common.Address{}) {
+			cooldown.Account = key.account.Hex()
+		}
+		active = append(active, cooldown)
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].Until.Before(active[j].Until) })
+	return active
+}
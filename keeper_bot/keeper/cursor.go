@@ -0,0 +1,137 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+// maxScanRange bounds the blocks fetched by a single FilterLogs call
+const maxScanRange = 5000
+
+// eventCursor is the persisted progress of a reorg-safe event scan
+type eventCursor struct {
+	// Block is the last block whose events were processed
+	Block uint64 `json:"block"`
+	// Hashes records the hash of recently processed blocks (the cursor block
+	// and blocks that contained events) so reorgs can be detected later
+	Hashes map[uint64]common.Hash `json:"hashes"`
+}
+
+// cursorKey is the Store key for a named event cursor
+func cursorKey(name string) StoreKey[eventCursor] {
+	return StoreKey[eventCursor]{"cursor_" + name}
+}
+
+// scanEvents passes handle the logs matching query from the persisted cursor
+// up to head - ReorgSafetyBlocks, then advances the cursor. Before scanning,
+// recently processed blocks are re-checked against the canonical chain; if a
+// hash changed, the cursor rewinds so the reorganized range is scanned again.
+// A fresh cursor starts at the safe head without backfilling history.
+func (b *Bot) scanEvents(ctx context.Context, name string, query ethereum.FilterQuery, handle func([]types.Log) error) error {
+	config := b.cfg()
+	head, err := b.eth().BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if head < config.ReorgSafetyBlocks {
+		return nil
+	}
+	safeHead := head - config.ReorgSafetyBlocks
+
+	cursor, ok, err := Load(b.store, cursorKey(name))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		cursor = eventCursor{Block: safeHead, Hashes: make(map[uint64]common.Hash)}
+		return b.saveCursor(ctx, name, cursor, nil)
+	}
+
+	if err := b.rewindOnReorg(ctx, name, &cursor); err != nil {
+		return err
+	}
+	if safeHead <= cursor.Block {
+		return nil
+	}
+
+	to := safeHead
+	if to-cursor.Block > maxScanRange {
+		to = cursor.Block + maxScanRange
+	}
+	query.FromBlock = new(big.Int).SetUint64(cursor.Block + 1)
+	query.ToBlock = new(big.Int).SetUint64(to)
+
+	logs, err := b.eth().FilterLogs(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to filter %s events: %w", name, err)
+	}
+
+	if len(logs) > 0 {
+		if err := handle(logs); err != nil {
+			return err
+		}
+	}
+
+	cursor.Block = to
+	return b.saveCursor(ctx, name, cursor, logs)
+}
+
+// rewindOnReorg moves the cursor back before the earliest recorded block
+// whose hash is no longer canonical
+func (b *Bot) rewindOnReorg(ctx context.Context, name string, cursor *eventCursor) error {
+	reorgFrom := uint64(0)
+	for number, hash := range cursor.Hashes {
+		header, err := b.eth().HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		}
+		if header.Hash() != hash && (reorgFrom == 0 || number < reorgFrom) {
+			reorgFrom = number
+		}
+	}
+	if reorgFrom == 0 {
+		return nil
+	}
+
+	b.logger.WithFields(logrus.Fields{
+		"cursor":     name,
+		"from_block": reorgFrom,
+		"old_cursor": cursor.Block,
+	}).Warn("Chain reorg detected, re-scanning events")
+	b.metrics.AddCounter(metricReorgsDetected, 1, "cursor", name)
+
+	cursor.Block = reorgFrom - 1
+	for number := range cursor.Hashes {
+		if number >= reorgFrom {
+			delete(cursor.Hashes, number)
+		}
+	}
+	return nil
+}
+
+// saveCursor records hashes for the cursor block and event blocks, prunes
+// those outside the lookback window, and persists the cursor
+func (b *Bot) saveCursor(ctx context.Context, name string, cursor eventCursor, logs []types.Log) error {
+	header, err := b.eth().HeaderByNumber(ctx, new(big.Int).SetUint64(cursor.Block))
+	if err != nil {
+		return err
+	}
+	cursor.Hashes[cursor.Block] = header.Hash()
+	for _, vLog := range logs {
+		cursor.Hashes[vLog.BlockNumber] = vLog.BlockHash
+	}
+
+	for number := range cursor.Hashes {
+		if number+b.cfg().ReorgLookbackBlocks < cursor.Block {
+			delete(cursor.Hashes, number)
+		}
+	}
+
+	return Save(b.store, cursorKey(name), cursor)
+}
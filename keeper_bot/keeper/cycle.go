@@ -0,0 +1,43 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runCycle runs one scheduled job under CycleTimeout, so a monitor wedged on
+// a slow RPC or ML call is cancelled instead of holding its run lock until
+// the next ticks pile up behind it, and with a fresh RetryBudget shared by
+// all its calls. Jobs named after a paused monitor are skipped.
+func (b *Bot) runCycle(ctx context.Context, job string, run func(ctx context.Context) error) error {
+	config := b.cfg()
+	if b.monitorPaused(job) {
+		b.logger.WithField("monitor", job).Debug("Monitor paused, skipping run")
+		return nil
+	}
+
+	if config.CycleTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.CycleTimeout)
+		defer cancel()
+	}
+
+	if config.RetryBudget > 0 {
+		ctx = withRetryBudget(ctx, job, config.RetryBudget)
+	}
+
+	started := time.Now()
+	err := run(ctx)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		b.logger.WithFields(logrus.Fields{
+			"job":      job,
+			"timeout":  config.CycleTimeout.String(),
+			"duration": time.Since(started).String(),
+		}).Warn("Cycle timed out and was cancelled")
+		b.metrics.AddCounter(metricCycleTimeouts, 1, "job", job)
+	}
+	return err
+}
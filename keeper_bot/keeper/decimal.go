@@ -0,0 +1,131 @@
+package keeper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal value decoded from an ML response. It accepts
+// a JSON number or a decimal string and keeps every digit, so a NAV like
+// 1.005 is published as 1005000 base units rather than float64's 1004999.
+type Decimal struct {
+	rat big.Rat
+}
+
+// NewDecimal returns the exact decimal value of a float64
+func NewDecimal(f float64) *Decimal {
+	d := new(Decimal)
+	d.rat.SetFloat64(f)
+	return d
+}
+
+// Bounds on a decoded Decimal. big.Rat would accept a fraction such as 1/3
+// or an exponent like 1e1000000000 whose expansion exhausts memory, neither
+// of which a NAV or score ever needs.
+const (
+	maxDecimalLength   = 100
+	maxDecimalExponent = 64
+)
+
+// UnmarshalJSON decodes a JSON number or decimal string without rounding
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	text := string(bytes.Trim(data, `"`))
+	if err := checkDecimalText(text); err != nil {
+		return fmt.Errorf("invalid decimal %.40s: %w", data, err)
+	}
+	if _, ok := d.rat.SetString(text); !ok {
+		return fmt.Errorf("invalid decimal %s", data)
+	}
+	return nil
+}
+
+// checkDecimalText accepts only plain decimal notation: an optional sign,
+// digits with at most one decimal point and an optional exponent within
+// maxDecimalExponent
+func checkDecimalText(text string) error {
+	if len(text) > maxDecimalLength {
+		return fmt.Errorf("%d characters exceeds %d", len(text), maxDecimalLength)
+	}
+	mantissa, exponent, hasExponent := strings.Cut(strings.ToLower(text), "e")
+	if strings.HasPrefix(mantissa, "-") || strings.HasPrefix(mantissa, "+") {
+		mantissa = mantissa[1:]
+	}
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	if whole+fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return fmt.Errorf("not a decimal number")
+	}
+	if hasExponent {
+		exp, err := strconv.Atoi(exponent)
+		if err != nil {
+			return fmt.Errorf("invalid exponent %q", exponent)
+		}
+		if exp > maxDecimalExponent || exp < -maxDecimalExponent {
+			return fmt.Errorf("exponent %d exceeds ±%d", exp, maxDecimalExponent)
+		}
+	}
+	return nil
+}
+
+// isDigits reports whether s holds only ASCII digits
+func isDigits(s string) bool {
+	return strings.Trim(s, "0123456789") == ""
+}
+
+// MarshalJSON encodes the value as a JSON number
+func (d *Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(json.Number(d.String()))
+}
+
+// Rat returns the exact value
+func (d *Decimal) Rat() *big.Rat {
+	return new(big.Rat).Set(&d.rat)
+}
+
+// Float64 returns the nearest float64, for logs and metrics only
+func (d *Decimal) Float64() float64 {
+	f, _ := d.rat.Float64()
+	return f
+}
+
+// String formats the value with up to 18 decimal places, trailing zeros trimmed
+func (d *Decimal) String() string {
+	return formatRat(&d.rat)
+}
+
+// formatRat formats an exact value with up to 18 decimal places, trailing
+// zeros trimmed
+func formatRat(value *big.Rat) string {
+	text := value.FloatString(18)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	return text
+}
+
+// toFixedPoint converts an exact value to an on-chain fixed-point amount,
+// rounding half away from zero at the last decimal place
+func toFixedPoint(value *big.Rat, decimals int) *big.Int {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(scale))
+
+	quo, rem := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	// |rem| / denom >= 1/2  <=>  2|rem| >= denom
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(scaled.Denom()) >= 0 {
+		if scaled.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
+
+// fromFixedPoint converts an on-chain fixed-point amount to an exact value
+func fromFixedPoint(amount *big.Int, decimals int) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Rat).SetFrac(amount, scale)
+}
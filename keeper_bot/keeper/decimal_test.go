@@ -0,0 +1,76 @@
+package keeper
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestDecimalKeepsDigitsFloatLoses(t *testing.T) {
+	var d Decimal
+	if err := json.Unmarshal([]byte(`1.005`), &d); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got := toFixedPoint(d.Rat(), 6); got.Cmp(big.NewInt(1_005_000)) != 0 {
+		t.Errorf("decimal 1.005 = %s base units, want 1005000", got)
+	}
+
+	// Decoded as float64 and scaled the way NAV used to be published, the
+	// same value loses a base unit
+	var f float64
+	if err := json.Unmarshal([]byte(`1.005`), &f); err != nil {
+		t.Fatalf("unmarshal float: %v", err)
+	}
+	if got := int64(f * 1e6); got != 1_004_999 {
+		t.Errorf("float64 1.005 = %d base units, want 1004999", got)
+	}
+}
+
+func TestDecimalUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`1.005`, "1.005"},
+		{`"1.005"`, "1.005"},
+		{`-0.25`, "-0.25"},
+		{`12`, "12"},
+		{`"0.5"`, "0.5"},
+		{`1.5e3`, "1500"},
+		{`1E-2`, "0.01"},
+		{`1e64`, "1" + strings.Repeat("0", 64)},
+	}
+	for _, tt := range tests {
+		var d Decimal
+		if err := json.Unmarshal([]byte(tt.input), &d); err != nil {
+			t.Errorf("unmarshal %s: %v", tt.input, err)
+			continue
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("unmarshal %s = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalUnmarshalJSONRejects(t *testing.T) {
+	for _, input := range []string{
+		`"1/3"`,
+		`"1e1000000000"`,
+		`"1e-65"`,
+		`"` + strings.Repeat("9", maxDecimalLength+1) + `"`,
+		`"0x10"`,
+		`"Inf"`,
+		`"NaN"`,
+		`"1.2.3"`,
+		`"--1"`,
+		`"."`,
+		`""`,
+		`"1e"`,
+	} {
+		var d Decimal
+		if err := json.Unmarshal([]byte(input), &d); err == nil {
+			t.Errorf("unmarshal %.40s = %s, want error", input, d.String())
+		}
+	}
+}
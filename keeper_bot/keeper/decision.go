@@ -0,0 +1,376 @@
+package keeper
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Decision outcomes
+const (
+	DecisionAct           = "act"
+	DecisionNoAction      = "no_action"
+	DecisionNotActionable = "not_actionable"
+)
+
+// Decision is one leverage decision as written to the decision log: the
+// inputs it was made from and the action chosen for them. Cooldowns, budgets
+// and other runtime guards applied afterwards are not part of the decision.
+type Decision struct {
+	Time       time.Time        `json:"time"`
+	Strategy   string           `json:"strategy"`
+	Account    string           `json:"account,omitempty"`
+	InputsHash string           `json:"inputs_hash"`
+	Position   StrategyPosition `json:"position"`
+	// MLResponse is the raw ML engine response, or the local model's when
+	// LocalModel is set; empty for fallback decisions
+	MLResponse      json.RawMessage `json:"ml_response,omitempty"`
+	Fallback        bool            `json:"fallback,omitempty"`
+	LocalModel      bool            `json:"local_model,omitempty"`
+	Recommendations []string        `json:"recommendations"`
+	Outcome         string          `json:"outcome"`
+	Action          string          `json:"action,omitempty"`
+	// Skipped are the conflicting recommendations RecommendationPolicy
+	// chose Action over
+	Skipped []string `json:"skipped,omitempty"`
+	Reason  string   `json:"reason,omitempty"`
+}
+
+// leverageDecision is the risk action chosen for one assessment
+type leverageDecision struct {
+	now        time.Time
+	assessment *LeverageHealthResponse
+	// fallbackReasons are the on-chain limits breached when the assessment
+	// was made locally
+	fallbackReasons []string
+	// notActionable is why the assessment may not be acted on, if it may not
+	notActionable error
+	// advisory are recommendations not acted on because the engine reported
+	// action_required=false
+	advisory []string
+	// overrideReasons are the local thresholds breached when an ML
+	// assessment recommending no action was overridden
+	overrideReasons []string
+	// unknownLevel is the unrecognized risk level the ML engine reported,
+	// replaced in assessment by UnknownRiskLevelAs
+	unknownLevel string
+
+	chosen  string
+	action  RiskAction
+	skipped []string
+	unknown []string
+	ok      bool
+}
+
+// outcome classifies the decision for the decision log
+func (d *leverageDecision) outcome() string {
+	switch {
+	case d.ok:
+		return DecisionAct
+	case d.notActionable != nil:
+		return DecisionNotActionable
+	default:
+		return DecisionNoAction
+	}
+}
+
+// decideRiskAction chooses the risk action for a position from its raw ML
+// response, or from the fallback assessment when response is nil. It reads
+// only the config and the registered actions, so a recorded decision replays
+// deterministically.
+//
+// Precedence for an ML response: a stale assessment is not actionable,
+// then one below MinDeleverageConfidence is not (see checkAssessment); a
+// not-actionable assessment falls back to the on-chain limits alone.
+// Otherwise the ML recommendations apply, cross-checked by
+// overrideByThresholds when they call for no action.
+func (b *Bot) decideRiskAction(now time.Time, position *StrategyPosition, response []byte) (*leverageDecision, error) {
+	config := b.cfg()
+	decision := &leverageDecision{now: now}
+
+	if response == nil {
+		decision.assessment, decision.fallbackReasons = b.fallbackAssessment(position)
+	} else {
+		var assessment LeverageHealthResponse
+		if err := json.Unmarshal(response, &assessment); err != nil {
+			return nil, fmt.Errorf("failed to parse ML response: %w", err)
+		}
+		decision.assessment = &assessment
+		decision.unknownLevel = b.normalizeRiskLevel(&assessment)
+
+		// Deleveraging reduces risk, so it accepts a lower confidence floor
+		// than NAV writes. Engines that report no confidence are taken at
+		// face value.
+		confidence := 1.0
+		if assessment.Confidence != nil {
+			confidence = *assessment.Confidence
+		}
+		err := checkAssessment(now, assessment.Timestamp, confidence, config.MinDeleverageConfidence, config.MaxAssessmentAge)
+		if err != nil {
+			decision.notActionable = err
+			b.overrideNotActionable(position, decision)
+			return decision, nil
+		}
+	}
+
+	recommendations := decision.assessment.Recommendations
+	if !decision.assessment.ActionRequired {
+		recommendations, decision.advisory = b.splitAdvisory(recommendations)
+	}
+	decision.chosen, decision.action, decision.skipped, decision.unknown, decision.ok = b.selectRiskAction(recommendations)
+	if response != nil && !decision.ok {
+		b.overrideByThresholds(position, decision)
+	}
+	if decision.unknownLevel != "" && !decision.ok {
+		b.overrideUnknownRiskLevel(position, decision)
+	}
+	return decision, nil
+}
+
+// overrideByThresholds chooses a protective action for an ML assessment that
+// recommends none when the position breaches the local thresholds: an
+// emergency deleverage at CriticalRisk, otherwise a leverage reduction
+func (b *Bot) overrideByThresholds(position *StrategyPosition, decision *leverageDecision) {
+	config := b.cfg()
+	if !config.ThresholdOverride {
+		return
+	}
+	reasons := b.positionBreaches(position)
+	recommendation := RecReduceLeverage
+	switch score := decision.assessment.CompositeRiskScore; {
+	case score >= config.CriticalRisk:
+		reasons = append(reasons, "risk_score_above_critical")
+		recommendation = RecEmergencyDeleverage
+	case score >= config.HighRisk:
+		reasons = append(reasons, "risk_score_above_high")
+	}
+	if len(reasons) == 0 {
+		return
+	}
+
+	chosen, action, _, _, ok := b.selectRiskAction([]string{recommendation})
+	if !ok {
+		return
+	}
+	decision.chosen, decision.action, decision.ok = chosen, action, true
+	decision.overrideReasons = reasons
+}
+
+// overrideNotActionable chooses a leverage reduction for a position whose ML
+// assessment may not be acted on when the position breaches MinHealthFactor
+// or MaxLTV on-chain. The local cross-check matters most when the model is
+// least trustworthy, so it runs whenever ThresholdOverride or
+// EnableFallbackPolicy is on. Like the fallback policy it rests on on-chain
+// data alone: the untrusted risk score is ignored and it never escalates to
+// an emergency deleverage.
+func (b *Bot) overrideNotActionable(position *StrategyPosition, decision *leverageDecision) {
+	config := b.cfg()
+	if !config.ThresholdOverride && !config.EnableFallbackPolicy {
+		return
+	}
+	reasons := b.positionBreaches(position)
+	if len(reasons) == 0 {
+		return
+	}
+
+	chosen, action, _, _, ok := b.selectRiskAction([]string{RecReduceLeverage})
+	if !ok {
+		return
+	}
+	decision.chosen, decision.action, decision.ok = chosen, action, true
+	decision.overrideReasons = reasons
+}
+
+// splitAdvisory separates the recommendations that are acted on without
+// action_required, per AlwaysActRecommendations, from the advisory rest
+func (b *Bot) splitAdvisory(recommendations []string) (act, advisory []string) {
+	for _, recommendation := range recommendations {
+		if slices.Contains(b.cfg().AlwaysActRecommendations, recommendation) {
+			act = append(act, recommendation)
+		} else {
+			advisory = append(advisory, recommendation)
+		}
+	}
+	return act, advisory
+}
+
+// newDecision builds the decision log record of a leverage decision
+func newDecision(strategy, account string, position *StrategyPosition, response []byte, decision *leverageDecision) Decision {
+	record := Decision{
+		Time:            decision.now.UTC(),
+		Strategy:        strategy,
+		Account:         account,
+		Position:        *position,
+		Fallback:        response == nil,
+		Recommendations: decision.assessment.Recommendations,
+		Outcome:         decision.outcome(),
+	}
+	if response != nil {
+		var compact bytes.Buffer
+		if json.Compact(&compact, response) == nil {
+			record.MLResponse = compact.Bytes()
+		}
+	}
+	record.InputsHash = record.inputsHash()
+
+	switch {
+	case len(decision.overrideReasons) > 0:
+		record.Action = decision.chosen
+		record.Reason = "override: " + strings.Join(decision.overrideReasons, ",")
+		if decision.notActionable != nil {
+			record.Reason += " (" + decision.notActionable.Error() + ")"
+		}
+	case decision.notActionable != nil:
+		record.Reason = decision.notActionable.Error()
+	case decision.ok:
+		record.Action = decision.chosen
+		record.Skipped = decision.skipped
+	case len(decision.advisory) > 0:
+		record.Reason = "advisory: action_required=false"
+	}
+	if record.Fallback {
+		record.Reason = strings.Join(decision.fallbackReasons, ",")
+	}
+	return record
+}
+
+// inputsHash fingerprints the inputs a decision was made from
+func (d Decision) inputsHash() string {
+	position, _ := json.Marshal(d.Position)
+	hash := sha256.New()
+	hash.Write(position)
+	hash.Write([]byte{0})
+	hash.Write(d.MLResponse)
+	hash.Write([]byte{0})
+	hash.Write([]byte(d.Time.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// recordDecision appends a decision to the decision log, reporting failures
+// on the operational log
+func (b *Bot) recordDecision(record Decision) {
+	if err := b.decisions.Write(record); err != nil {
+		b.logger.WithError(err).WithField("strategy", record.Strategy).Error("Failed to write decision record")
+	}
+}
+
+// DecisionLog appends Decisions as JSON lines for replay with --compare
+type DecisionLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenDecisionLog opens path for appending; an empty path disables the log
+func OpenDecisionLog(path string) (*DecisionLog, error) {
+	if path == "" {
+		return &DecisionLog{}, nil
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open decision log: %w", err)
+	}
+	return &DecisionLog{file: file}, nil
+}
+
+// Write appends a record
+func (d *DecisionLog) Write(record Decision) error {
+	if d.file == nil {
+		return nil
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err = d.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the decision log file
+func (d *DecisionLog) Close() error {
+	if d.file == nil {
+		return nil
+	}
+	return d.file.Close()
+}
+
+// CompareDecisions replays every decision in a decision log through the
+// current decision logic with config, without a chain or ML engine, and
+// writes a line to out for each one that now chooses a different outcome or
+// action. It returns the number of divergent decisions. Only the built-in
+// risk actions are replayed.
+func CompareDecisions(config *Config, log io.Reader, out io.Writer) (int, error) {
+	replayer := &Bot{riskActions: make(map[string]RiskAction)}
+	replayer.config.Store(config)
+	replayer.registerDefaultRiskActions()
+
+	scanner := bufio.NewScanner(log)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	replayed, diverged := 0, 0
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var recorded Decision
+		if err := json.Unmarshal(scanner.Bytes(), &recorded); err != nil {
+			return diverged, fmt.Errorf("line %d: malformed decision: %w", line, err)
+		}
+		if hash := recorded.inputsHash(); hash != recorded.InputsHash {
+			return diverged, fmt.Errorf("line %d: inputs hash mismatch (recorded %s, computed %s)", line, recorded.InputsHash, hash)
+		}
+
+		var response []byte
+		if !recorded.Fallback {
+			response = recorded.MLResponse
+		}
+		decision, err := replayer.decideRiskAction(recorded.Time, &recorded.Position, response)
+		if err != nil {
+			return diverged, fmt.Errorf("line %d: %w", line, err)
+		}
+		replay := newDecision(recorded.Strategy, recorded.Account, &recorded.Position, response, decision)
+		replayed++
+
+		if replay.Outcome == recorded.Outcome && replay.Action == recorded.Action {
+			continue
+		}
+		diverged++
+		position := recorded.Strategy
+		if recorded.Account != "" {
+			position += "/" + recorded.Account
+		}
+		fmt.Fprintf(out, "line %d: %s %s: recorded %s, replayed %s\n",
+			line, recorded.Time.Format(time.RFC3339), position,
+			describeDecision(recorded), describeDecision(replay))
+	}
+	if err := scanner.Err(); err != nil {
+		return diverged, fmt.Errorf("failed to read decision log: %w", err)
+	}
+
+	fmt.Fprintf(out, "%d decisions replayed, %d diverged\n", replayed, diverged)
+	return diverged, nil
+}
+
+// describeDecision renders a decision's outcome for the comparison report
+func describeDecision(d Decision) string {
+	text := d.Outcome
+	if d.Action != "" {
+		text += " " + d.Action
+	}
+	if d.Reason != "" {
+		text += " (" + d.Reason + ")"
+	}
+	return text
+}
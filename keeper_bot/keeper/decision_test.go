@@ -0,0 +1,128 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// assessmentJSON renders an ML leverage assessment recommending no action
+func assessmentJSON(score, confidence float64, timestamp time.Time) []byte {
+	return fmt.Appendf(nil, `{"composite_risk_score":%g,"risk_level":"LOW","action_required":false,"recommendations":[],"timestamp":%d,"confidence":%g}`,
+		score, timestamp.Unix(), confidence)
+}
+
+func TestDecideRiskActionOverridesNotActionable(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	healthy := &StrategyPosition{TotalBorrowed: 100, HealthFactor: 2, LTV: 0.4}
+	breaching := &StrategyPosition{TotalBorrowed: 100, HealthFactor: 1.05, LTV: 0.4}
+
+	tests := []struct {
+		name              string
+		position          *StrategyPosition
+		response          []byte
+		thresholdOverride bool
+		wantErr           error
+		wantOutcome       string
+		wantAction        string
+	}{
+		{
+			name:              "low confidence, position breaches",
+			position:          breaching,
+			response:          assessmentJSON(0.1, 0.2, now),
+			thresholdOverride: true,
+			wantErr:           ErrLowConfidence,
+			wantOutcome:       DecisionAct,
+			wantAction:        RecReduceLeverage,
+		},
+		{
+			name:              "stale, position breaches",
+			position:          breaching,
+			response:          assessmentJSON(0.1, 0.9, now.Add(-time.Hour)),
+			thresholdOverride: true,
+			wantErr:           ErrStaleAssessment,
+			wantOutcome:       DecisionAct,
+			wantAction:        RecReduceLeverage,
+		},
+		{
+			name:              "low confidence ignores the untrusted critical score",
+			position:          breaching,
+			response:          assessmentJSON(0.99, 0.2, now),
+			thresholdOverride: true,
+			wantErr:           ErrLowConfidence,
+			wantOutcome:       DecisionAct,
+			wantAction:        RecReduceLeverage,
+		},
+		{
+			name:              "low confidence, position within limits",
+			position:          healthy,
+			response:          assessmentJSON(0.1, 0.2, now),
+			thresholdOverride: true,
+			wantErr:           ErrLowConfidence,
+			wantOutcome:       DecisionNotActionable,
+		},
+		{
+			name:        "low confidence, cross-check disabled",
+			position:    breaching,
+			response:    assessmentJSON(0.1, 0.2, now),
+			wantErr:     ErrLowConfidence,
+			wantOutcome: DecisionNotActionable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(t)
+			config.MinHealthFactor = 1.1
+			config.MaxLTV = 0.8
+			config.MinDeleverageConfidence = 0.5
+			config.MaxAssessmentAge = 10 * time.Minute
+			config.ThresholdOverride = tt.thresholdOverride
+			config.EnableFallbackPolicy = false
+			bot, _ := newTestBot(t, config, nil)
+
+			decision, err := bot.decideRiskAction(now, tt.position, tt.response)
+			if err != nil {
+				t.Fatalf("decideRiskAction: %v", err)
+			}
+			if !errors.Is(decision.notActionable, tt.wantErr) {
+				t.Errorf("notActionable = %v, want %v", decision.notActionable, tt.wantErr)
+			}
+			if got := decision.outcome(); got != tt.wantOutcome {
+				t.Errorf("outcome = %q, want %q", got, tt.wantOutcome)
+			}
+			if decision.ok && decision.chosen != tt.wantAction {
+				t.Errorf("chosen = %q, want %q", decision.chosen, tt.wantAction)
+			}
+		})
+	}
+}
+
+func TestDecideRiskActionResolvesConflictingRecommendations(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	position := &StrategyPosition{TotalBorrowed: 100, HealthFactor: 1.3, LTV: 0.6}
+	// Pausing alone conflicts with deleveraging the position now
+	response := fmt.Appendf(nil, `{"composite_risk_score":0.9,"risk_level":"CRITICAL","action_required":true,"confidence":0.9,"timestamp":%d,
+		"recommendations":["PAUSE_NEW_POSITIONS","EMERGENCY_DELEVERAGE"]}`, now.Unix())
+	bot, _ := newTestBot(t, testConfig(t), nil)
+
+	decision, err := bot.decideRiskAction(now, position, response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decision.ok || decision.chosen != RecEmergencyDeleverage {
+		t.Fatalf("chosen = %q (ok %t), want %q", decision.chosen, decision.ok, RecEmergencyDeleverage)
+	}
+	if decision.action.Severity != SeverityEmergency {
+		t.Fatalf("action severity = %d, want %d", decision.action.Severity, SeverityEmergency)
+	}
+
+	record := newDecision("0xstrategy", "", position, response, decision)
+	if record.Outcome != DecisionAct || record.Action != RecEmergencyDeleverage {
+		t.Fatalf("recorded %s %q, want %s %q", record.Outcome, record.Action, DecisionAct, RecEmergencyDeleverage)
+	}
+	if want := []string{RecPauseNewPositions}; !slices.Equal(record.Skipped, want) {
+		t.Fatalf("recorded skipped = %q, want the conflicting %q", record.Skipped, want)
+	}
+}
@@ -0,0 +1,110 @@
+package keeper
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrDegraded is returned for a routine transaction suspended by degraded mode
+var ErrDegraded = errors.New("routine transactions suspended in degraded mode")
+
+// Reasons the bot runs in degraded mode
+const (
+	degradedMLUnhealthy    = "ml_unhealthy"
+	degradedChainUnhealthy = "chain_unhealthy"
+	degradedRPCReconnect   = "rpc_reconnecting"
+	degradedClockSkew      = "clock_skew" // suffixed by the skewed source
+)
+
+// degradedReasonsLocked derives the degraded-mode reasons from the last
+// health report, an RPC reconnect in progress and clock skew, which leaves
+// ML and chain timestamps untrustworthy. Callers hold mutex.
+func (b *Bot) degradedReasonsLocked() []string {
+	var reasons []string
+	if b.lastHealth != nil {
+		for _, failed := range b.lastHealth.Failed() {
+			switch failed {
+			case "ml_engine":
+				reasons = append(reasons, degradedMLUnhealthy)
+			case "chain":
+				reasons = append(reasons, degradedChainUnhealthy)
+			}
+		}
+	}
+	if b.reconnecting {
+		reasons = append(reasons, degradedRPCReconnect)
+	}
+	for source, skewed := range b.clockSkewed {
+		if skewed {
+			reasons = append(reasons, degradedClockSkew+"_"+source)
+		}
+	}
+	slices.Sort(reasons)
+	return reasons
+}
+
+// updateDegraded re-derives degraded mode after a health input changed,
+// exporting it and logging transitions
+func (b *Bot) updateDegraded() {
+	b.mutex.Lock()
+	previous := b.degraded
+	b.degraded = b.degradedReasonsLocked()
+	current := b.degraded
+	b.mutex.Unlock()
+
+	value := 0.0
+	if len(current) > 0 {
+		value = 1
+	}
+	b.metrics.SetGauge(metricDegraded, value)
+
+	switch {
+	case slices.Equal(previous, current):
+	case len(current) == 0:
+		b.logger.WithField("was", strings.Join(previous, ", ")).Info("Degraded mode cleared")
+	default:
+		b.logger.WithField("reasons", strings.Join(current, ", ")).Warn("DEGRADED MODE: routine writes suspended and confidence floors raised")
+	}
+}
+
+// isDegraded reports whether the bot runs in degraded mode
+func (b *Bot) isDegraded() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.degraded) > 0
+}
+
+// degradedFloor raises a confidence floor by DegradedConfidenceMargin while
+// degraded, capped at 1
+func (b *Bot) degradedFloor(floor float64) float64 {
+	if !b.isDegraded() {
+		return floor
+	}
+	return min(floor+b.cfg().DegradedConfidenceMargin, 1)
+}
+
+// degradedMinNAVChange widens MinNAVChange by DegradedNAVChangeMultiplier
+// while degraded, so only larger NAV moves are published
+func (b *Bot) degradedMinNAVChange() float64 {
+	config := b.cfg()
+	if !b.isDegraded() || config.DegradedNAVChangeMultiplier <= 1 {
+		return config.MinNAVChange
+	}
+	return config.MinNAVChange * config.DegradedNAVChangeMultiplier
+}
+
+// degradedExemptActions keep running in degraded mode: leverage
+// reductions, which the fallback policy relies on while the ML engine is
+// down, and gas top-ups that keep emergency actions affordable
+var degradedExemptActions = []string{"reduce_leverage", "balance_refill"}
+
+// degradedBlocks reports whether degraded mode suspends a transaction for
+// action: every routine write while DegradedBlockRoutineTx is set, except
+// emergency and degradedExemptActions
+func (b *Bot) degradedBlocks(action string) bool {
+	if !b.cfg().DegradedBlockRoutineTx || isEmergencyAction(action) || slices.Contains(degradedExemptActions, action) {
+		return false
+	}
+	return b.isDegraded()
+}
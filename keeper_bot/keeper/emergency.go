@@ -0,0 +1,127 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+// PendingEmergency is an emergency deleverage sent but not yet confirmed
+type PendingEmergency struct {
+	Strategy string    `json:"strategy"`
+	Account  string    `json:"account,omitempty"`
+	TxHash   string    `json:"tx_hash"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+// trackEmergency enters emergency mode once an emergency deleverage reaches
+// TxConfirmations confirmations. Until then it is reported as pending; if it
+// reverts, is reorganized out for good or does not confirm within
+// TxConfirmTimeout, emergency mode is left as it was. Transactions signed
+// but not broadcast in dry-run mode or the startup grace period never
+// change the mode.
+func (b *Bot) trackEmergency(strategy, account common.Address, subAccount bool, tx *types.Transaction) {
+	if b.cfg().DryRun || b.InStartupGrace() {
+		b.logger.WithField("tx", tx.Hash().Hex()).Info("Emergency deleverage not broadcast, emergency mode unchanged")
+		return
+	}
+
+	pending := PendingEmergency{Strategy: strategy.Hex(), TxHash: tx.Hash().Hex(), SentAt: time.Now()}
+	if subAccount {
+		pending.Account = account.Hex()
+	}
+	b.mutex.Lock()
+	b.pendingEmergencies[tx.Hash()] = pending
+	b.mutex.Unlock()
+
+	b.goBackground(func(ctx context.Context) { b.confirmEmergency(ctx, pending, tx) })
+}
+
+// confirmEmergency waits for a pending emergency deleverage and enters
+// emergency mode if it succeeds
+func (b *Bot) confirmEmergency(ctx context.Context, pending PendingEmergency, tx *types.Transaction) {
+	ctx, cancel := context.WithTimeout(ctx, b.cfg().TxConfirmTimeout)
+	defer cancel()
+
+	receipt, depth, err := b.waitForConfirmations(ctx, tx)
+	if errors.Is(err, context.Canceled) {
+		// Shutting down; a restart recovers the transaction from the nonce gap
+		return
+	}
+
+	b.mutex.Lock()
+	delete(b.pendingEmergencies, tx.Hash())
+	b.mutex.Unlock()
+
+	fields := map[string]interface{}{"strategy": pending.Strategy, "tx": pending.TxHash}
+	if pending.Account != "" {
+		fields["account"] = pending.Account
+	}
+	switch {
+	case err != nil:
+		fields["error"] = err.Error()
+		b.alerter.Send(Alert{
+			Severity: AlertCritical,
+			Title:    "Emergency deleverage not confirmed, emergency mode not entered",
+			Fields:   fields,
+		})
+		return
+	case receipt.Status != types.ReceiptStatusSuccessful:
+		fields["block"] = receipt.BlockNumber.String()
+		if reason := b.replayRevert(ctx, b.abis.strategy, tx, receipt); reason != "" {
+			fields["revert_reason"] = reason
+		}
+		b.alerter.Send(Alert{
+			Severity: AlertCritical,
+			Title:    "Emergency deleverage reverted, emergency mode not entered",
+			Fields:   fields,
+		})
+		return
+	}
+
+	b.mutex.Lock()
+	b.emergencyMode = true
+	b.mutex.Unlock()
+	if err := Save(b.store, keyEmergencyMode, true); err != nil {
+		b.logger.WithError(err).Warn("Failed to persist emergency mode")
+	}
+	b.logger.WithFields(logrus.Fields(fields)).WithFields(logrus.Fields{
+		"block":         receipt.BlockNumber,
+		"confirmations": depth,
+	}).Warn("Emergency deleverage confirmed, emergency mode entered")
+}
+
+// ClearEmergencyMode leaves emergency mode once an operator has confirmed the
+// positions are healthy again, reporting whether it was set. The cleared mode
+// is saved before it takes effect, so a failed save leaves the bot in
+// emergency mode rather than re-entering it on the next restart.
+func (b *Bot) ClearEmergencyMode() (bool, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if !b.emergencyMode {
+		return false, nil
+	}
+	if err := Save(b.store, keyEmergencyMode, false); err != nil {
+		return true, fmt.Errorf("failed to persist emergency mode: %w", err)
+	}
+	b.emergencyMode = false
+	b.logger.Warn("Emergency mode cleared by operator")
+	return true, nil
+}
+
+// pendingEmergencyList returns the pending emergency deleverages, oldest
+// first; callers must hold b.mutex
+func (b *Bot) pendingEmergencyList() []PendingEmergency {
+	list := make([]PendingEmergency, 0, len(b.pendingEmergencies))
+	for _, pending := range b.pendingEmergencies {
+		list = append(list, pending)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].SentAt.Before(list[j].SentAt) })
+	return list
+}
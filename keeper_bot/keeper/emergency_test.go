@@ -0,0 +1,41 @@
+package keeper
+
+import "testing"
+
+func TestClearEmergencyModeSurvivesRestart(t *testing.T) {
+	store := NewMemoryStore()
+	if err := Save(store, keyEmergencyMode, true); err != nil {
+		t.Fatal(err)
+	}
+
+	bot, _ := newTestBot(t, testConfig(t), store)
+	if err := bot.restoreState(); err != nil {
+		t.Fatalf("restoreState: %v", err)
+	}
+	if !bot.Status().EmergencyMode {
+		t.Fatal("emergency mode not restored")
+	}
+
+	cleared, err := bot.ClearEmergencyMode()
+	if err != nil || !cleared {
+		t.Fatalf("ClearEmergencyMode = %t, %v, want true, nil", cleared, err)
+	}
+	if bot.Status().EmergencyMode {
+		t.Error("emergency mode still set after clearing")
+	}
+	if saved, ok, err := Load(store, keyEmergencyMode); err != nil || !ok || saved {
+		t.Errorf("saved emergency mode = %t (found %t, %v), want false", saved, ok, err)
+	}
+
+	restarted, _ := newTestBot(t, testConfig(t), store)
+	if err := restarted.restoreState(); err != nil {
+		t.Fatalf("restoreState: %v", err)
+	}
+	if restarted.Status().EmergencyMode {
+		t.Error("emergency mode re-entered after restart")
+	}
+
+	if cleared, err := restarted.ClearEmergencyMode(); err != nil || cleared {
+		t.Errorf("ClearEmergencyMode when not set = %t, %v, want false, nil", cleared, err)
+	}
+}
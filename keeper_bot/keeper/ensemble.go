@@ -0,0 +1,208 @@
+package keeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrEnsembleUnavailable is returned when no ensemble model answered
+var ErrEnsembleUnavailable = errors.New("no ensemble model answered")
+
+// modelAssessment is one ensemble model's leverage assessment
+type modelAssessment struct {
+	model    string
+	weight   float64
+	response *LeverageHealthResponse
+	err      error
+}
+
+// ensembleEnabled reports whether leverage is assessed by a model ensemble
+func (b *Bot) ensembleEnabled() bool {
+	return len(b.cfg().MLEnsembleEndpoints) > 0
+}
+
+// validateEnsemble checks every ensemble weight is positive and names a
+// configured model
+func validateEnsemble(config *Config) error {
+	for model, weight := range config.MLEnsembleWeights {
+		if _, ok := config.MLEnsembleEndpoints[model]; !ok {
+			return fmt.Errorf("ML ensemble weight for unknown model %q", model)
+		}
+		if weight <= 0 {
+			return fmt.Errorf("ML ensemble weight for %q must be positive, got %v", model, weight)
+		}
+	}
+	if config.MLEnsembleQuorum > len(config.MLEnsembleEndpoints) {
+		return fmt.Errorf("ML ensemble quorum %d exceeds the %d configured models", config.MLEnsembleQuorum, len(config.MLEnsembleEndpoints))
+	}
+	return nil
+}
+
+// assessLeverage requests a leverage assessment from the ML engine, or from
+// every ensemble model combined when an ensemble is configured. The result
+// is the raw response JSON either way, so decisions built on it replay alike.
+func (b *Bot) assessLeverage(ctx context.Context, request LeverageHealthRequest) ([]byte, error) {
+	config := b.cfg()
+	if !b.ensembleEnabled() {
+		return b.callMLAPI(ctx, "leverage-health", request)
+	}
+
+	models := b.queryEnsemble(ctx, request)
+	for _, m := range models {
+		entry := b.logger.WithFields(logrus.Fields{"model": m.model, "weight": m.weight})
+		if m.err != nil {
+			entry.WithError(m.err).Warn("Ensemble model assessment failed")
+			continue
+		}
+		entry.WithFields(logrus.Fields{
+			"risk_score":      b.score(m.response.CompositeRiskScore),
+			"risk_level":      m.response.RiskLevel,
+			"recommendations": m.response.Recommendations,
+		}).Info("Ensemble model assessment")
+	}
+
+	combined, votes, err := combineAssessments(models, config.MLEnsembleQuorum)
+	if err != nil {
+		return nil, err
+	}
+	b.logger.WithFields(logrus.Fields{
+		"risk_score":      b.score(combined.CompositeRiskScore),
+		"risk_level":      combined.RiskLevel,
+		"recommendations": combined.Recommendations,
+		"emergency_votes": votes,
+		"quorum":          emergencyQuorum(len(models), config.MLEnsembleQuorum),
+	}).Info("Ensemble assessment combined")
+	return json.Marshal(combined)
+}
+
+// queryEnsemble asks every ensemble model concurrently, returning their
+// assessments ordered by model name
+func (b *Bot) queryEnsemble(ctx context.Context, request LeverageHealthRequest) []modelAssessment {
+	config := b.cfg()
+	models := make([]modelAssessment, 0, len(config.MLEnsembleEndpoints))
+	for model := range config.MLEnsembleEndpoints {
+		weight, ok := config.MLEnsembleWeights[model]
+		if !ok {
+			weight = 1
+		}
+		models = append(models, modelAssessment{model: model, weight: weight})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].model < models[j].model })
+
+	var wg sync.WaitGroup
+	for i := range models {
+		wg.Add(1)
+		go func(m *modelAssessment) {
+			defer wg.Done()
+			raw, err := b.callMLAPIAt(ctx, config.MLEnsembleEndpoints[m.model], "leverage-health", request)
+			if err != nil {
+				m.err = err
+				return
+			}
+			var response LeverageHealthResponse
+			if err := json.Unmarshal(raw, &response); err != nil {
+				m.err = fmt.Errorf("failed to parse ML response: %w", err)
+				return
+			}
+			m.response = &response
+		}(&models[i])
+	}
+	wg.Wait()
+	return models
+}
+
+// emergencyQuorum is the number of models that must recommend an emergency
+// deleverage; 0 configured means a majority of all models
+func emergencyQuorum(models, configured int) int {
+	if configured > 0 {
+		return configured
+	}
+	return models/2 + 1
+}
+
+// combineAssessments merges the answering models' assessments. Scores and
+// confidences are weight-averaged; the risk level, action_required and
+// ordinary recommendations follow the weighted majority. EMERGENCY_DELEVERAGE
+// is kept only if at least quorum models recommend it, counting models that
+// failed as disagreeing. The oldest timestamp is kept so staleness checks
+// see the weakest input. It also returns the emergency vote count.
+func combineAssessments(models []modelAssessment, quorum int) (*LeverageHealthResponse, int, error) {
+	var answered []modelAssessment
+	var totalWeight float64
+	for _, m := range models {
+		if m.err == nil {
+			answered = append(answered, m)
+			totalWeight += m.weight
+		}
+	}
+	if len(answered) == 0 {
+		return nil, 0, ErrEnsembleUnavailable
+	}
+
+	combined := &LeverageHealthResponse{}
+	var confidence, actionWeight float64
+	levelWeight := make(map[string]float64)
+	recWeight := make(map[string]float64)
+	recVotes := make(map[string]int)
+	for _, m := range answered {
+		r := m.response
+		combined.CompositeRiskScore += m.weight * r.CompositeRiskScore / totalWeight
+		modelConfidence := 1.0
+		if r.Confidence != nil {
+			modelConfidence = *r.Confidence
+		}
+		confidence += m.weight * modelConfidence / totalWeight
+		levelWeight[r.RiskLevel] += m.weight
+		if r.ActionRequired {
+			actionWeight += m.weight
+		}
+		for _, recommendation := range uniqueStrings(r.Recommendations) {
+			recWeight[recommendation] += m.weight
+			recVotes[recommendation]++
+		}
+		if combined.Timestamp == 0 || (r.Timestamp != 0 && r.Timestamp < combined.Timestamp) {
+			combined.Timestamp = r.Timestamp
+		}
+	}
+	combined.Confidence = &confidence
+	combined.ActionRequired = actionWeight*2 > totalWeight
+
+	var bestLevel float64
+	for level, weight := range levelWeight {
+		if weight > bestLevel || (weight == bestLevel && level < combined.RiskLevel) {
+			combined.RiskLevel, bestLevel = level, weight
+		}
+	}
+
+	votes := recVotes[RecEmergencyDeleverage]
+	for recommendation, weight := range recWeight {
+		keep := weight*2 > totalWeight
+		if recommendation == RecEmergencyDeleverage {
+			keep = votes >= emergencyQuorum(len(models), quorum)
+		}
+		if keep {
+			combined.Recommendations = append(combined.Recommendations, recommendation)
+		}
+	}
+	sort.Strings(combined.Recommendations)
+	return combined, votes, nil
+}
+
+// uniqueStrings drops repeated values, keeping the first occurrence
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
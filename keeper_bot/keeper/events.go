@@ -0,0 +1,166 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	eventMinBackoff = time.Second
+	eventMaxBackoff = time.Minute
+
+	// eventCatchUpInterval is how often the reorg-safe cursor scan runs while subscribed
+	eventCatchUpInterval = time.Minute
+)
+
+// DefaultTriggerEvents are the LeveragedRWAStrategy events that change position health
+var DefaultTriggerEvents = []string{
+	"HealthFactorUpdated(uint256,uint256)",
+	"StablecoinBorrowed(uint256,uint256)",
+	"LeverageReduced(uint256,string)",
+}
+
+// watchStrategyEvents subscribes to strategy events and triggers leverage
+// monitoring out of band, resubscribing with backoff whenever the subscription drops
+func (b *Bot) watchStrategyEvents(ctx context.Context) {
+	trigger := make(chan struct{}, 1)
+	b.goBackground(func(ctx context.Context) { b.runEventTriggeredMonitor(ctx, trigger) })
+
+	backoff := eventMinBackoff
+	for {
+		subscribed, err := b.subscribeStrategyEvents(ctx, trigger)
+		if ctx.Err() != nil {
+			return
+		}
+		b.noteRPCError(err)
+		if subscribed {
+			backoff = eventMinBackoff
+		}
+
+		b.logger.WithError(err).WithField("retry_in", backoff.String()).Warn("Strategy event subscription dropped")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > eventMaxBackoff {
+			backoff = eventMaxBackoff
+		}
+	}
+}
+
+// strategyEventQuery filters the configured trigger events on all strategies
+func (b *Bot) strategyEventQuery() ethereum.FilterQuery {
+	config := b.cfg()
+	topics := make([]common.Hash, 0, len(config.TriggerEvents))
+	for _, signature := range config.TriggerEvents {
+		topics = append(topics, crypto.Keccak256Hash([]byte(signature)))
+	}
+
+	return ethereum.FilterQuery{
+		Addresses: b.leveragedStrategies,
+		Topics:    [][]common.Hash{topics},
+	}
+}
+
+// catchUpStrategyEvents scans from the persisted cursor to the reorg-safe
+// head, triggering monitoring if events were missed (e.g. while the
+// subscription was down)
+func (b *Bot) catchUpStrategyEvents(ctx context.Context, trigger chan<- struct{}) {
+	err := b.scanEvents(ctx, "strategy_events", b.strategyEventQuery(), func(logs []types.Log) error {
+		b.logger.WithField("events", len(logs)).Info("Catch-up scan found strategy events")
+		fireTrigger(trigger)
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		b.logger.WithError(err).Warn("Strategy event catch-up scan failed")
+	}
+}
+
+// fireTrigger requests a monitoring run; a pending trigger already covers it
+func fireTrigger(trigger chan<- struct{}) {
+	select {
+	case trigger <- struct{}{}:
+	default:
+	}
+}
+
+// subscribeStrategyEvents forwards matching logs to trigger until the subscription fails
+func (b *Bot) subscribeStrategyEvents(ctx context.Context, trigger chan<- struct{}) (bool, error) {
+	query := b.strategyEventQuery()
+
+	logs := make(chan types.Log)
+	sub, err := b.eth().SubscribeFilterLogs(ctx, query, logs)
+	if err != nil {
+		return false, fmt.Errorf("failed to subscribe to strategy events: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	b.logger.WithField("events", b.cfg().TriggerEvents).Info("Subscribed to strategy events")
+
+	// Recover anything missed before (re)subscribing, then keep the cursor moving
+	b.catchUpStrategyEvents(ctx, trigger)
+	catchUp := time.NewTicker(eventCatchUpInterval)
+	defer catchUp.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case err := <-sub.Err():
+			return true, err
+		case <-catchUp.C:
+			b.catchUpStrategyEvents(ctx, trigger)
+		case vLog := <-logs:
+			b.logger.WithFields(logrus.Fields{
+				"block": vLog.BlockNumber,
+				"tx":    vLog.TxHash.Hex(),
+			}).Debug("Strategy event received")
+			fireTrigger(trigger)
+		}
+	}
+}
+
+// runEventTriggeredMonitor runs one leverage assessment per burst of events.
+// Events arriving within EventDebounce of the first are coalesced, and runs
+// are serialized so a burst never launches overlapping assessments.
+func (b *Bot) runEventTriggeredMonitor(ctx context.Context, trigger <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-trigger:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.cfg().EventDebounce):
+		}
+
+		// Drop any trigger that arrived during the debounce window
+		select {
+		case <-trigger:
+		default:
+		}
+
+		b.logger.Info("Strategy event triggered leverage monitoring")
+		if b.monitorPaused(MonitorLeverage) {
+			continue
+		}
+		b.runCycle(ctx, "leverage_event", func(ctx context.Context) error {
+			results, err := b.MonitorLeverageStrategy(ctx)
+			b.recordRun(MonitorRun{Monitor: "leverage_event", Leverage: results}, err)
+			return err
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package keeper
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// fakeChainService answers the eth_ JSON-RPC methods a keeper without
+// contracts needs to start: an empty chain at a fixed height with a funded
+// keeper and no pending transactions
+type fakeChainService struct {
+	chainID *big.Int
+}
+
+func (s *fakeChainService) ChainId() *hexutil.Big { return (*hexutil.Big)(s.chainID) }
+
+func (s *fakeChainService) BlockNumber() hexutil.Uint64 { return 100 }
+
+func (s *fakeChainService) GetBlockByNumber(number string, full bool) (json.RawMessage, error) {
+	header := &types.Header{
+		Number:     big.NewInt(100),
+		Time:       uint64(time.Now().Unix()),
+		Difficulty: new(big.Int),
+		BaseFee:    big.NewInt(1),
+	}
+	return json.Marshal(header)
+}
+
+func (s *fakeChainService) GetBalance(account common.Address, block string) *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil))
+}
+
+func (s *fakeChainService) GetTransactionCount(account common.Address, block string) hexutil.Uint64 {
+	return 0
+}
+
+func (s *fakeChainService) GetCode(account common.Address, block string) hexutil.Bytes { return nil }
+
+func (s *fakeChainService) GasPrice() *hexutil.Big { return (*hexutil.Big)(big.NewInt(1_000_000_000)) }
+
+// newFakeChain starts a JSON-RPC node serving fakeChainService over a
+// websocket, which unlike HTTP holds a connection open until the client
+// closes it. The node is closed when the test ends; dial it at wsURL.
+func newFakeChain(t *testing.T, chainID int64) (node *httptest.Server, wsURL string) {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &fakeChainService{chainID: big.NewInt(chainID)}); err != nil {
+		t.Fatal(err)
+	}
+	node = httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	t.Cleanup(func() {
+		node.Close()
+		server.Stop()
+	})
+	return node, "ws" + strings.TrimPrefix(node.URL, "http")
+}
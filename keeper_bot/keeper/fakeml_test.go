@@ -0,0 +1,224 @@
+package keeper
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ML engine endpoints served by fakeMLServer. The prediction endpoints are
+// relative to fakeMLBasePath, as Config.MLAPIBasePath makes them for the keeper.
+const (
+	fakeMLLeverageHealth = "leverage-health"
+	fakeMLNAVPrediction  = "invoice-nav-prediction"
+	fakeMLKYCRisk        = "kyc-risk-assessment"
+	fakeMLHealth         = "health"
+	fakeMLModelInfo      = "model-info"
+)
+
+// fakeMLBasePath is the prediction API prefix the fake serves
+const fakeMLBasePath = "/api/v1"
+
+// fakeMLResponse builds a response body per request, for responses that must
+// vary such as fresh timestamps
+type fakeMLResponse func(r *http.Request) interface{}
+
+// fakeMLFault is a failure injected into an endpoint's responses
+type fakeMLFault struct {
+	// Delay is waited before responding, or until the client gives up
+	Delay time.Duration
+	// Status replaces the 200 response status when non-zero
+	Status int
+	// BadJSON sends a malformed body instead of the configured response
+	BadJSON bool
+	// Times is how many requests the fault applies to; 0 means all of them
+	Times int
+}
+
+// fakeMLRequest is a request received by the fake
+type fakeMLRequest struct {
+	Endpoint string
+	Method   string
+	Header   http.Header
+	Body     []byte
+}
+
+// decode unmarshals the request body into v
+func (r fakeMLRequest) decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// fakeMLServer is an httptest server standing in for the ML engine. It
+// serves canned responses for the three prediction endpoints, /health and
+// /model-info, applies injected faults, and records every request.
+type fakeMLServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses map[string]interface{}
+	faults    map[string][]fakeMLFault
+	requests  []fakeMLRequest
+}
+
+// newFakeMLServer starts a fake ML engine, closed when the test ends.
+// responses maps endpoint names to a JSON-encodable body or a
+// fakeMLResponse, overriding the healthy defaults. Point
+// Config.MLAPIEndpoint at the server's URL; the default MLAPIBasePath
+// matches fakeMLBasePath.
+func newFakeMLServer(t *testing.T, responses map[string]interface{}) *fakeMLServer {
+	t.Helper()
+	f := &fakeMLServer{
+		responses: defaultFakeMLResponses(),
+		faults:    make(map[string][]fakeMLFault),
+	}
+	for endpoint, response := range responses {
+		f.responses[endpoint] = response
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// setResponse replaces the response of an endpoint
+func (f *fakeMLServer) setResponse(endpoint string, response interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[endpoint] = response
+}
+
+// injectFault queues a fault on an endpoint. Faults apply in the order they
+// were injected, each until its Times is used up.
+func (f *fakeMLServer) injectFault(endpoint string, fault fakeMLFault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults[endpoint] = append(f.faults[endpoint], fault)
+}
+
+// requestsTo returns the requests received by an endpoint, oldest first; an
+// empty endpoint returns every request
+func (f *fakeMLServer) requestsTo(endpoint string) []fakeMLRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []fakeMLRequest
+	for _, req := range f.requests {
+		if endpoint == "" || req.Endpoint == endpoint {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+// serve records a request and answers it with its endpoint's fault or
+// response
+func (f *fakeMLServer) serve(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, fakeMLBasePath), "/")
+	body, _ := io.ReadAll(r.Body)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, fakeMLRequest{
+		Endpoint: endpoint,
+		Method:   r.Method,
+		Header:   r.Header.Clone(),
+		Body:     body,
+	})
+	response, ok := f.responses[endpoint]
+	fault, faulted := f.nextFault(endpoint)
+	f.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if faulted && fault.Delay > 0 {
+		select {
+		case <-time.After(fault.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if faulted && fault.Status != 0 {
+		w.WriteHeader(fault.Status)
+	}
+	if faulted && fault.BadJSON {
+		io.WriteString(w, `{"malformed":`)
+		return
+	}
+	if build, ok := response.(fakeMLResponse); ok {
+		response = build(r)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// nextFault takes the next fault queued on an endpoint; callers must hold mu
+func (f *fakeMLServer) nextFault(endpoint string) (fakeMLFault, bool) {
+	queue := f.faults[endpoint]
+	if len(queue) == 0 {
+		return fakeMLFault{}, false
+	}
+
+	fault := queue[0]
+	if fault.Times > 0 {
+		queue[0].Times--
+		if queue[0].Times == 0 {
+			f.faults[endpoint] = queue[1:]
+		}
+	}
+	return fault, true
+}
+
+// defaultFakeMLResponses are healthy, low-risk, fresh answers for every endpoint
+func defaultFakeMLResponses() map[string]interface{} {
+	return map[string]interface{}{
+		fakeMLLeverageHealth: fakeMLResponse(func(*http.Request) interface{} {
+			return map[string]interface{}{
+				"composite_risk_score": 0.2,
+				"risk_level":           "LOW",
+				"action_required":      false,
+				"recommendations":      []string{},
+				"confidence":           0.9,
+				"timestamp":            time.Now().Unix(),
+			}
+		}),
+		fakeMLNAVPrediction: fakeMLResponse(func(*http.Request) interface{} {
+			return map[string]interface{}{
+				"predicted_nav":            "1.000000",
+				"confidence":               0.9,
+				"expected_collection_rate": 0.98,
+				"risk_adjusted_yield":      0.08,
+				"timestamp":                time.Now().Unix(),
+			}
+		}),
+		fakeMLKYCRisk: fakeMLResponse(func(*http.Request) interface{} {
+			return map[string]interface{}{
+				"kyc_risk_score":        0.1,
+				"risk_classification":   "LOW_RISK",
+				"verification_required": false,
+				"compliance_flags":      []string{},
+				"timestamp":             time.Now().Unix(),
+			}
+		}),
+		fakeMLHealth: fakeMLResponse(func(*http.Request) interface{} {
+			return map[string]interface{}{
+				"status":        "healthy",
+				"model_version": "fake",
+				"timestamp":     time.Now().Format(time.RFC3339),
+			}
+		}),
+		fakeMLModelInfo: fakeMLResponse(func(*http.Request) interface{} {
+			return map[string]interface{}{
+				"model_version":  "fake",
+				"schema_version": "1.0",
+				"trained_at":     time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+			}
+		}),
+	}
+}
@@ -0,0 +1,71 @@
+package keeper
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+// fallbackRiskLevel marks assessments made locally without the ML engine
+const fallbackRiskLevel = "FALLBACK"
+
+// fallbackAssessment derives a conservative risk signal from on-chain data
+// alone: a health factor below MinHealthFactor or an LTV above MaxLTV
+// recommends reducing leverage. It never escalates to an emergency deleverage,
+// which stays reserved for ML-backed decisions.
+func (b *Bot) fallbackAssessment(position *StrategyPosition) (*LeverageHealthResponse, []string) {
+	reasons := b.positionBreaches(position)
+	assessment := &LeverageHealthResponse{RiskLevel: fallbackRiskLevel}
+	if len(reasons) > 0 {
+		assessment.ActionRequired = true
+		assessment.Recommendations = []string{RecReduceLeverage}
+	}
+	return assessment, reasons
+}
+
+// positionBreaches lists the on-chain limits a position breaches. A position
+// without debt has no meaningful health factor and breaches none.
+func (b *Bot) positionBreaches(position *StrategyPosition) []string {
+	config := b.cfg()
+	if position.TotalBorrowed == 0 {
+		return nil
+	}
+	var reasons []string
+	if position.HealthFactor < config.MinHealthFactor {
+		reasons = append(reasons, "health_factor_below_minimum")
+	}
+	if position.LTV > config.MaxLTV {
+		reasons = append(reasons, "ltv_above_maximum")
+	}
+	return reasons
+}
+
+// applyFallbackPolicy acts on the fallback assessment for a strategy whose
+// ML assessment failed
+func (b *Bot) applyFallbackPolicy(ctx context.Context, strategy common.Address, position *StrategyPosition, decision *leverageDecision, result *LeverageResult) error {
+	assessment, reasons := decision.assessment, decision.fallbackReasons
+
+	logger := b.logger.WithFields(logrus.Fields{
+		"strategy":      strategy.Hex(),
+		"health_factor": b.score(position.HealthFactor),
+		"ltv":           b.score(position.LTV),
+		"reasons":       reasons,
+	})
+	b.metrics.AddCounter(metricFallbackDecisions, 1, "strategy", strategy.Hex(), "action_required", boolLabel(assessment.ActionRequired))
+
+	if !assessment.ActionRequired {
+		logger.Warn("FALLBACK POLICY: ML engine unavailable, on-chain position within limits, no action")
+		return nil
+	}
+	logger.Warn("FALLBACK POLICY: ML engine unavailable, reducing leverage from on-chain data")
+	return b.executeRiskActions(ctx, strategy, decision, result)
+}
+
+// boolLabel formats a bool as a metric label value
+func boolLabel(v bool) string {
+	if v {
+		return "true"
+	}
+	return "false"
+}
@@ -0,0 +1,56 @@
+package keeper
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/sirupsen/logrus"
+)
+
+// roundTo rounds v half away from zero to decimals places; a negative
+// decimals leaves v at full precision
+func roundTo(v float64, decimals int) float64 {
+	if decimals < 0 {
+		return v
+	}
+	scale := math.Pow10(decimals)
+	return math.Round(v*scale) / scale
+}
+
+// score rounds a risk score, confidence, health factor or LTV to
+// ScorePrecision for logs, results and metrics
+func (b *Bot) score(v float64) float64 {
+	return roundTo(v, b.cfg().ScorePrecision)
+}
+
+// scorePtr is score for an optional value
+func (b *Bot) scorePtr(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	rounded := b.score(*v)
+	return &rounded
+}
+
+// formatNAV formats an exact NAV in human units with NAVPrecision decimals
+func (b *Bot) formatNAV(nav *big.Rat) string {
+	config := b.cfg()
+	if config.NAVPrecision < 0 {
+		return formatRat(nav)
+	}
+	return nav.FloatString(config.NAVPrecision)
+}
+
+// formatNAVWei formats a NAV as the on-chain fixed-point amount
+func formatNAVWei(nav *big.Rat) string {
+	return toFixedPoint(nav, navDecimals).String()
+}
+
+// navFields are log fields reporting a NAV under name in human units and
+// under name_wei as the on-chain amount
+func (b *Bot) navFields(name string, nav *big.Rat) logrus.Fields {
+	return logrus.Fields{
+		name:          b.formatNAV(nav),
+		name + "_wei": formatNAVWei(nav),
+	}
+}
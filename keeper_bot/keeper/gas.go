@@ -0,0 +1,59 @@
+package keeper
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// TxUrgency selects the gas pricing policy of a transaction
+type TxUrgency int
+
+const (
+	// UrgencyRoutine is used by scheduled maintenance such as NAV updates
+	UrgencyRoutine TxUrgency = iota
+	// UrgencyEmergency is used by safety actions that must land promptly
+	UrgencyEmergency
+)
+
+// String implements fmt.Stringer
+func (u TxUrgency) String() string {
+	if u == UrgencyEmergency {
+		return "emergency"
+	}
+	return "routine"
+}
+
+// urgencyOf returns the urgency of a keeper action
+func urgencyOf(action string) TxUrgency {
+	if isEmergencyAction(action) {
+		return UrgencyEmergency
+	}
+	return UrgencyRoutine
+}
+
+// gasPrice applies the urgency's multiplier and cap to a suggested gas
+// price. Routine transactions are capped by MaxGasPrice; emergency ones may
+// exceed it up to EmergencyMaxGasPrice.
+func (b *Bot) gasPrice(suggested *big.Int, urgency TxUrgency) *big.Int {
+	config := b.cfg()
+	if urgency == UrgencyEmergency {
+		return scaleGasPrice(suggested, config.EmergencyGasMultiplier, config.EmergencyMaxGasPrice)
+	}
+	return scaleGasPrice(suggested, config.RoutineGasMultiplier, config.MaxGasPrice)
+}
+
+// scaleGasPrice multiplies a gas price, rounding down, and caps the result;
+// a non-positive multiplier leaves the price unchanged and a nil or zero cap
+// disables capping. The multiplier is taken at its shortest decimal form, so
+// 1.2 scales by exactly 6/5 rather than the binary float just below it.
+func scaleGasPrice(price *big.Int, multiplier float64, limit *big.Int) *big.Int {
+	scaled := new(big.Int).Set(price)
+	if factor, ok := new(big.Rat).SetString(strconv.FormatFloat(multiplier, 'f', -1, 64)); ok && multiplier > 0 {
+		scaled.Mul(scaled, factor.Num())
+		scaled.Quo(scaled, factor.Denom())
+	}
+	if limit != nil && limit.Sign() > 0 && scaled.Cmp(limit) > 0 {
+		scaled.Set(limit)
+	}
+	return scaled
+}
@@ -0,0 +1,80 @@
+package keeper
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestUrgencyOf(t *testing.T) {
+	tests := []struct {
+		action string
+		want   TxUrgency
+	}{
+		{"emergency_deleverage", UrgencyEmergency},
+		{"emergency_deleverage" + approvalSuffix, UrgencyEmergency},
+		{"reduce_leverage", UrgencyRoutine},
+		{"reduce_leverage" + approvalSuffix, UrgencyRoutine},
+		{"nav_update", UrgencyRoutine},
+		{"mark_impaired", UrgencyRoutine},
+		{"balance_refill", UrgencyRoutine},
+	}
+	for _, tt := range tests {
+		if got := urgencyOf(tt.action); got != tt.want {
+			t.Errorf("urgencyOf(%q) = %s, want %s", tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestGasPrice(t *testing.T) {
+	const gwei = 1_000_000_000
+	config := testConfig(t)
+	config.RoutineGasMultiplier = 1.2
+	config.MaxGasPrice = big.NewInt(5 * gwei)
+	config.EmergencyGasMultiplier = 2
+	config.EmergencyMaxGasPrice = big.NewInt(20 * gwei)
+	bot, _ := newTestBot(t, config, nil)
+
+	tests := []struct {
+		name      string
+		action    string
+		suggested int64
+		want      int64
+	}{
+		{"routine multiplier", "nav_update", 2 * gwei, 2_400_000_000},
+		{"routine capped at MaxGasPrice", "reduce_leverage", 6 * gwei, 5 * gwei},
+		{"emergency multiplier above MaxGasPrice", "emergency_deleverage", 4 * gwei, 8 * gwei},
+		{"emergency capped at EmergencyMaxGasPrice", "emergency_deleverage", 15 * gwei, 20 * gwei},
+		{"emergency approval priced as emergency", "emergency_deleverage" + approvalSuffix, 4 * gwei, 8 * gwei},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bot.gasPrice(big.NewInt(tt.suggested), urgencyOf(tt.action))
+			if got.Cmp(big.NewInt(tt.want)) != 0 {
+				t.Fatalf("gasPrice(%d, %s) = %s, want %d", tt.suggested, tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaleGasPrice(t *testing.T) {
+	tests := []struct {
+		name       string
+		price      int64
+		multiplier float64
+		limit      *big.Int
+		want       int64
+	}{
+		{"non-positive multiplier leaves price", 100, 0, nil, 100},
+		{"rounds down", 101, 1.5, nil, 151},
+		{"zero cap disables capping", 100, 3, big.NewInt(0), 300},
+		{"nil cap disables capping", 100, 3, nil, 300},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scaleGasPrice(big.NewInt(tt.price), tt.multiplier, tt.limit)
+			if got.Cmp(big.NewInt(tt.want)) != 0 {
+				t.Fatalf("scaleGasPrice(%d, %g, %v) = %s, want %d", tt.price, tt.multiplier, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
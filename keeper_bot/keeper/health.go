@@ -0,0 +1,136 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrUnhealthyStart is returned by Start when RequireHealthyStart is set and
+// the initial health check does not pass within StartupHealthTimeout
+var ErrUnhealthyStart = errors.New("initial health check did not pass")
+
+// Retries of the initial health check back off between these delays
+const (
+	startupHealthRetryMin = 5 * time.Second
+	startupHealthRetryMax = time.Minute
+)
+
+// CheckResult is the outcome of a single health sub-check
+type CheckResult struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// HealthReport is a snapshot of the bot's dependencies taken by HealthCheck
+type HealthReport struct {
+	CheckedAt time.Time     `json:"checked_at"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+// add records the result of a named sub-check
+func (r *HealthReport) add(name string, err error) {
+	result := CheckResult{Name: name, OK: err == nil}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	r.Checks = append(r.Checks, result)
+}
+
+// Healthy reports whether every sub-check passed
+func (r *HealthReport) Healthy() bool {
+	return len(r.Failed()) == 0
+}
+
+// Failed returns the names of the sub-checks that did not pass
+func (r *HealthReport) Failed() []string {
+	var failed []string
+	for _, check := range r.Checks {
+		if !check.OK {
+			failed = append(failed, check.Name)
+		}
+	}
+	return failed
+}
+
+// LastHealthReport returns the most recent health report, or nil before the first check
+func (b *Bot) LastHealthReport() *HealthReport {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.lastHealth
+}
+
+// Ready reports whether the bot is able to act: the last health check passed
+// within the configured freshness window and the bot is not shutting down.
+// The returned string explains why the bot is not ready.
+func (b *Bot) Ready() (bool, string) {
+	config := b.cfg()
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.shuttingDown {
+		return false, "shutting down"
+	}
+	if b.lastHealth == nil {
+		return false, "no health check has completed yet"
+	}
+	if !b.lastHealth.Healthy() {
+		return false, "last health check failed"
+	}
+	if config.ReadinessMaxAge > 0 && time.Since(b.lastHealth.CheckedAt) > config.ReadinessMaxAge {
+		return false, "last health check is stale"
+	}
+	return true, ""
+}
+
+// startupHealthCheck runs the initial health check. With RequireHealthyStart
+// it retries with backoff until the check passes or StartupHealthTimeout
+// elapses; otherwise a failure is logged and alerted and the bot starts
+// degraded.
+func (b *Bot) startupHealthCheck(ctx context.Context) error {
+	config := b.cfg()
+	err := b.HealthCheck(ctx)
+	if err == nil {
+		return nil
+	}
+	if !config.RequireHealthyStart {
+		failed := b.LastHealthReport().Failed()
+		b.logger.WithError(err).WithField("failed", failed).Error("STARTING DEGRADED - INITIAL HEALTH CHECK FAILED, monitors may fail until it recovers")
+		b.alerter.Send(Alert{
+			Severity: AlertWarning,
+			Title:    "Keeper started degraded: initial health check failed",
+			Fields:   map[string]interface{}{"failed": strings.Join(failed, ", ")},
+		})
+		return nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, config.StartupHealthTimeout)
+	defer cancel()
+	delay := startupHealthRetryMin
+	for {
+		b.logger.WithError(err).WithFields(logrus.Fields{
+			"retry_in": delay.String(),
+			"timeout":  config.StartupHealthTimeout.String(),
+		}).Warn("Initial health check failed, holding startup")
+
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("%w within %s: %w", ErrUnhealthyStart, config.StartupHealthTimeout, err)
+		case <-time.After(delay):
+		}
+
+		if err = b.HealthCheck(waitCtx); err == nil {
+			b.logger.Info("Initial health check passed, starting")
+			return nil
+		}
+		delay = min(delay*2, startupHealthRetryMax)
+	}
+}
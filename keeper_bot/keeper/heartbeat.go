@@ -0,0 +1,75 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// heartbeatTimeout bounds a single heartbeat ping
+const heartbeatTimeout = 10 * time.Second
+
+// CycleHealth is the monitoring liveness reported on /health
+type CycleHealth struct {
+	// LastSuccess is the last successful monitor run; zero if none yet
+	LastSuccess time.Time
+	// Age is the time since LastSuccess, or since startup before the first
+	Age time.Duration
+	// Healthy is false once Age exceeds MaxCycleAge
+	Healthy bool
+}
+
+// CycleHealth reports how long ago a monitor last completed successfully.
+// It does not take the bot mutex, so it still answers if the bot is stuck.
+func (b *Bot) CycleHealth() CycleHealth {
+	config := b.cfg()
+	health := CycleHealth{Healthy: true}
+	since := b.createdAt
+	if last := b.lastCycleAt.Load(); last != 0 {
+		health.LastSuccess = time.Unix(0, last)
+		since = health.LastSuccess
+	}
+	health.Age = time.Since(since)
+	monitoring := b.leverageEnabled() || b.navEnabled() || b.kycEnabled()
+	if monitoring && config.MaxCycleAge > 0 && health.Age > config.MaxCycleAge {
+		health.Healthy = false
+	}
+	return health
+}
+
+// noteSuccessfulCycle records a successful monitor run and pings the
+// dead man's switch, so the external monitor pages when cycles stop
+func (b *Bot) noteSuccessfulCycle() {
+	b.lastCycleAt.Store(time.Now().UnixNano())
+	if b.cfg().HeartbeatURL == "" {
+		return
+	}
+	b.goBackground(func(ctx context.Context) {
+		if err := b.sendHeartbeat(ctx); err != nil {
+			b.logger.WithError(err).Warn("Heartbeat ping failed")
+		}
+	})
+}
+
+// sendHeartbeat pings HeartbeatURL. The ping outlives shutdown so the last
+// cycle of a --once run is still reported.
+func (b *Bot) sendHeartbeat(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), heartbeatTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.cfg().HeartbeatURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
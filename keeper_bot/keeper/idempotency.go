@@ -0,0 +1,46 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// idempotencyHeader carries the idempotency key on ML engine requests
+const idempotencyHeader = "Idempotency-Key"
+
+type idempotencyKeyCtx struct{}
+
+// idempotencyKey identifies one monitor decision: the same action on the
+// same contract at the same block always yields the same key, so a request
+// replayed after a restart can be recognized by the ML engine and in the
+// audit log
+func idempotencyKey(action string, contract common.Address, block *big.Int) string {
+	return fmt.Sprintf("%s:%s:%s", action, contract.Hex(), block)
+}
+
+// withIdempotencyKey attaches key to ML requests and transactions made with ctx
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// idempotencyKeyFrom returns the key attached to ctx, if any
+func idempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyCtx{}).(string)
+	return key
+}
+
+// resolveBlock pins a block tag (nil for latest) to a concrete block number,
+// so every read in a cycle sees the same state
+func (b *Bot) resolveBlock(ctx context.Context, tag *big.Int) (*big.Int, error) {
+	header, err := retryValue(ctx, b, "header", func(ctx context.Context) (*types.Header, error) {
+		return b.eth().HeaderByNumber(ctx, tag)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve block: %w", err)
+	}
+	return header.Number, nil
+}
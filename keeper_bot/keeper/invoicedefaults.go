@@ -0,0 +1,188 @@
+package keeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+// invoiceKey identifies one invoice of one invoice token
+type invoiceKey struct {
+	token common.Address
+	id    uint64
+}
+
+// invoiceDefaultsEnabled reports whether per-invoice default predictions are requested
+func (b *Bot) invoiceDefaultsEnabled() bool {
+	return b.cfg().InvoiceDefaultThreshold > 0
+}
+
+// validateInvoiceImpairMethod checks InvoiceImpairMethod names an invoice
+// token method taking a single invoice ID
+func validateInvoiceImpairMethod(config *Config, abis contractABIs) error {
+	if config.InvoiceImpairMethod == "" {
+		return nil
+	}
+	method, ok := abis.invoiceToken.Methods[config.InvoiceImpairMethod]
+	if !ok {
+		return fmt.Errorf("invoice impair method %q not in invoice token ABI", config.InvoiceImpairMethod)
+	}
+	if len(method.Inputs) != 1 || method.Inputs[0].Type.String() != "uint256" {
+		return fmt.Errorf("invoice impair method %q must take a single uint256 invoice ID", config.InvoiceImpairMethod)
+	}
+	return nil
+}
+
+// checkInvoiceDefaults predicts the default probability of every invoice in
+// token's pool and flags those above InvoiceDefaultThreshold, returning how
+// many were flagged. Predictions from pages that succeed are acted on even
+// when other pages fail.
+func (b *Bot) checkInvoiceDefaults(ctx context.Context, token common.Address) (int, error) {
+	block, err := b.resolveBlock(ctx, b.navBlock)
+	if err != nil {
+		return 0, err
+	}
+	pool, err := b.callContract(ctx, block, b.abis.invoiceToken, token, "pool")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read pool data: %w", err)
+	}
+	poolID := pool[0].([32]byte)
+	invoices := int(pool[2].(*big.Int).Int64())
+
+	predictions, err := b.predictInvoiceDefaults(ctx, strings.TrimRight(string(poolID[:]), "\x00"), invoices)
+
+	flagged := 0
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, prediction := range predictions {
+		if prediction.DefaultProbability <= b.cfg().InvoiceDefaultThreshold {
+			continue
+		}
+		flagged++
+		if flagErr := b.flagInvoice(ctx, token, block, prediction); flagErr != nil {
+			errs = append(errs, fmt.Errorf("invoice %d: %w", prediction.InvoiceID, flagErr))
+		}
+	}
+	return flagged, errors.Join(errs...)
+}
+
+// predictInvoiceDefaults requests default predictions for a pool's invoices
+// in pages of InvoiceDefaultPageSize, on up to InvoiceDefaultConcurrency
+// workers. Predictions are returned in page order; one failed page does not
+// affect others.
+func (b *Bot) predictInvoiceDefaults(ctx context.Context, poolID string, invoices int) ([]InvoiceDefaultPrediction, error) {
+	config := b.cfg()
+	pageSize := config.InvoiceDefaultPageSize
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	workers := config.InvoiceDefaultConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	pages := (invoices + pageSize - 1) / pageSize
+	results := make([][]InvoiceDefaultPrediction, pages)
+	pageErrs := make([]error, pages)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				req := InvoiceDefaultRequest{PoolID: poolID, Offset: page * pageSize, Limit: pageSize}
+				results[page], pageErrs[page] = b.predictInvoiceDefaultPage(ctx, req)
+				if pageErrs[page] != nil {
+					pageErrs[page] = fmt.Errorf("page at offset %d: %w", req.Offset, pageErrs[page])
+				}
+			}
+		}()
+	}
+
+	for page := 0; page < pages; page++ {
+		jobs <- page
+	}
+	close(jobs)
+	wg.Wait()
+
+	var predictions []InvoiceDefaultPrediction
+	for _, page := range results {
+		predictions = append(predictions, page...)
+	}
+	return predictions, errors.Join(pageErrs...)
+}
+
+// predictInvoiceDefaultPage requests one page of default predictions
+func (b *Bot) predictInvoiceDefaultPage(ctx context.Context, req InvoiceDefaultRequest) ([]InvoiceDefaultPrediction, error) {
+	response, err := b.callMLAPI(ctx, "invoice-default-prediction", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var page InvoiceDefaultResponse
+	if err := json.Unmarshal(response, &page); err != nil {
+		return nil, fmt.Errorf("failed to parse invoice default response: %w", err)
+	}
+	b.observeMLClock(page.Timestamp)
+	return page.Predictions, nil
+}
+
+// flagInvoice alerts on an invoice predicted to default, once per invoice,
+// and marks it impaired with InvoiceImpairMethod when configured. A failed
+// impairment is retried on the next cycle without alerting again.
+func (b *Bot) flagInvoice(ctx context.Context, token common.Address, block *big.Int, prediction InvoiceDefaultPrediction) error {
+	config := b.cfg()
+	key := invoiceKey{token: token, id: prediction.InvoiceID}
+	b.mutex.Lock()
+	impaired, alerted := b.flaggedInvoices[key]
+	b.flaggedInvoices[key] = impaired
+	b.mutex.Unlock()
+
+	logger := b.logger.WithFields(logrus.Fields{
+		"token":               token.Hex(),
+		"invoice_id":          prediction.InvoiceID,
+		"default_probability": b.score(prediction.DefaultProbability),
+	})
+
+	if !alerted {
+		b.metrics.AddCounter(metricInvoiceDefaults, 1, "token", token.Hex())
+		logger.Warn("Invoice predicted to default")
+		b.sendDeduped(fmt.Sprintf("invoice_default:%s:%d", token.Hex(), prediction.InvoiceID), Alert{
+			Severity: AlertWarning,
+			Title:    "Invoice predicted to default",
+			Fields: map[string]interface{}{
+				"token":               token.Hex(),
+				"invoice_id":          prediction.InvoiceID,
+				"default_probability": b.score(prediction.DefaultProbability),
+				"threshold":           config.InvoiceDefaultThreshold,
+			},
+		})
+	}
+	if impaired || config.InvoiceImpairMethod == "" {
+		return nil
+	}
+
+	ctx = withIdempotencyKey(ctx, fmt.Sprintf("%s:%d", idempotencyKey("mark_impaired", token, block), prediction.InvoiceID))
+	invoiceID := new(big.Int).SetUint64(prediction.InvoiceID)
+	tx, err := b.sendTx(ctx, "mark_impaired", b.abis.invoiceToken, token, config.InvoiceImpairMethod, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	b.flaggedInvoices[key] = true
+	b.mutex.Unlock()
+	logger.WithField("tx", tx.Hash().Hex()).Info("Invoice marked impaired")
+	return nil
+}
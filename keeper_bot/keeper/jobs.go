@@ -0,0 +1,236 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ErrUnknownJob is returned when triggering a job that is not scheduled
+var ErrUnknownJob = errors.New("unknown job")
+
+// ErrConsecutiveFailures stops the bot when a monitor reaches
+// MaxConsecutiveFailures with FailureAction shutdown
+var ErrConsecutiveFailures = errors.New("too many consecutive failed runs")
+
+// Actions taken when a monitor reaches MaxConsecutiveFailures
+const (
+	FailureActionShutdown = "shutdown"
+	FailureActionDisable  = "disable"
+)
+
+// Outcomes of a job run reported on /status
+const (
+	JobOutcomeOK     = "ok"
+	JobOutcomeFailed = "failed"
+	JobOutcomePaused = "paused"
+)
+
+// cronJob is a scheduled job: its interval, which a config reload may
+// change, its cron entry and the outcome of its last run
+type cronJob struct {
+	name    string
+	minutes int
+	id      cron.EntryID
+	ctx     context.Context
+	run     func(ctx context.Context) error
+
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastOutcome  string
+	lastError    string
+	// failures counts the runs failed in a row since the last success
+	failures int
+}
+
+// JobStatus is a scheduled job as reported on /status
+type JobStatus struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	// NextRun is when the scheduler next runs the job
+	NextRun      time.Time `json:"next_run"`
+	LastRun      time.Time `json:"last_run,omitzero"`
+	LastDuration string    `json:"last_duration,omitempty"`
+	LastOutcome  string    `json:"last_outcome,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	// ConsecutiveFailures counts the runs failed in a row
+	ConsecutiveFailures int `json:"consecutive_failures"`
+}
+
+// cronSpec turns an interval in minutes into a cron schedule, aligned to the
+// clock when the interval divides an hour or is a whole hour
+func cronSpec(minutes int) string {
+	switch {
+	case minutes < 60 && 60%minutes == 0:
+		return fmt.Sprintf("*/%d * * * *", minutes)
+	case minutes == 60:
+		return "0 * * * *"
+	default:
+		return fmt.Sprintf("@every %dm", minutes)
+	}
+}
+
+// schedule registers a job to run every minutes as a cycle under ctx,
+// remembering it under name so a reload can reschedule it, /status can
+// report it and an operator can trigger it
+func (b *Bot) schedule(ctx context.Context, name string, minutes int, run func(ctx context.Context) error) {
+	job := &cronJob{name: name, minutes: minutes, ctx: ctx, run: run}
+	id, err := b.cron.AddFunc(cronSpec(minutes), func() { b.runJob(job) })
+	if err != nil {
+		b.logger.WithError(err).WithField("job", name).Error("Failed to schedule job")
+		return
+	}
+	job.id = id
+	b.mutex.Lock()
+	b.cronJobs[name] = job
+	b.mutex.Unlock()
+}
+
+// reschedule moves a scheduled job to a new interval, reporting whether it
+// changed; unscheduled (disabled) jobs are left alone
+func (b *Bot) reschedule(name string, minutes int) bool {
+	b.mutex.Lock()
+	job, ok := b.cronJobs[name]
+	b.mutex.Unlock()
+	if !ok || job.minutes == minutes {
+		return false
+	}
+
+	id, err := b.cron.AddFunc(cronSpec(minutes), func() { b.runJob(job) })
+	if err != nil {
+		b.logger.WithError(err).WithField("job", name).Error("Failed to reschedule job")
+		return false
+	}
+	b.cron.Remove(job.id)
+
+	b.mutex.Lock()
+	job.id, job.minutes = id, minutes
+	b.mutex.Unlock()
+	return true
+}
+
+// runJob runs one cycle of a job and records its outcome, acting on a
+// failure streak reaching MaxConsecutiveFailures
+func (b *Bot) runJob(job *cronJob) {
+	paused := b.monitorPaused(job.name)
+	started := time.Now()
+	err := b.runCycle(job.ctx, job.name, job.run)
+
+	outcome := JobOutcomeOK
+	switch {
+	case paused:
+		outcome = JobOutcomePaused
+	case err != nil:
+		outcome = JobOutcomeFailed
+	}
+
+	config := b.cfg()
+	b.mutex.Lock()
+	job.lastRun, job.lastDuration, job.lastOutcome = started, time.Since(started), outcome
+	job.lastError = ""
+	if err != nil {
+		job.lastError = err.Error()
+	}
+	switch outcome {
+	case JobOutcomeOK:
+		job.failures = 0
+	case JobOutcomeFailed:
+		job.failures++
+	}
+	failures, limit, action := job.failures, config.MaxConsecutiveFailures, config.FailureAction
+	b.mutex.Unlock()
+
+	b.metrics.SetGauge(metricConsecutiveFailures, float64(failures), "job", job.name)
+	if outcome == JobOutcomeFailed && limit > 0 && failures == limit {
+		b.onFailureStreak(job.name, failures, action, err)
+	}
+}
+
+// onFailureStreak alerts on a job failing MaxConsecutiveFailures runs in a
+// row and applies FailureAction
+func (b *Bot) onFailureStreak(name string, failures int, action string, err error) {
+	b.alerter.Send(Alert{
+		Severity: AlertCritical,
+		Title:    "Monitor failing repeatedly",
+		Fields: map[string]interface{}{
+			"job":      name,
+			"failures": failures,
+			"action":   action,
+			"error":    err.Error(),
+		},
+	})
+
+	if action == FailureActionDisable {
+		b.mutex.Lock()
+		b.pausedMonitors[name] = true
+		b.mutex.Unlock()
+		b.logger.WithField("monitor", name).Error("Monitor disabled after consecutive failures")
+		return
+	}
+
+	select {
+	case b.halt <- fmt.Errorf("%w: %s failed %d times: %w", ErrConsecutiveFailures, name, failures, err):
+	default: // already stopping
+	}
+}
+
+// validateFailureAction checks a FailureAction setting
+func validateFailureAction(action string) error {
+	switch action {
+	case FailureActionShutdown, FailureActionDisable:
+		return nil
+	default:
+		return fmt.Errorf("invalid failure action %q (want %s or %s)", action, FailureActionShutdown, FailureActionDisable)
+	}
+}
+
+// resetFailures clears the failure streak of a job, as when an operator
+// resumes a disabled monitor; callers must hold mutex
+func (b *Bot) resetFailures(name string) {
+	if job, ok := b.cronJobs[name]; ok {
+		job.failures = 0
+	}
+}
+
+// RunJob starts a scheduled job's cycle now, in the background, without
+// waiting for its next tick. A paused monitor's job stays skipped, and a
+// monitor already running skips the extra cycle as for a scheduled tick.
+func (b *Bot) RunJob(name string) error {
+	b.mutex.Lock()
+	job, ok := b.cronJobs[name]
+	b.mutex.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownJob, name)
+	}
+
+	b.logger.WithField("job", name).Info("Job triggered by operator")
+	b.goBackground(func(context.Context) { b.runJob(job) })
+	return nil
+}
+
+// jobStatuses lists every scheduled job by name; callers must hold mutex
+func (b *Bot) jobStatuses() []JobStatus {
+	jobs := make([]JobStatus, 0, len(b.cronJobs))
+	for _, job := range b.cronJobs {
+		status := JobStatus{
+			Name:        job.name,
+			Schedule:    cronSpec(job.minutes),
+			NextRun:     b.cron.Entry(job.id).Next,
+			LastRun:     job.lastRun,
+			LastOutcome: job.lastOutcome,
+			LastError:   job.lastError,
+
+			ConsecutiveFailures: job.failures,
+		}
+		if !job.lastRun.IsZero() {
+			status.LastDuration = job.lastDuration.Round(time.Millisecond).String()
+		}
+		jobs = append(jobs, status)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
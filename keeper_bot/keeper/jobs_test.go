@@ -0,0 +1,88 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+// leverageJob is a job assessing leverage against the bot's ML engine
+func leverageJob(bot *Bot) *cronJob {
+	return &cronJob{
+		name: MonitorLeverage,
+		ctx:  context.Background(),
+		run: func(ctx context.Context) error {
+			_, err := bot.assessLeverage(ctx, leverageRequest)
+			return err
+		},
+	}
+}
+
+func TestFailingMLEngineTripsFailureStreak(t *testing.T) {
+	t.Run("disable", func(t *testing.T) {
+		bot, ml, sink := fakeMLBot(t, nil)
+		config := *bot.cfg()
+		config.MaxConsecutiveFailures, config.FailureAction = 2, FailureActionDisable
+		bot.config.Store(&config)
+		ml.injectFault(fakeMLLeverageHealth, fakeMLFault{Status: http.StatusInternalServerError})
+		job := leverageJob(bot)
+
+		bot.runJob(job)
+		if bot.monitorPaused(MonitorLeverage) {
+			t.Fatal("monitor disabled after one failure")
+		}
+		bot.runJob(job)
+		if !bot.monitorPaused(MonitorLeverage) {
+			t.Fatal("monitor still enabled after MaxConsecutiveFailures")
+		}
+		bot.alerter.Wait()
+		if got, want := sink.titles(), []string{"Monitor failing repeatedly"}; !slices.Equal(got, want) {
+			t.Fatalf("alerts = %q, want %q", got, want)
+		}
+
+		// A disabled monitor stops calling the engine
+		calls := len(ml.requestsTo(fakeMLLeverageHealth))
+		bot.runJob(job)
+		if got := len(ml.requestsTo(fakeMLLeverageHealth)); got != calls {
+			t.Fatalf("disabled monitor made %d more ML requests", got-calls)
+		}
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		bot, ml, _ := fakeMLBot(t, nil)
+		config := *bot.cfg()
+		config.MaxConsecutiveFailures, config.FailureAction = 2, FailureActionShutdown
+		bot.config.Store(&config)
+		ml.injectFault(fakeMLLeverageHealth, fakeMLFault{Status: http.StatusInternalServerError})
+		job := leverageJob(bot)
+
+		bot.runJob(job)
+		bot.runJob(job)
+		select {
+		case err := <-bot.halt:
+			if !errors.Is(err, ErrConsecutiveFailures) {
+				t.Fatalf("halt err = %v, want %v", err, ErrConsecutiveFailures)
+			}
+		default:
+			t.Fatal("bot not halted after MaxConsecutiveFailures")
+		}
+	})
+
+	t.Run("success resets streak", func(t *testing.T) {
+		bot, ml, _ := fakeMLBot(t, nil)
+		config := *bot.cfg()
+		config.MaxConsecutiveFailures, config.FailureAction = 2, FailureActionDisable
+		config.RetryAttempts = 0
+		bot.config.Store(&config)
+		ml.injectFault(fakeMLLeverageHealth, fakeMLFault{Status: http.StatusInternalServerError, Times: 1})
+		job := leverageJob(bot)
+
+		bot.runJob(job)
+		bot.runJob(job)
+		if job.failures != 0 || bot.monitorPaused(MonitorLeverage) {
+			t.Fatalf("failures = %d, paused = %t after recovering", job.failures, bot.monitorPaused(MonitorLeverage))
+		}
+	})
+}
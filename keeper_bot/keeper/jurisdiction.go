@@ -0,0 +1,177 @@
+package keeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Compliance flags raised locally rather than by the ML engine
+const (
+	// flagBlockedJurisdiction marks investments flagged without an ML call
+	flagBlockedJurisdiction = "BLOCKED_JURISDICTION"
+	// Jurisdiction rule violations, see JurisdictionRule
+	flagJurisdictionCapExceeded = "JURISDICTION_CAP_EXCEEDED"
+	flagJurisdictionTierTooLow  = "JURISDICTION_TIER_TOO_LOW"
+)
+
+// JurisdictionRule is a hard compliance rule for one jurisdiction, applied
+// whatever the ML engine's assessment. Zero fields are not enforced.
+type JurisdictionRule struct {
+	// MaxInvestment caps a single investment amount
+	MaxInvestment float64 `json:"max_investment,omitempty"`
+	// MinTier is the lowest KYC tier allowed to invest
+	MinTier int `json:"min_tier,omitempty"`
+}
+
+// kycCacheEntry is an allowlisted investment's last ML assessment
+type kycCacheEntry struct {
+	resp *KYCRiskResponse
+	at   time.Time
+}
+
+// screenInvestment applies the jurisdiction fast paths before the ML engine.
+// Blocked jurisdictions are flagged high risk immediately; allowlisted ones
+// reuse their last assessment until KYCAllowedReassessInterval has passed.
+// It returns ok false when the investment needs a full ML assessment.
+func (b *Bot) screenInvestment(investment KYCRequest) (*KYCRiskResponse, bool) {
+	config := b.cfg()
+	logger := b.logger.WithFields(logrus.Fields{
+		"jurisdiction": investment.Jurisdiction,
+		"tier":         investment.Tier,
+		"amount":       investment.InvestmentAmount,
+	})
+
+	if containsJurisdiction(config.KYCBlockedJurisdictions, investment.Jurisdiction) {
+		logger.WithField("decision", "blocked").Warn("KYC fast-path decision")
+		return &KYCRiskResponse{
+			KYCRiskScore:         1,
+			RiskClassification:   "HIGH_RISK",
+			VerificationRequired: true,
+			ComplianceFlags:      []string{flagBlockedJurisdiction},
+			Timestamp:            time.Now().Unix(),
+		}, true
+	}
+
+	if !containsJurisdiction(config.KYCAllowedJurisdictions, investment.Jurisdiction) {
+		return nil, false
+	}
+
+	b.mutex.Lock()
+	entry, cached := b.kycCache[kycCacheKey(investment)]
+	b.mutex.Unlock()
+	if !cached || time.Since(entry.at) >= config.KYCAllowedReassessInterval {
+		return nil, false
+	}
+
+	logger.WithFields(logrus.Fields{
+		"decision":       "allowlisted",
+		"assessed_at":    entry.at,
+		"classification": entry.resp.RiskClassification,
+	}).Info("KYC fast-path decision")
+	return entry.resp, true
+}
+
+// rememberAssessment caches a low-risk allowlisted investment's ML
+// assessment so later cycles can skip the round-trip; high-risk results are
+// never cached and keep being re-assessed every cycle
+func (b *Bot) rememberAssessment(investment KYCRequest, resp *KYCRiskResponse) {
+	if !containsJurisdiction(b.cfg().KYCAllowedJurisdictions, investment.Jurisdiction) || resp.RiskClassification == "HIGH_RISK" {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.kycCache[kycCacheKey(investment)] = kycCacheEntry{resp: resp, at: time.Now()}
+}
+
+// assessOrScreen returns the fast-path decision for an investment if one
+// applies, otherwise its ML assessment
+func (b *Bot) assessOrScreen(ctx context.Context, investment KYCRequest) (*KYCRiskResponse, error) {
+	if resp, ok := b.screenInvestment(investment); ok {
+		return resp, nil
+	}
+	resp, err := b.assessInvestment(ctx, investment)
+	if err != nil {
+		return nil, err
+	}
+	b.rememberAssessment(investment, resp)
+	return resp, nil
+}
+
+// loadJurisdictionRules reads a JSON object of JurisdictionRules keyed by
+// jurisdiction code; an empty path means no rules
+func loadJurisdictionRules(path string) (map[string]JurisdictionRule, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KYC rules: %w", err)
+	}
+	var parsed map[string]JurisdictionRule
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("malformed KYC rules file %s: %w", path, err)
+	}
+
+	rules := make(map[string]JurisdictionRule, len(parsed))
+	for jurisdiction, rule := range parsed {
+		if rule.MaxInvestment < 0 || rule.MinTier < 0 {
+			return nil, fmt.Errorf("KYC rule for %s: limits must not be negative", jurisdiction)
+		}
+		rules[strings.ToUpper(jurisdiction)] = rule
+	}
+	return rules, nil
+}
+
+// jurisdictionViolations checks an investment against its jurisdiction's
+// rule, returning a compliance flag per violation
+func (b *Bot) jurisdictionViolations(investment KYCRequest) []string {
+	rule, ok := b.kycRules[strings.ToUpper(investment.Jurisdiction)]
+	if !ok {
+		return nil
+	}
+	var flags []string
+	if rule.MaxInvestment > 0 && investment.InvestmentAmount > rule.MaxInvestment {
+		flags = append(flags, flagJurisdictionCapExceeded)
+	}
+	if rule.MinTier > 0 && investment.Tier < rule.MinTier {
+		flags = append(flags, flagJurisdictionTierTooLow)
+	}
+	return flags
+}
+
+// applyJurisdictionRules adds rule violation flags to an assessment,
+// requiring verification. The assessment is copied, so cached ML results
+// stay unmodified; the ML score and classification are kept as they are.
+func applyJurisdictionRules(resp *KYCRiskResponse, violations []string) *KYCRiskResponse {
+	if len(violations) == 0 {
+		return resp
+	}
+	flagged := *resp
+	flagged.ComplianceFlags = append(slices.Clone(resp.ComplianceFlags), violations...)
+	flagged.VerificationRequired = true
+	return &flagged
+}
+
+// kycCacheKey identifies an investment by its full assessment payload
+func kycCacheKey(investment KYCRequest) string {
+	key, _ := json.Marshal(investment)
+	return string(key)
+}
+
+// containsJurisdiction reports whether a jurisdiction code is listed,
+// ignoring case
+func containsJurisdiction(list []string, jurisdiction string) bool {
+	for _, listed := range list {
+		if strings.EqualFold(listed, jurisdiction) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,95 +4,562 @@ package keeper
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 )
 
 // New creates a new keeper bot instance
 func New(config *Config) (*Bot, error) {
-	client, err := ethclient.Dial(config.MantleRPC)
+	shared, err := newSharedResources(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to Mantle: %w", err)
+		return nil, err
 	}
+	bot, err := newBot(config, shared)
+	if err != nil {
+		if shared.statsd != nil {
+			shared.statsd.Close()
+		}
+		return nil, err
+	}
+	bot.statsd = shared.statsd
+	return bot, nil
+}
+
+// sharedResources are the ML client, metrics registry and alerter shared by
+// every chain's bot
+type sharedResources struct {
+	httpClient *http.Client
+	mlSlots    chan struct{}
+	mlQueue    chan struct{}
+	metrics    *Metrics
+	statsd     *StatsDSink
+	alerter    *Alerter
+}
 
-	privateKey, err := crypto.HexToECDSA(config.PrivateKey)
+// newSharedResources creates the resources shared by the bots of config
+func newSharedResources(config *Config) (*sharedResources, error) {
+	mlConcurrency := config.MLMaxConcurrency
+	if mlConcurrency < 1 {
+		mlConcurrency = 1
+	}
+
+	httpClient, err := newMLHTTPClient(config)
 	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %w", err)
+		return nil, err
 	}
 
-	publicKey := privateKey.Public()
-	publicKeyECDSA := publicKey.(*ecdsa.PublicKey)
-	address := crypto.PubkeyToAddress(*publicKeyECDSA)
+	metrics := NewMetrics()
+	var statsd *StatsDSink
+	if config.StatsDAddr != "" {
+		if statsd, err = NewStatsDSink(config.StatsDAddr); err != nil {
+			return nil, err
+		}
+		metrics.AddSink(statsd)
+	}
+
+	logger, err := newLogger(config)
+	if err != nil {
+		if statsd != nil {
+			statsd.Close()
+		}
+		return nil, err
+	}
 
+	return &sharedResources{
+		httpClient: httpClient,
+		mlSlots:    make(chan struct{}, mlConcurrency),
+		mlQueue:    make(chan struct{}, max(config.MLQueueDepth, 0)),
+		metrics:    metrics,
+		statsd:     statsd,
+		alerter:    NewAlerter(config, logger),
+	}, nil
+}
+
+// newLogger creates a JSON logger at config's LogLevel, labeling every entry
+// with the chain when the keeper runs several
+func newLogger(config *Config) (*logrus.Logger, error) {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 	logger.SetLevel(logrus.InfoLevel)
+	if config.LogLevel != "" {
+		level, err := logrus.ParseLevel(config.LogLevel)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level: %w", err)
+		}
+		logger.SetLevel(level)
+	}
+	if config.ChainName != "" {
+		logger.AddHook(chainHook(config.ChainName))
+	}
+	return logger, nil
+}
+
+// newBot creates the bot for one chain on top of the shared resources. On
+// error it releases whatever it had opened; the shared resources are left to
+// the caller.
+func newBot(config *Config, shared *sharedResources) (_ *Bot, err error) {
+	var closers []func()
+	defer func() {
+		if err != nil {
+			for i := len(closers) - 1; i >= 0; i-- {
+				closers[i]()
+			}
+		}
+	}()
 
-	return &Bot{
-		config:        config,
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := dialChain(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	closers = append(closers, client.Close)
+
+	privateKey, err := loadPrivateKey(config)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := privateKey.Public()
+	publicKeyECDSA := publicKey.(*ecdsa.PublicKey)
+	address := crypto.PubkeyToAddress(*publicKeyECDSA)
+	if err := checkExpectedAddress(config.ExpectedKeeperAddress, address); err != nil {
+		return nil, err
+	}
+
+	maxInFlight := config.MaxInFlightTx
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+
+	logger, err := newLogger(config)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := shared.metrics
+	if config.ChainName != "" {
+		metrics = metrics.WithLabels("chain", config.ChainName)
+	}
+	metrics.SetGauge(metricInFlightTx, 0)
+
+	var privateRelay *rpc.Client
+	if config.PrivateTxRelayURL != "" {
+		privateRelay, err = rpc.Dial(config.PrivateTxRelayURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to private tx relay: %w", err)
+		}
+		closers = append(closers, privateRelay.Close)
+	}
+
+	leverageBlock, err := monitorBlock(config.LeverageBlockTag, config.BlockTag)
+	if err != nil {
+		return nil, fmt.Errorf("invalid leverage block tag: %w", err)
+	}
+	navBlock, err := monitorBlock(config.NAVBlockTag, config.BlockTag)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NAV block tag: %w", err)
+	}
+
+	if err := validateNAVSubmitMode(config.NAVSubmitMode); err != nil {
+		return nil, err
+	}
+	if err := validateReloadable(config); err != nil {
+		return nil, err
+	}
+	if err := validateEnsemble(config); err != nil {
+		return nil, err
+	}
+	if err := validateMLEndpoints(config.MLEndpoints); err != nil {
+		return nil, err
+	}
+
+	abis, err := loadContractABIs(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateInvoiceImpairMethod(config, abis); err != nil {
+		return nil, err
+	}
+	if err := validateDeleverageSlippage(config, abis); err != nil {
+		return nil, err
+	}
+	if config.DeleverageApprovalSpender != "" && !common.IsHexAddress(config.DeleverageApprovalSpender) {
+		return nil, fmt.Errorf("invalid deleverage approval spender %q", config.DeleverageApprovalSpender)
+	}
+
+	kycRules, err := loadJurisdictionRules(config.KYCRulesPath)
+	if err != nil {
+		return nil, err
+	}
+	if severityRank(config.KYCRuleViolationSeverity) == 0 {
+		return nil, fmt.Errorf("invalid KYC rule violation severity %q", config.KYCRuleViolationSeverity)
+	}
+
+	localScorer, err := newLocalScorer(config)
+	if err != nil {
+		return nil, err
+	}
+	if localScorer != nil {
+		closers = append(closers, func() { localScorer.Close() })
+	}
+
+	store, err := newStore(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store: %w", err)
+	}
+	if config.StateBackend != StateBackendFile && config.Profile != ProfileLocal {
+		logger.WithField("profile", config.Profile).Warn("In-memory state backend: alert dedup, the transaction budget and emergency mode reset on restart; set STATE_BACKEND=file")
+	}
+
+	audits, err := OpenAuditLog(config.AuditLogPath)
+	if err != nil {
+		return nil, err
+	}
+	closers = append(closers, func() { audits.Close() })
+
+	decisions, err := OpenDecisionLog(config.DecisionLogPath)
+	if err != nil {
+		return nil, err
+	}
+	closers = append(closers, func() { decisions.Close() })
+
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	closers = append(closers, bgCancel)
+
+	bot := &Bot{
 		client:        client,
+		privateRelay:  privateRelay,
 		privateKey:    privateKey,
 		address:       address,
 		chainID:       big.NewInt(config.ChainID),
 		logger:        logger,
-		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		httpClient:    shared.httpClient, // timeouts are per request, see callMLAPI
 		cron:          cron.New(),
+		cronJobs:      make(map[string]*cronJob),
 		emergencyMode: false,
 
+		pendingEmergencies: make(map[common.Hash]PendingEmergency),
+		createdAt:          time.Now(),
+		bgCtx:              bgCtx,
+		bgCancel:           bgCancel,
+		metrics:            metrics,
+		localScorer:        localScorer,
+		txSlots:            make(chan struct{}, maxInFlight),
+		mlSlots:            shared.mlSlots,
+		mlQueue:            shared.mlQueue,
+		riskActions:        make(map[string]RiskAction),
+		noSubAccounts:      make(map[common.Address]bool),
+		cooldowns:          make(map[cooldownKey]time.Time),
+		kycCache:           make(map[string]kycCacheEntry),
+		kycRules:           kycRules,
+		flaggedInvoices:    make(map[invoiceKey]bool),
+		clockSkewed:        make(map[string]bool),
+		pendingApprovals:   make(map[approvalKey]pendingApproval),
+		warnSamples:        make(map[string]*warnSample),
+		alertsSent:         make(map[string]time.Time),
+		revokedRoles:       make(map[common.Address]string),
+		roleChecks:         make(map[common.Address]roleCheck),
+
+		pausedMonitors: make(map[string]bool),
+		halt:           make(chan error, 1),
+
+		strategyDecimals: make(map[common.Address]strategyTokens),
+		tokenDecimals:    make(map[common.Address]int),
+
+		alerter:          shared.alerter.forChain(config.ChainName, logger),
+		audits:           audits,
+		decisions:        decisions,
+		store:            store,
+		gasSpent:         new(big.Int),
+		gasSpentByAction: make(map[string]*big.Int),
+
+		abis:          abis,
+		leverageBlock: leverageBlock,
+		navBlock:      navBlock,
+
 		// Initialize contract addresses
-		leveragedStrategy: common.HexToAddress(config.LeveragedStrategyAddr),
-		invoiceToken:      common.HexToAddress(config.InvoiceTokenAddr),
-		kycVerifier:       common.HexToAddress(config.KYCVerifierAddr),
-	}, nil
+		leveragedStrategies: parseAddresses(config.LeveragedStrategyAddrs),
+		invoiceTokens:       parseAddresses(config.InvoiceTokenAddrs),
+		kycVerifier:         common.HexToAddress(config.KYCVerifierAddr),
+	}
+	bot.config.Store(config)
+	bot.registerDefaultRiskActions()
+
+	if err := bot.detectMulticall(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := bot.loadTokenDecimals(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := bot.negotiateMLSchema(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := bot.restoreState(); err != nil {
+		return nil, err
+	}
+
+	return bot, nil
 }
 
 // Start starts the keeper bot with scheduled tasks
 func (b *Bot) Start(ctx context.Context) error {
+	config := b.cfg()
 	b.logger.Info("Starting Veritas Keeper Bot...")
-	b.logger.WithField("address", b.address.Hex()).Info("Keeper address")
+	b.mutex.Lock()
+	b.startedAt = time.Now()
+	b.mutex.Unlock()
+	b.logger.WithField("address", b.keeperAddress().Hex()).Info("Keeper address")
+	if config.DryRun {
+		b.logger.Warn("Dry-run mode: transactions will be signed and audited but not broadcast")
+	}
+	if config.StartupGracePeriod > 0 {
+		b.logger.WithField("grace_period", config.StartupGracePeriod.String()).Warn("Startup grace period: monitors will not send transactions until it ends")
+	}
+	b.warnSlippageUnenforced()
 
-	// Schedule tasks
-	b.cron.AddFunc("*/5 * * * *", func() { // Every 5 minutes
-		if err := b.MonitorLeverageStrategy(ctx); err != nil {
-			b.logger.WithError(err).Error("Leverage monitoring failed")
-		}
-	})
+	// Check dependencies before scheduling cycles that rely on them
+	if err := b.startupHealthCheck(ctx); err != nil {
+		b.Close()
+		return err
+	}
 
-	b.cron.AddFunc("*/30 * * * *", func() { // Every 30 minutes
-		if err := b.UpdateInvoiceNAV(ctx); err != nil {
-			b.logger.WithError(err).Error("NAV update failed")
-		}
-	})
+	if err := b.recoverPendingTxs(ctx); err != nil {
+		b.logger.WithError(err).Warn("Pending transaction recovery incomplete")
+	}
 
-	b.cron.AddFunc("*/15 * * * *", func() { // Every 15 minutes
-		if err := b.MonitorKYCCompliance(ctx); err != nil {
-			b.logger.WithError(err).Error("KYC monitoring failed")
-		}
+	// Schedule tasks; monitors without their contract addresses stay off
+	if b.leverageEnabled() {
+		b.schedule(ctx, MonitorLeverage, config.LeverageMonitorInterval, func(ctx context.Context) error {
+			results, err := b.MonitorLeverageStrategy(ctx)
+			b.recordRun(MonitorRun{Monitor: "leverage", Leverage: results}, err)
+			return err
+		})
+	}
+
+	if b.navEnabled() {
+		b.schedule(ctx, MonitorNAV, config.NAVUpdateInterval, func(ctx context.Context) error {
+			results, err := b.UpdateInvoiceNAV(ctx)
+			b.recordRun(MonitorRun{Monitor: "nav", NAV: results}, err)
+			return err
+		})
+	}
+
+	if b.kycEnabled() {
+		b.schedule(ctx, MonitorKYC, config.KYCMonitorInterval, func(ctx context.Context) error {
+			result, err := b.MonitorKYCCompliance(ctx)
+			b.recordRun(MonitorRun{Monitor: "kyc", KYC: result}, err)
+			return err
+		})
+	}
+
+	b.schedule(ctx, "role_check", config.RoleCheckInterval, func(ctx context.Context) error {
+		b.refreshRoles(ctx, true)
+		return nil
 	})
 
-	b.cron.AddFunc("0 * * * *", func() { // Every hour
-		if err := b.HealthCheck(ctx); err != nil {
+	b.schedule(ctx, MonitorHealth, config.HealthCheckInterval, func(ctx context.Context) error {
+		err := b.HealthCheck(ctx)
+		if err != nil {
 			b.logger.WithError(err).Error("Health check failed")
 		}
+		return err
 	})
 
+	b.logger.WithFields(logrus.Fields{
+		"leverage": b.leverageEnabled(),
+		"nav":      b.navEnabled(),
+		"kyc":      b.kycEnabled(),
+	}).Info("Active monitors")
+
 	// Start cron scheduler
 	b.cron.Start()
 
-	// Initial health check
-	b.HealthCheck(ctx)
+	if config.EventTriggerEnabled && b.leverageEnabled() {
+		b.goBackground(b.watchStrategyEvents)
+	}
 
 	// Keep running
-	<-ctx.Done()
+	select {
+	case <-ctx.Done():
+		b.Close()
+		return ctx.Err()
+	case err := <-b.halt:
+		b.Close()
+		return err
+	}
+}
+
+// RunOnce runs each enabled monitor a single time, sequentially, without the
+// cron scheduler or event watcher, and returns the joined monitor errors
+func (b *Bot) RunOnce(ctx context.Context) error {
+	b.logger.WithField("address", b.keeperAddress().Hex()).Info("Running monitors once")
+	if b.cfg().DryRun {
+		b.logger.Warn("Dry-run mode: transactions will be signed and audited but not broadcast")
+	}
+
+	if err := b.recoverPendingTxs(ctx); err != nil {
+		b.logger.WithError(err).Warn("Pending transaction recovery incomplete")
+	}
+
+	var errs []error
+	if b.leverageEnabled() {
+		results, err := b.MonitorLeverageStrategy(ctx)
+		b.recordRun(MonitorRun{Monitor: "leverage", Leverage: results}, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("leverage: %w", err))
+		}
+	}
+	if b.navEnabled() {
+		results, err := b.UpdateInvoiceNAV(ctx)
+		b.recordRun(MonitorRun{Monitor: "nav", NAV: results}, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("nav: %w", err))
+		}
+	}
+	if b.kycEnabled() {
+		result, err := b.MonitorKYCCompliance(ctx)
+		b.recordRun(MonitorRun{Monitor: "kyc", KYC: result}, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("kyc: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops the scheduler and background goroutines, waiting for running
+// cron jobs, transaction watchers and alert deliveries to finish, then
+// closes the logs and the chain and relay connections
+func (b *Bot) Close() {
+	b.mutex.Lock()
+	b.shuttingDown = true
+	b.mutex.Unlock()
 	b.logger.Info("Keeper bot shutting down...")
-	b.cron.Stop()
-	return ctx.Err()
+
+	b.bgCancel()
+	<-b.cron.Stop().Done()
+	b.bgWG.Wait()
+	b.alerter.Wait()
+	if err := b.audits.Close(); err != nil {
+		b.logger.WithError(err).Error("Failed to close audit log")
+	}
+	if err := b.decisions.Close(); err != nil {
+		b.logger.WithError(err).Error("Failed to close decision log")
+	}
+	if b.statsd != nil {
+		b.statsd.Close()
+	}
+	if client := b.eth(); client != nil {
+		client.Close()
+	}
+	if b.privateRelay != nil {
+		b.privateRelay.Close()
+	}
+	if b.localScorer != nil {
+		if err := b.localScorer.Close(); err != nil {
+			b.logger.WithError(err).Error("Failed to close local model")
+		}
+	}
+
+	b.logger.Info("Keeper bot stopped")
+}
+
+// InStartupGrace reports whether the bot is still within StartupGracePeriod
+// of Start, during which monitors run but transactions are not broadcast
+func (b *Bot) InStartupGrace() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.inStartupGrace()
+}
+
+// inStartupGrace is InStartupGrace for callers holding mutex
+func (b *Bot) inStartupGrace() bool {
+	return !b.startedAt.IsZero() && time.Since(b.startedAt) < b.cfg().StartupGracePeriod
+}
+
+// goBackground runs fn in a goroutine tracked for shutdown; fn must return
+// once its context is cancelled
+func (b *Bot) goBackground(fn func(ctx context.Context)) {
+	b.bgWG.Add(1)
+	go func() {
+		defer b.bgWG.Done()
+		fn(b.bgCtx)
+	}()
+}
+
+// restoreState reloads persisted state from the previous run
+func (b *Bot) restoreState() error {
+	budget, ok, err := Load(b.store, keyTxBudget)
+	if err != nil {
+		return err
+	}
+	if ok {
+		b.budget = budget
+	}
+
+	emergency, _, err := Load(b.store, keyEmergencyMode)
+	if err != nil {
+		return err
+	}
+	if emergency {
+		b.emergencyMode = true
+		b.logger.Warn("Recovered emergency mode from previous run")
+	}
+
+	return b.restoreAlertDedup()
+}
+
+// monitorBlock resolves a monitor's block tag, falling back to the default tag
+func monitorBlock(tag, defaultTag string) (*big.Int, error) {
+	if tag == "" {
+		tag = defaultTag
+	}
+	return parseBlockTag(tag)
+}
+
+// parseAddresses converts hex address strings to addresses
+func parseAddresses(hexAddrs []string) []common.Address {
+	addrs := make([]common.Address, 0, len(hexAddrs))
+	for _, hexAddr := range hexAddrs {
+		addrs = append(addrs, common.HexToAddress(hexAddr))
+	}
+	return addrs
+}
+
+// leverageEnabled reports whether any leveraged strategy is configured
+func (b *Bot) leverageEnabled() bool {
+	return len(b.leveragedStrategies) > 0
+}
+
+// navEnabled reports whether any invoice token is configured for NAV updates
+func (b *Bot) navEnabled() bool {
+	return len(b.invoiceTokens) > 0
+}
+
+// kycEnabled reports whether a KYC verifier is configured
+func (b *Bot) kycEnabled() bool {
+	return b.kycVerifier != (common.Address{})
+}
+
+// tryStartRun acquires a monitor's run lock, logging and returning false if a run is already in progress
+func (b *Bot) tryStartRun(monitor string, run *sync.Mutex) bool {
+	if run.TryLock() {
+		return true
+	}
+	b.logger.WithField("monitor", monitor).Warn("Previous run still in progress, skipping trigger")
+	b.metrics.AddCounter(metricMonitorRunsSkipped, 1, "monitor", monitor)
+	return false
 }
@@ -0,0 +1,82 @@
+package keeper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+// liveTestConfig is a config for New against a fake chain and ML server,
+// with its logs and file state under a temporary directory
+func liveTestConfig(t *testing.T, chainURL, mlURL string) *Config {
+	config := testConfig(t)
+	config.LogLevel = "error"
+	config.ChainID = 31337
+	config.MantleRPC = chainURL
+	config.MLAPIEndpoint = mlURL
+	config.PrivateKey = "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	config.Multicall3Addr = ""
+	config.AuditLogPath = filepath.Join(t.TempDir(), "audit.jsonl")
+	config.DecisionLogPath = filepath.Join(t.TempDir(), "decisions.jsonl")
+	config.StateBackend, config.StatePath = StateBackendFile, filepath.Join(t.TempDir(), "state.json")
+	return config
+}
+
+func TestStartAndCloseLeaveNoGoroutines(t *testing.T) {
+	ignore := goleak.IgnoreCurrent()
+	ml := newFakeMLServer(t, nil)
+	chain, chainURL := newFakeChain(t, 31337)
+
+	bot, err := New(liveTestConfig(t, chainURL, ml.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bot.Start(ctx) }()
+
+	// Stop once the scheduler is running
+	deadline := time.Now().Add(10 * time.Second)
+	for len(bot.jobStatuses()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("bot did not schedule its jobs")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Start did not return after cancel")
+	}
+
+	ml.Close()
+	chain.Close()
+	goleak.VerifyNone(t, ignore)
+}
+
+func TestNewReleasesResourcesOnError(t *testing.T) {
+	ignore := goleak.IgnoreCurrent()
+	ml := newFakeMLServer(t, nil)
+	chain, chainURL := newFakeChain(t, 31337)
+
+	// restoreState is the last step of newBot, so every resource it opens,
+	// the chain client included, is held when it fails
+	config := liveTestConfig(t, chainURL, ml.URL)
+	if err := os.WriteFile(config.StatePath, []byte(`{"emergency_mode": "yes"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := New(config); err == nil {
+		t.Fatal("New succeeded with a corrupt emergency mode")
+	}
+
+	ml.Close()
+	chain.Close()
+	goleak.VerifyNone(t, ignore)
+}
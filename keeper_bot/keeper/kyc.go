@@ -3,47 +3,134 @@ package keeper
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 )
 
-// MonitorKYCCompliance monitors KYC compliance
-func (b *Bot) MonitorKYCCompliance(ctx context.Context) error {
+// MonitorKYCCompliance monitors KYC compliance and summarizes the assessments
+func (b *Bot) MonitorKYCCompliance(ctx context.Context) (*KYCResult, error) {
+	if !b.kycEnabled() {
+		return nil, nil
+	}
+	if !b.tryStartRun("kyc", &b.kycRun) {
+		return nil, nil
+	}
+	defer b.kycRun.Unlock()
+
 	b.logger.Info("Monitoring KYC compliance...")
 
 	// Mock investment data - in production would get from contract events
-	investments := []map[string]interface{}{
+	investments := []KYCRequest{
 		{
-			"investmentAmount":     500000,
-			"tier":                 2,
-			"jurisdiction":         "US",
-			"transactionFrequency": 5,
-			"walletAgeDays":        100,
-			"previousDefiExposure": 0,
+			InvestmentAmount:     500000,
+			Tier:                 2,
+			Jurisdiction:         "US",
+			TransactionFrequency: 5,
+			WalletAgeDays:        100,
+			PreviousDefiExposure: 0,
 		},
 	}
 
-	for _, investment := range investments {
-		response, err := b.callMLAPI("/api/v1/kyc-risk-assessment", investment)
-		if err != nil {
-			b.logger.WithError(err).Error("KYC risk assessment failed")
+	results := b.assessInvestments(ctx, investments)
+
+	var errs []error
+	highRisk, violated := 0, 0
+	for i, result := range results {
+		if result.err != nil {
+			b.logger.WithError(result.err).WithField("investment", i).Error("KYC risk assessment failed")
+			errs = append(errs, fmt.Errorf("investment %d: %w", i, result.err))
 			continue
 		}
 
-		var kycResp KYCRiskResponse
-		if err := json.Unmarshal(response, &kycResp); err != nil {
-			b.logger.WithError(err).Error("Failed to parse KYC response")
-			continue
+		if violations := b.jurisdictionViolations(investments[i]); len(violations) > 0 {
+			violated++
+			result.resp = applyJurisdictionRules(result.resp, violations)
+			b.alerter.Send(Alert{
+				Severity: b.cfg().KYCRuleViolationSeverity,
+				Title:    "KYC jurisdiction rule violated",
+				Fields: map[string]interface{}{
+					"investment":   i,
+					"jurisdiction": investments[i].Jurisdiction,
+					"tier":         investments[i].Tier,
+					"amount":       investments[i].InvestmentAmount,
+					"flags":        violations,
+				},
+			})
 		}
 
-		if kycResp.RiskClassification == "HIGH_RISK" {
-			b.logger.WithFields(logrus.Fields{
-				"risk_score":     kycResp.KYCRiskScore,
-				"classification": kycResp.RiskClassification,
-				"flags":          kycResp.ComplianceFlags,
-			}).Warn("HIGH RISK INVESTMENT DETECTED")
+		if result.resp.RiskClassification == "HIGH_RISK" {
+			highRisk++
+			b.warnSampled("kyc_high_risk:"+kycCacheKey(investments[i]), b.logger.WithFields(logrus.Fields{
+				"investment":     i,
+				"risk_score":     b.score(result.resp.KYCRiskScore),
+				"classification": result.resp.RiskClassification,
+				"flags":          result.resp.ComplianceFlags,
+			}), "HIGH RISK INVESTMENT DETECTED")
 		}
 	}
 
-	return nil
+	summary := &KYCResult{
+		Assessed:       len(results) - len(errs),
+		Failed:         len(errs),
+		HighRisk:       highRisk,
+		RuleViolations: violated,
+	}
+	return summary, errors.Join(errs...)
+}
+
+// kycResult is the outcome of one investment's risk assessment
+type kycResult struct {
+	resp *KYCRiskResponse
+	err  error
+}
+
+// assessInvestments scores investments on up to KYCConcurrency workers,
+// applying the jurisdiction fast paths first. Results are returned in input
+// order; one failure does not affect others.
+func (b *Bot) assessInvestments(ctx context.Context, investments []KYCRequest) []kycResult {
+	results := make([]kycResult, len(investments))
+
+	workers := b.cfg().KYCConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resp, err := b.assessOrScreen(ctx, investments[i])
+				results[i] = kycResult{resp: resp, err: err}
+			}
+		}()
+	}
+
+	for i := range investments {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// assessInvestment scores a single investment with the ML engine
+func (b *Bot) assessInvestment(ctx context.Context, investment KYCRequest) (*KYCRiskResponse, error) {
+	response, err := b.callMLAPI(ctx, "kyc-risk-assessment", investment)
+	if err != nil {
+		return nil, err
+	}
+
+	var kycResp KYCRiskResponse
+	if err := json.Unmarshal(response, &kycResp); err != nil {
+		return nil, fmt.Errorf("failed to parse KYC response: %w", err)
+	}
+	b.observeMLClock(kycResp.Timestamp)
+	return &kycResp, nil
 }
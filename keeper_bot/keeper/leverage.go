@@ -2,83 +2,446 @@ package keeper
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"slices"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/sirupsen/logrus"
 )
 
-// MonitorLeverageStrategy monitors the leveraged RWA strategy
-func (b *Bot) MonitorLeverageStrategy(ctx context.Context) error {
+// MonitorLeverageStrategy monitors every configured leveraged RWA strategy,
+// returning one result per strategy assessed. A failure on one strategy does
+// not stop the others from being assessed.
+func (b *Bot) MonitorLeverageStrategy(ctx context.Context) ([]LeverageResult, error) {
+	if !b.tryStartRun("leverage", &b.leverageRun) {
+		return nil, nil
+	}
+	defer b.leverageRun.Unlock()
+
 	b.logger.Info("Monitoring leverage strategy health...")
 
-	// Get position data from contract (simplified - would use contract bindings)
-	positionData := map[string]interface{}{
-		"totalCollateral":     1000000, // Mock data
-		"totalBorrowed":       600000,
-		"currentHealthFactor": 1.5,
-		"aitValue":            650000,
+	results := make([]LeverageResult, 0, len(b.leveragedStrategies))
+	var errs []error
+	for _, read := range b.readPositions(ctx) {
+		result, err := b.monitorStrategy(ctx, read)
+		if err != nil {
+			b.logger.WithError(err).WithFields(positionFields(read.strategy, read.account)).Error("Strategy monitoring failed")
+			b.metrics.AddCounter(metricLeverageFailures, 1, "strategy", read.strategy.Hex())
+			errs = append(errs, fmt.Errorf("strategy %s: %w", read.strategy.Hex(), err))
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+// monitorStrategy assesses a single strategy position and acts on its
+// recommendations. A zero account assesses the aggregate position; otherwise
+// the sub-account is assessed and risk actions target it alone.
+func (b *Bot) monitorStrategy(ctx context.Context, read positionRead) (LeverageResult, error) {
+	strategy, account := read.strategy, read.account
+	result := LeverageResult{Strategy: strategy.Hex()}
+	key := idempotencyKey("leverage_health", strategy, read.block)
+	if account != (common.Address{}) {
+		result.Account = account.Hex()
+		ctx = withSubAccount(ctx, account)
+		key += ":" + account.Hex()
+	}
+	if read.err != nil {
+		return result, read.err
+	}
+	position := read.position
+	ctx = withIdempotencyKey(ctx, key)
+
+	labels := positionLabels(strategy, account)
+	result.Position = position
+	result.HealthFactor = b.score(position.HealthFactor)
+	result.LTV = b.score(position.LTV)
+	b.metrics.SetGauge(metricHealthFactor, result.HealthFactor, labels...)
+	b.metrics.SetGauge(metricLTV, result.LTV, labels...)
+
+	positionData := LeverageHealthRequest{
+		TotalCollateral:     position.TotalCollateral,
+		TotalBorrowed:       position.TotalBorrowed,
+		CurrentHealthFactor: position.HealthFactor,
+		AITValue:            position.AITValue,
 	}
 
 	// Call ML engine for risk assessment
-	response, err := b.callMLAPI("/api/v1/leverage-health", positionData)
+	response, err := b.assessLeverage(ctx, positionData)
 	if err != nil {
-		return fmt.Errorf("ML API call failed: %w", err)
+		err = fmt.Errorf("ML API call failed: %w", err)
+		if !b.cfg().EnableFallbackPolicy {
+			return result, err
+		}
+
+		// The local model stands in for the engine when one is loaded;
+		// otherwise the rule-based fallback policy applies
+		if local := b.localAssessment(strategy, account, positionData); local != nil {
+			result.LocalModel = true
+			return result, errors.Join(err, b.actOnAssessment(ctx, strategy, account, position, local, &result))
+		}
+		result.RiskLevel = fallbackRiskLevel
+		result.Fallback = true
+		decision, _ := b.decideRiskAction(time.Now(), position, nil)
+		b.recordDecision(newDecision(strategy.Hex(), result.Account, position, nil, decision))
+		fallbackErr := b.applyFallbackPolicy(ctx, strategy, position, decision, &result)
+		return result, errors.Join(err, fallbackErr)
 	}
 
-	var healthResp LeverageHealthResponse
-	if err := json.Unmarshal(response, &healthResp); err != nil {
-		return fmt.Errorf("failed to parse ML response: %w", err)
+	return result, b.actOnAssessment(ctx, strategy, account, position, response, &result)
+}
+
+// actOnAssessment decides on and executes the risk action for a position's
+// raw leverage assessment, from the ML engine or the local model
+func (b *Bot) actOnAssessment(ctx context.Context, strategy, account common.Address, position *StrategyPosition, response []byte, result *LeverageResult) error {
+	decision, err := b.decideRiskAction(time.Now(), position, response)
+	if err != nil {
+		return err
+	}
+	record := newDecision(strategy.Hex(), result.Account, position, response, decision)
+	record.LocalModel = result.LocalModel
+	b.recordDecision(record)
+
+	healthResp := decision.assessment
+	if !result.LocalModel {
+		b.observeMLClock(healthResp.Timestamp)
 	}
+	if decision.unknownLevel != "" {
+		b.logger.WithFields(positionFields(strategy, account)).WithFields(logrus.Fields{
+			"risk_level": decision.unknownLevel,
+			"treated_as": healthResp.RiskLevel,
+		}).Warn("Unknown ML risk level, treating conservatively")
+		b.alertUnknownRiskLevel(strategy.Hex(), decision.unknownLevel)
+	}
+	result.RiskLevel = healthResp.RiskLevel
+	result.Score = b.score(healthResp.CompositeRiskScore)
+	result.Confidence = b.scorePtr(healthResp.Confidence)
 
-	b.logger.WithFields(logrus.Fields{
+	entry := b.logger.WithFields(positionFields(strategy, account)).WithFields(logrus.Fields{
 		"risk_level": healthResp.RiskLevel,
-		"risk_score": healthResp.CompositeRiskScore,
-	}).Info("Risk assessment completed")
+		"risk_score": result.Score,
+	})
+	if result.Confidence != nil {
+		entry = entry.WithField("confidence", *result.Confidence)
+	}
+	if result.LocalModel {
+		entry = entry.WithField("source", "local_model")
+	}
+	entry.Info("Risk assessment completed")
+	labels := positionLabels(strategy, account)
+	b.metrics.AddCounter(metricLeverageAssessments, 1, "strategy", strategy.Hex())
+	b.metrics.SetGauge(metricCompositeRiskScore, result.Score, labels...)
+
+	if decision.notActionable != nil {
+		entry := b.logger.WithError(decision.notActionable).WithFields(positionFields(strategy, account))
+		if !decision.ok {
+			entry.Warn("Risk assessment not actionable, skipping risk actions")
+			return nil
+		}
+		entry.WithField("reasons", decision.overrideReasons).Warn("Risk assessment not actionable but position breaches local limits, acting on on-chain data")
+	}
 
 	// Execute actions based on recommendations
-	return b.executeRiskActions(ctx, &healthResp)
+	return b.executeRiskActions(ctx, strategy, decision, result)
 }
 
-// executeRiskActions performs risk management actions
-func (b *Bot) executeRiskActions(ctx context.Context, assessment *LeverageHealthResponse) error {
-	for _, recommendation := range assessment.Recommendations {
-		switch recommendation {
-		case "EMERGENCY_DELEVERAGE":
-			b.logger.Warn("EMERGENCY DELEVERAGING TRIGGERED")
-			return b.emergencyDeleverage(ctx)
-		case "REDUCE_LEVERAGE":
-			b.logger.Info("Reducing leverage position")
-			return b.reduceLeverage(ctx)
-		case "PAUSE_NEW_POSITIONS":
-			b.logger.Info("Pausing new positions due to low liquidity")
-			// Implementation would pause new borrowing
+// positionRead is a monitored position and the outcome of reading it
+type positionRead struct {
+	strategy common.Address
+	account  common.Address // zero for the aggregate position
+	block    *big.Int
+	position *StrategyPosition
+	err      error
+}
+
+// readPositions reads every monitored position at one resolved block,
+// batching all their calls into a single RPC call when Multicall3 is
+// available. A position that fails to read carries its error.
+func (b *Bot) readPositions(ctx context.Context) []positionRead {
+	var reads []positionRead
+	var calls [][]contractCall
+	for _, strategy := range b.leveragedStrategies {
+		for _, account := range b.monitoredAccounts(ctx, strategy) {
+			reads = append(reads, positionRead{strategy: strategy, account: account})
+			calls = append(calls, b.positionCalls(strategy, account))
 		}
 	}
-	return nil
+
+	block, err := b.resolveBlock(ctx, b.leverageBlock)
+	if err != nil {
+		for i := range reads {
+			reads[i].err = err
+		}
+		return reads
+	}
+
+	results := b.batchCall(ctx, block, slices.Concat(calls...))
+	for i := range reads {
+		n := len(calls[i])
+		reads[i].block = block
+		reads[i].position, reads[i].err = b.decodePosition(reads[i].strategy, reads[i].account, results[:n])
+		results = results[n:]
+	}
+	return reads
+}
+
+// readPosition reads one position from chain at block: a sub-account's, or
+// the strategy's aggregate position for a zero account
+func (b *Bot) readPosition(ctx context.Context, strategy, account common.Address, block *big.Int) (*StrategyPosition, error) {
+	return b.decodePosition(strategy, account, b.batchCall(ctx, block, b.positionCalls(strategy, account)))
+}
+
+// positionCalls are the contract reads making up a position
+func (b *Bot) positionCalls(strategy, account common.Address) []contractCall {
+	if account != (common.Address{}) {
+		return []contractCall{
+			{abi: b.abis.strategy, contract: strategy, method: "getSubAccountMetrics", args: []interface{}{account}},
+		}
+	}
+	return []contractCall{
+		{abi: b.abis.strategy, contract: strategy, method: "totalCollateral"},
+		{abi: b.abis.strategy, contract: strategy, method: "totalBorrowed"},
+		{abi: b.abis.strategy, contract: strategy, method: "getLeverageMetrics"},
+	}
+}
+
+// decodePosition builds a position from the results of its positionCalls,
+// failing with the first call's error
+func (b *Bot) decodePosition(strategy, account common.Address, results []callResult) (*StrategyPosition, error) {
+	for _, result := range results {
+		if result.err != nil {
+			return nil, result.err
+		}
+	}
+
+	decimals := b.strategyDecimals[strategy]
+	if account != (common.Address{}) {
+		out := results[0].out
+		return &StrategyPosition{
+			TotalCollateral: scaleAmount(out[0].(*big.Int), decimals.collateral),
+			TotalBorrowed:   scaleAmount(out[1].(*big.Int), decimals.debt),
+			LTV:             scaleAmount(out[2].(*big.Int), bpsDecimals),
+			HealthFactor:    scaleAmount(out[3].(*big.Int), bpsDecimals),
+			AITValue:        scaleAmount(out[4].(*big.Int), decimals.ait),
+		}, nil
+	}
+
+	collateral, borrowed, leverage := results[0].out, results[1].out, results[2].out
+	return &StrategyPosition{
+		TotalCollateral: scaleAmount(collateral[0].(*big.Int), decimals.collateral),
+		TotalBorrowed:   scaleAmount(borrowed[0].(*big.Int), decimals.debt),
+		LTV:             scaleAmount(leverage[0].(*big.Int), bpsDecimals),
+		HealthFactor:    scaleAmount(leverage[1].(*big.Int), bpsDecimals),
+		AITValue:        scaleAmount(leverage[2].(*big.Int), decimals.ait),
+	}, nil
 }
 
-// emergencyDeleverage executes emergency deleveraging
-func (b *Bot) emergencyDeleverage(ctx context.Context) error {
-	auth, err := b.getTransactOpts(ctx)
+// executeRiskActions performs the most severe recommended risk action,
+// recording what was done in result
+func (b *Bot) executeRiskActions(ctx context.Context, strategy common.Address, decision *leverageDecision, result *LeverageResult) error {
+	config := b.cfg()
+	chosen := decision.chosen
+
+	for _, recommendation := range decision.unknown {
+		b.logger.WithFields(logrus.Fields{
+			"strategy":       strategy.Hex(),
+			"recommendation": recommendation,
+		}).Warn("No action registered for ML recommendation")
+	}
+
+	if len(decision.advisory) > 0 {
+		b.logger.WithFields(logrus.Fields{
+			"strategy":        strategy.Hex(),
+			"recommendations": decision.advisory,
+		}).Info("Advisory ML recommendations (action_required=false), taking no action")
+	}
+
+	if !decision.ok {
+		return nil
+	}
+
+	if len(decision.overrideReasons) > 0 && decision.notActionable == nil {
+		b.logger.WithFields(logrus.Fields{
+			"strategy":      strategy.Hex(),
+			"ml_risk_level": decision.assessment.RiskLevel,
+			"ml_risk_score": b.score(decision.assessment.CompositeRiskScore),
+			"reasons":       decision.overrideReasons,
+			"action":        chosen,
+		}).Warn("ML engine recommended no action but local risk checks call for one, overriding")
+	}
+	if len(decision.overrideReasons) > 0 {
+		b.metrics.AddCounter(metricThresholdOverrides, 1, "strategy", strategy.Hex(), "action", chosen)
+	}
+
+	if len(decision.skipped) > 0 {
+		b.logger.WithFields(logrus.Fields{
+			"strategy": strategy.Hex(),
+			"chosen":   chosen,
+			"skipped":  decision.skipped,
+			"policy":   config.RecommendationPolicy,
+		}).Info("Conflicting recommendations, executing one chosen by policy")
+	}
+
+	// Degraded mode demands more confidence of ML-backed actions; threshold
+	// overrides and fallback decisions rest on on-chain data alone
+	if confidence := decision.assessment.Confidence; confidence != nil && len(decision.overrideReasons) == 0 {
+		if floor := b.degradedFloor(config.MinDeleverageConfidence); *confidence < floor {
+			b.logger.WithFields(logrus.Fields{
+				"strategy":       strategy.Hex(),
+				"action":         chosen,
+				"confidence":     b.score(*confidence),
+				"min_confidence": floor,
+			}).Warn("Degraded mode: ML confidence below raised floor, skipping risk action")
+			b.metrics.AddCounter(metricTxSkipped, 1, "reason", "degraded_confidence")
+			return nil
+		}
+	}
+
+	account, _ := subAccountFrom(ctx)
+	key := cooldownKey{action: chosen, strategy: strategy, account: account}
+	if until, active := b.cooldownUntil(key); active {
+		b.logger.WithFields(positionFields(strategy, account)).WithFields(logrus.Fields{
+			"action": chosen,
+			"until":  until,
+		}).Info("Risk action in cooldown, skipping")
+		b.metrics.AddCounter(metricTxSkipped, 1, "reason", "cooldown")
+		return nil
+	}
+
+	result.ActionTaken = chosen
+	tx, err := decision.action.Handler(ctx, strategy)
+	if errors.Is(err, ErrInsufficientLiquidity) {
+		return b.escalateToPause(ctx, strategy, err, result)
+	}
+	if tx != nil {
+		result.TxHash = tx.Hash().Hex()
+		result.EstimatedGas = b.estimateUnsentGas(ctx, tx)
+	}
 	if err != nil {
 		return err
 	}
-
-	// In production: call emergencyDeleverage on LeveragedRWAStrategy contract
-	b.logger.WithField("nonce", auth.Nonce).Info("Emergency deleverage transaction sent")
-	b.emergencyMode = true
+	b.startCooldown(key)
 	return nil
 }
 
-// reduceLeverage gradually reduces leverage
-func (b *Bot) reduceLeverage(ctx context.Context) error {
-	auth, err := b.getTransactOpts(ctx)
+// emergencyDeleverage executes emergency deleveraging of the strategy, or of
+// the sub-account targeted by ctx
+func (b *Bot) emergencyDeleverage(ctx context.Context, strategy common.Address) (*types.Transaction, error) {
+	config := b.cfg()
+	account, subAccount := subAccountFrom(ctx)
+
+	var out []interface{}
+	var err error
+	if subAccount {
+		out, err = b.callContract(ctx, nil, b.abis.strategy, strategy, "subAccountAITHoldings", account)
+	} else {
+		out, err = b.callContract(ctx, nil, b.abis.strategy, strategy, "totalAITHoldings")
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
+	holdings := out[0].(*big.Int)
 
-	// In production: call harvestRwaYield and repayDebt
-	b.logger.WithField("nonce", auth.Nonce).Info("Leverage reduction transaction sent")
-	return nil
+	// Sell a fixed fraction of AIT holdings to repay debt
+	aitToSell, _ := new(big.Float).Mul(
+		new(big.Float).SetInt(holdings),
+		big.NewFloat(config.DeleverageFraction),
+	).Int(nil)
+
+	// Refuse a sale the market would fill too far below the AIT's NAV
+	minOut, err := b.deleverageMinOut(ctx, strategy, aitToSell)
+	if errors.Is(err, ErrSlippageExceeded) {
+		b.metrics.AddCounter(metricTxSkipped, 1, "reason", "slippage")
+		fields := map[string]interface{}{
+			"strategy":     strategy.Hex(),
+			"ait_to_sell":  aitToSell.String(),
+			"slippage_bps": config.MaxDeleverageSlippageBps,
+			"error":        err.Error(),
+		}
+		if subAccount {
+			fields["account"] = account.Hex()
+		}
+		b.alerter.Send(Alert{
+			Severity: AlertCritical,
+			Title:    "Emergency deleverage quote exceeds maximum slippage, not sending",
+			Fields:   fields,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	method, args := b.deleverageCall(account, subAccount, aitToSell, minOut)
+
+	approved := false
+	if config.DeleverageApprovalSpender != "" {
+		spender := common.HexToAddress(config.DeleverageApprovalSpender)
+		if approved, err = b.ensureAllowance(ctx, "emergency_deleverage", b.strategyDecimals[strategy].aitToken, spender, aitToSell); err != nil {
+			return nil, fmt.Errorf("failed to approve deleverage: %w", err)
+		}
+	}
+
+	// Do not burn gas on a deleverage that would revert. A simulation
+	// would run without the approval just sent, so it is skipped then.
+	if config.SimulateBeforeSend && !approved {
+		if err := b.simulateTx(ctx, b.abis.strategy, strategy, method, args...); err != nil {
+			b.metrics.AddCounter(metricTxSkipped, 1, "reason", "simulation_reverted")
+			fields := map[string]interface{}{
+				"strategy":    strategy.Hex(),
+				"ait_to_sell": aitToSell.String(),
+				"error":       err.Error(),
+			}
+			if subAccount {
+				fields["account"] = account.Hex()
+			}
+			b.alerter.Send(Alert{
+				Severity: AlertCritical,
+				Title:    "Emergency deleverage simulation failed, not sending",
+				Fields:   fields,
+			})
+			return nil, err
+		}
+	}
+
+	tx, err := b.sendTx(ctx, "emergency_deleverage", b.abis.strategy, strategy, method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	b.logger.WithFields(positionFields(strategy, account)).WithFields(logrus.Fields{
+		"tx":          tx.Hash().Hex(),
+		"ait_to_sell": aitToSell.String(),
+	}).Info("Emergency deleverage transaction sent")
+
+	b.trackEmergency(strategy, account, subAccount, tx)
+	return tx, nil
+}
+
+// reduceLeverage gradually reduces leverage of the strategy, or of the
+// sub-account targeted by ctx
+func (b *Bot) reduceLeverage(ctx context.Context, strategy common.Address) (*types.Transaction, error) {
+	account, subAccount := subAccountFrom(ctx)
+
+	if err := b.checkLiquidity(ctx, strategy); err != nil {
+		return nil, err
+	}
+
+	var tx *types.Transaction
+	var err error
+	if subAccount {
+		tx, err = b.sendTx(ctx, "reduce_leverage", b.abis.strategy, strategy, "reduceSubAccountLeverage", account)
+	} else {
+		// Harvested RWA yield is held as USDC by the strategy for debt repayment
+		tx, err = b.sendTx(ctx, "reduce_leverage", b.abis.strategy, strategy, "harvestRwaYield")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b.logger.WithFields(positionFields(strategy, account)).WithField("tx", tx.Hash().Hex()).Info("Leverage reduction transaction sent")
+	return tx, nil
 }
@@ -0,0 +1,116 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInsufficientLiquidity is returned when a strategy's liquidity score is
+// below MinLiquidity
+var ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+
+const erc20BalanceABIJSON = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+var erc20BalanceABI = mustParseABI(erc20BalanceABIJSON)
+
+// liquidityScore is the fraction of a strategy's debt its stablecoin balance
+// could repay now, capped at 1; a strategy without debt scores 1
+func (b *Bot) liquidityScore(ctx context.Context, strategy common.Address) (float64, error) {
+	borrowed, err := b.callContract(ctx, nil, b.abis.strategy, strategy, "totalBorrowed")
+	if err != nil {
+		return 0, err
+	}
+	debt := borrowed[0].(*big.Int)
+	if debt.Sign() == 0 {
+		return 1, nil
+	}
+
+	balance, err := b.callContract(ctx, nil, erc20BalanceABI, b.strategyDecimals[strategy].debtToken, "balanceOf", strategy)
+	if err != nil {
+		return 0, err
+	}
+	// Balance and debt share the debt token's decimals
+	score, _ := new(big.Rat).SetFrac(balance[0].(*big.Int), debt).Float64()
+	return min(score, 1), nil
+}
+
+// checkLiquidity returns ErrInsufficientLiquidity when the strategy cannot
+// safely repay debt because its liquidity score is below MinLiquidity
+func (b *Bot) checkLiquidity(ctx context.Context, strategy common.Address) error {
+	config := b.cfg()
+	if config.MinLiquidity <= 0 {
+		return nil
+	}
+	score, err := b.liquidityScore(ctx, strategy)
+	if err != nil {
+		return fmt.Errorf("failed to read liquidity: %w", err)
+	}
+	if score < config.MinLiquidity {
+		return fmt.Errorf("%w: score %.4f below minimum %.4f", ErrInsufficientLiquidity, score, config.MinLiquidity)
+	}
+	return nil
+}
+
+// escalateToPause pauses new positions on a strategy whose leverage
+// reduction was deferred for lack of liquidity. The reduction's cooldown is
+// not started, so it is retried once liquidity recovers. When no pause
+// action is registered, or it sends no transaction, nothing protective
+// happened: the deferral is alerted and no cooldown is started.
+func (b *Bot) escalateToPause(ctx context.Context, strategy common.Address, reason error, result *LeverageResult) error {
+	account, _ := subAccountFrom(ctx)
+	logger := b.logger.WithFields(positionFields(strategy, account)).WithError(reason)
+	b.metrics.AddCounter(metricTxSkipped, 1, "reason", "insufficient_liquidity")
+
+	pause, ok := b.riskAction(RecPauseNewPositions)
+	if !ok {
+		b.pauseUnavailable(strategy, account, reason, "no action registered to pause new positions", result)
+		return nil
+	}
+	key := cooldownKey{action: RecPauseNewPositions, strategy: strategy, account: account}
+	if until, active := b.cooldownUntil(key); active {
+		result.ActionTaken = ""
+		logger.WithField("until", until).Info("Leverage reduction deferred, pause of new positions in cooldown")
+		return nil
+	}
+
+	logger.WithFields(logrus.Fields{
+		"deferred":  result.ActionTaken,
+		"escalated": RecPauseNewPositions,
+	}).Warn("Leverage reduction deferred, pausing new positions instead")
+	result.ActionTaken = RecPauseNewPositions
+	tx, err := pause.Handler(ctx, strategy)
+	if err != nil {
+		return err
+	}
+	if tx == nil {
+		b.pauseUnavailable(strategy, account, reason, "pause action sent no transaction", result)
+		return nil
+	}
+	result.TxHash = tx.Hash().Hex()
+	result.EstimatedGas = b.estimateUnsentGas(ctx, tx)
+	b.startCooldown(key)
+	return nil
+}
+
+// pauseUnavailable records a leverage reduction deferred without pausing
+// new positions and alerts it, since the position is left unprotected
+func (b *Bot) pauseUnavailable(strategy, account common.Address, reason error, why string, result *LeverageResult) {
+	deferred := result.ActionTaken
+	result.ActionTaken = ""
+	fields := positionFields(strategy, account)
+	fields["deferred"] = deferred
+	fields["reason"] = reason.Error()
+	fields["pause"] = why
+	b.sendDeduped("pause_unavailable:"+strategy.Hex()+":"+account.Hex(), Alert{
+		Severity: AlertCritical,
+		Title:    "Leverage reduction deferred and new positions not paused",
+		Fields:   fields,
+	})
+}
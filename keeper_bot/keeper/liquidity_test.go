@@ -0,0 +1,78 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestEscalateToPause(t *testing.T) {
+	strategy := common.HexToAddress("0x1")
+	reason := fmt.Errorf("%w: score 0.0500 below minimum 0.1000", ErrInsufficientLiquidity)
+	pauseKey := cooldownKey{action: RecPauseNewPositions, strategy: strategy}
+
+	tests := []struct {
+		name string
+		// pause, when set, replaces the built-in PAUSE_NEW_POSITIONS handler
+		pause        func(context.Context, common.Address) (*types.Transaction, error)
+		unregister   bool
+		wantAction   string
+		wantCooldown bool
+		wantAlert    bool
+	}{
+		{
+			name:       "built-in handler sends nothing",
+			wantAction: "",
+			wantAlert:  true,
+		},
+		{
+			name:       "no pause action registered",
+			unregister: true,
+			wantAction: "",
+			wantAlert:  true,
+		},
+		{
+			name: "pause transaction sent",
+			pause: func(context.Context, common.Address) (*types.Transaction, error) {
+				return types.NewTx(&types.LegacyTx{}), nil
+			},
+			wantAction:   RecPauseNewPositions,
+			wantCooldown: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(t)
+			config.PauseNewPositionsCooldown = time.Hour
+			bot, sink := newTestBot(t, config, nil)
+			switch {
+			case tt.unregister:
+				delete(bot.riskActions, RecPauseNewPositions)
+			case tt.pause != nil:
+				bot.RegisterRiskAction(RecPauseNewPositions, RiskAction{Severity: SeverityPause, Handler: tt.pause})
+			}
+
+			result := &LeverageResult{ActionTaken: RecReduceLeverage}
+			if err := bot.escalateToPause(context.Background(), strategy, reason, result); err != nil {
+				t.Fatalf("escalateToPause: %v", err)
+			}
+			bot.alerter.Wait()
+
+			if result.ActionTaken != tt.wantAction {
+				t.Errorf("ActionTaken = %q, want %q", result.ActionTaken, tt.wantAction)
+			}
+			if _, active := bot.cooldownUntil(pauseKey); active != tt.wantCooldown {
+				t.Errorf("pause cooldown active = %v, want %v", active, tt.wantCooldown)
+			}
+			alerted := slices.Contains(sink.titles(), "Leverage reduction deferred and new positions not paused")
+			if alerted != tt.wantAlert {
+				t.Errorf("alerted = %v, want %v (alerts %q)", alerted, tt.wantAlert, sink.titles())
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package keeper
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrLocalModelUnsupported is returned when LocalModelPath is set on a
+// keeper built without a local model runtime
+var ErrLocalModelUnsupported = errors.New("local model requires a keeper built with -tags onnx")
+
+// LocalScorer computes a composite risk score in-process from the
+// leverage-health features, in leverageFeatures order
+type LocalScorer interface {
+	Score(features []float32) (float64, error)
+	Close() error
+}
+
+// openLocalScorer loads the model at Config.LocalModelPath; it is set by the
+// runtime compiled in, see localmodel_onnx.go
+var openLocalScorer func(config *Config) (LocalScorer, error)
+
+// newLocalScorer loads the configured local model, if any
+func newLocalScorer(config *Config) (LocalScorer, error) {
+	if config.LocalModelPath == "" {
+		return nil, nil
+	}
+	if openLocalScorer == nil {
+		return nil, ErrLocalModelUnsupported
+	}
+	return openLocalScorer(config)
+}
+
+// leverageFeatures is the local model's input vector for a position
+func leverageFeatures(request LeverageHealthRequest) []float32 {
+	return []float32{
+		float32(request.TotalCollateral),
+		float32(request.TotalBorrowed),
+		float32(request.CurrentHealthFactor),
+		float32(request.AITValue),
+	}
+}
+
+// localAssessment scores a position with the local model, returning the
+// assessment as raw response JSON so it is decided and logged like an ML
+// engine response. Like the fallback policy it recommends at most a leverage
+// reduction, at HighRisk. It returns nil when no local model is loaded or
+// scoring fails, leaving the rule-based fallback policy to apply.
+func (b *Bot) localAssessment(strategy, account common.Address, request LeverageHealthRequest) []byte {
+	config := b.cfg()
+	if b.localScorer == nil {
+		return nil
+	}
+	logger := b.logger.WithFields(positionFields(strategy, account)).WithField("source", "local_model")
+
+	score, err := b.localScorer.Score(leverageFeatures(request))
+	if err != nil {
+		logger.WithError(err).Error("Local model scoring failed")
+		return nil
+	}
+
+	assessment := LeverageHealthResponse{
+		CompositeRiskScore: score,
+		RiskLevel:          "LOW",
+		Timestamp:          time.Now().Unix(),
+	}
+	switch {
+	case score >= config.CriticalRisk:
+		assessment.RiskLevel = "CRITICAL"
+	case score >= config.HighRisk:
+		assessment.RiskLevel = "HIGH"
+	}
+	if score >= config.HighRisk {
+		assessment.ActionRequired = true
+		assessment.Recommendations = []string{RecReduceLeverage}
+	}
+
+	response, err := json.Marshal(assessment)
+	if err != nil {
+		logger.WithError(err).Error("Failed to encode local model assessment")
+		return nil
+	}
+	logger.WithFields(logrus.Fields{
+		"risk_score": b.score(score),
+		"risk_level": assessment.RiskLevel,
+	}).Warn("ML engine unavailable, using local model assessment")
+	return response
+}
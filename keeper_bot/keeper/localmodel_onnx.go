@@ -0,0 +1,84 @@
+//go:build onnx
+
+package keeper
+
+import (
+	"fmt"
+	"sync"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// The ONNX Runtime shared library is loaded at run time from
+// ONNXRuntimeLibPath, or the platform default when unset.
+
+func init() {
+	openLocalScorer = openONNXScorer
+}
+
+// onnxScorer runs a model with one [1, 4] float input and one [1, 1] float
+// output holding the composite risk score
+type onnxScorer struct {
+	mu      sync.Mutex
+	session *ort.AdvancedSession
+	input   *ort.Tensor[float32]
+	output  *ort.Tensor[float32]
+}
+
+// openONNXScorer initializes ONNX Runtime and loads LocalModelPath
+func openONNXScorer(config *Config) (LocalScorer, error) {
+	if config.ONNXRuntimeLibPath != "" {
+		ort.SetSharedLibraryPath(config.ONNXRuntimeLibPath)
+	}
+	if err := ort.InitializeEnvironment(); err != nil {
+		return nil, fmt.Errorf("failed to initialize ONNX Runtime: %w", err)
+	}
+
+	inputs, outputs, err := ort.GetInputOutputInfo(config.LocalModelPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local model %s: %w", config.LocalModelPath, err)
+	}
+	if len(inputs) != 1 || len(outputs) != 1 {
+		return nil, fmt.Errorf("local model %s: want 1 input and 1 output, got %d and %d", config.LocalModelPath, len(inputs), len(outputs))
+	}
+
+	input, err := ort.NewEmptyTensor[float32](ort.NewShape(1, int64(len(leverageFeatures(LeverageHealthRequest{})))))
+	if err != nil {
+		return nil, err
+	}
+	output, err := ort.NewEmptyTensor[float32](ort.NewShape(1, 1))
+	if err != nil {
+		input.Destroy()
+		return nil, err
+	}
+	session, err := ort.NewAdvancedSession(config.LocalModelPath,
+		[]string{inputs[0].Name}, []string{outputs[0].Name},
+		[]ort.Value{input}, []ort.Value{output}, nil)
+	if err != nil {
+		input.Destroy()
+		output.Destroy()
+		return nil, fmt.Errorf("failed to load local model %s: %w", config.LocalModelPath, err)
+	}
+	return &onnxScorer{session: session, input: input, output: output}, nil
+}
+
+// Score implements LocalScorer. The session reuses its tensors, so calls
+// are serialized.
+func (s *onnxScorer) Score(features []float32) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copy(s.input.GetData(), features)
+	if err := s.session.Run(); err != nil {
+		return 0, fmt.Errorf("local model inference failed: %w", err)
+	}
+	return float64(s.output.GetData()[0]), nil
+}
+
+// Close implements LocalScorer
+func (s *onnxScorer) Close() error {
+	s.session.Destroy()
+	s.input.Destroy()
+	s.output.Destroy()
+	return ort.DestroyEnvironment()
+}
@@ -0,0 +1,46 @@
+package keeper
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// warnSample tracks a repeating warning between emitted log lines
+type warnSample struct {
+	last       time.Time
+	suppressed int
+}
+
+// warnSampled logs a warning that repeats every cycle while a condition
+// persists. The first occurrence of key is logged, then repeats are
+// suppressed until WarnSampleInterval has passed; the next emitted line
+// carries the suppressed count. Only use it for warnings: errors and
+// emergency events must always be logged.
+func (b *Bot) warnSampled(key string, entry *logrus.Entry, msg string) {
+	interval := b.cfg().WarnSampleInterval
+	if interval <= 0 {
+		entry.Warn(msg)
+		return
+	}
+
+	now := time.Now()
+	b.mutex.Lock()
+	sample, seen := b.warnSamples[key]
+	if seen && now.Sub(sample.last) < interval {
+		sample.suppressed++
+		b.mutex.Unlock()
+		return
+	}
+	suppressed := 0
+	if seen {
+		suppressed = sample.suppressed
+	}
+	b.warnSamples[key] = &warnSample{last: now}
+	b.mutex.Unlock()
+
+	if suppressed > 0 {
+		entry = entry.WithField("suppressed", suppressed)
+	}
+	entry.Warn(msg)
+}
@@ -0,0 +1,317 @@
+package keeper
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metric names exposed on /metrics
+const (
+	metricInFlightTx  = "veritas_keeper_inflight_transactions"
+	metricTxSkipped   = "veritas_keeper_transactions_skipped_total"
+	metricGasSpentWei = "veritas_keeper_gas_spent_wei_total"
+	metricTxConfirm   = "veritas_keeper_transaction_confirmation_seconds"
+
+	metricLeverageAssessments = "veritas_keeper_leverage_assessments_total"
+	metricLeverageFailures    = "veritas_keeper_leverage_failures_total"
+	metricFallbackDecisions   = "veritas_keeper_fallback_decisions_total"
+	metricThresholdOverrides  = "veritas_keeper_threshold_overrides_total"
+	metricNAVUpdates          = "veritas_keeper_nav_updates_total"
+	metricInvoiceDefaults     = "veritas_keeper_invoice_defaults_flagged_total"
+
+	// Published invoice token NAV, labeled by token
+	metricInvoiceNAV    = "veritas_invoice_nav"
+	metricInvoiceNAVWei = "veritas_invoice_nav_wei"
+
+	// Leverage position gauges, labeled by strategy
+	metricHealthFactor       = "veritas_health_factor"
+	metricLTV                = "veritas_ltv"
+	metricCompositeRiskScore = "veritas_composite_risk_score"
+
+	metricMonitorRunsSkipped   = "veritas_keeper_monitor_runs_skipped_total"
+	metricCycleTimeouts        = "veritas_keeper_cycle_timeouts_total"
+	metricConsecutiveFailures  = "veritas_keeper_consecutive_failures"
+	metricRetryBudgetExhausted = "veritas_keeper_retry_budget_exhausted_total"
+	metricRefillAttempts       = "veritas_keeper_balance_refill_attempts_total"
+	metricReorgsDetected       = "veritas_keeper_reorgs_detected_total"
+	metricRPCReconnects        = "veritas_keeper_rpc_reconnects_total"
+	metricClockSkew            = "veritas_keeper_clock_skew_seconds"
+	metricDegraded             = "veritas_keeper_degraded"
+	metricMLQueueDepth         = "veritas_keeper_ml_queue_depth"
+	metricMLRejected           = "veritas_keeper_ml_requests_rejected_total"
+	metricMulticallFallbacks   = "veritas_keeper_multicall_fallbacks_total"
+)
+
+type metricDesc struct {
+	kind string
+	help string
+}
+
+var metricDescs = map[string]metricDesc{
+	metricInFlightTx:  {"gauge", "Keeper transactions broadcast but not yet confirmed"},
+	metricTxSkipped:   {"counter", "Keeper transactions skipped by a guard, by reason"},
+	metricGasSpentWei: {"counter", "Gas fees paid by mined keeper transactions in wei, by action"},
+	metricTxConfirm:   {"histogram", "Seconds from broadcast to the including block's timestamp for mined keeper transactions, by action"},
+
+	metricLeverageAssessments: {"counter", "Completed ML leverage assessments, by strategy"},
+	metricLeverageFailures:    {"counter", "Failed leverage monitoring runs, by strategy"},
+	metricFallbackDecisions:   {"counter", "Local fallback risk decisions made while the ML engine was unavailable"},
+	metricThresholdOverrides:  {"counter", "ML no-action assessments overridden by local risk thresholds, by strategy and action"},
+	metricNAVUpdates:          {"counter", "NAV update cycles, by invoice token and result"},
+	metricInvoiceDefaults:     {"counter", "Invoices newly predicted to default past InvoiceDefaultThreshold, by invoice token"},
+
+	metricInvoiceNAV:    {"gauge", "Last published invoice token NAV in human units"},
+	metricInvoiceNAVWei: {"gauge", "Last published invoice token NAV in on-chain base units"},
+
+	metricHealthFactor:       {"gauge", "Strategy health factor at the last leverage cycle"},
+	metricLTV:                {"gauge", "Strategy loan-to-value ratio at the last leverage cycle"},
+	metricCompositeRiskScore: {"gauge", "ML composite risk score from the last successful assessment"},
+
+	metricMonitorRunsSkipped:   {"counter", "Monitor triggers skipped because a run was in progress"},
+	metricCycleTimeouts:        {"counter", "Scheduled jobs cancelled after exceeding CycleTimeout, by job"},
+	metricConsecutiveFailures:  {"gauge", "Scheduled runs of a job that failed in a row, by job"},
+	metricRetryBudgetExhausted: {"counter", "Scheduled jobs that spent their RetryBudget and stopped retrying, by job"},
+	metricRefillAttempts:       {"counter", "Keeper balance top-up requests, by result"},
+	metricReorgsDetected:       {"counter", "Chain reorgs detected by event cursors, by cursor"},
+	metricRPCReconnects:        {"counter", "Chain client reconnections after a lost connection"},
+	metricClockSkew:            {"gauge", "Timestamp drift from the local clock in seconds, by source (ml, chain)"},
+	metricDegraded:             {"gauge", "1 while the keeper runs in degraded mode, else 0"},
+	metricMLQueueDepth:         {"gauge", "ML requests waiting for a concurrency slot"},
+	metricMLRejected:           {"counter", "ML requests rejected because the queue was full, by endpoint"},
+	metricMulticallFallbacks:   {"counter", "Batched contract reads retried as individual calls after Multicall3 failed"},
+}
+
+// histogramBuckets are the upper bounds of each histogram's buckets
+var histogramBuckets = map[string][]float64{
+	metricTxConfirm: {2, 5, 10, 20, 30, 60, 120, 300, 600},
+}
+
+// MetricSink receives every metric update as it is recorded, to push it to
+// a backend other than the Prometheus registry
+type MetricSink interface {
+	// Gauge sets a gauge; labels are alternating name/value pairs
+	Gauge(name string, value float64, labels []string)
+	// Count increments a counter; labels are alternating name/value pairs
+	Count(name string, delta float64, labels []string)
+}
+
+// HistogramSink is a MetricSink that also receives histogram observations;
+// sinks without it do not see histograms
+type HistogramSink interface {
+	// Histogram records an observation; labels are alternating name/value pairs
+	Histogram(name string, value float64, labels []string)
+}
+
+// histogram is one label set of a histogram metric
+type histogram struct {
+	labels []string
+	counts []uint64 // observations per bucket, non-cumulative; the last is +Inf
+	sum    float64
+	count  uint64
+}
+
+// Metrics is a minimal registry rendered in the Prometheus text format. Every
+// update is also fanned out to the added sinks.
+type Metrics struct {
+	mu     sync.Mutex
+	values map[string]map[string]float64 // metric name -> label set -> value
+	hists  map[string]map[string]*histogram
+	sinks  []MetricSink
+
+	// parent is the registry a labeled view records into, prefixing labels
+	// to every update, see WithLabels
+	parent *Metrics
+	labels []string
+}
+
+// NewMetrics creates an empty metrics registry
+func NewMetrics() *Metrics {
+	return &Metrics{
+		values: make(map[string]map[string]float64),
+		hists:  make(map[string]map[string]*histogram),
+	}
+}
+
+// WithLabels returns a view of the registry that adds labels, alternating
+// name/value pairs, to every metric it records
+func (m *Metrics) WithLabels(labels ...string) *Metrics {
+	root := m
+	if m.parent != nil {
+		root = m.parent
+	}
+	return &Metrics{parent: root, labels: append(append([]string(nil), m.labels...), labels...)}
+}
+
+// AddSink fans every later metric update out to sink
+func (m *Metrics) AddSink(sink MetricSink) {
+	if m.parent != nil {
+		m.parent.AddSink(sink)
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sinks = append(m.sinks, sink)
+}
+
+// SetGauge sets a gauge; labels are alternating name/value pairs
+func (m *Metrics) SetGauge(name string, value float64, labels ...string) {
+	if m.parent != nil {
+		m.parent.SetGauge(name, value, append(append([]string(nil), m.labels...), labels...)...)
+		return
+	}
+	m.mu.Lock()
+	m.series(name)[formatLabels(labels)] = value
+	sinks := m.sinks
+	m.mu.Unlock()
+
+	for _, sink := range sinks {
+		sink.Gauge(name, value, labels)
+	}
+}
+
+// AddCounter increments a counter; labels are alternating name/value pairs
+func (m *Metrics) AddCounter(name string, delta float64, labels ...string) {
+	if m.parent != nil {
+		m.parent.AddCounter(name, delta, append(append([]string(nil), m.labels...), labels...)...)
+		return
+	}
+	m.mu.Lock()
+	m.series(name)[formatLabels(labels)] += delta
+	sinks := m.sinks
+	m.mu.Unlock()
+
+	for _, sink := range sinks {
+		sink.Count(name, delta, labels)
+	}
+}
+
+// ObserveHistogram records an observation of a histogram declared in
+// histogramBuckets; labels are alternating name/value pairs
+func (m *Metrics) ObserveHistogram(name string, value float64, labels ...string) {
+	if m.parent != nil {
+		m.parent.ObserveHistogram(name, value, append(append([]string(nil), m.labels...), labels...)...)
+		return
+	}
+	bounds := histogramBuckets[name]
+	key := formatLabels(labels)
+
+	m.mu.Lock()
+	series, ok := m.hists[name]
+	if !ok {
+		series = make(map[string]*histogram)
+		m.hists[name] = series
+	}
+	h, ok := series[key]
+	if !ok {
+		h = &histogram{labels: labels, counts: make([]uint64, len(bounds)+1)}
+		series[key] = h
+	}
+	bucket := sort.SearchFloat64s(bounds, value)
+	h.counts[bucket]++
+	h.sum += value
+	h.count++
+	sinks := m.sinks
+	m.mu.Unlock()
+
+	for _, sink := range sinks {
+		if sink, ok := sink.(HistogramSink); ok {
+			sink.Histogram(name, value, labels)
+		}
+	}
+}
+
+// series returns the label-set map for a metric, creating it if needed
+func (m *Metrics) series(name string) map[string]float64 {
+	s, ok := m.values[name]
+	if !ok {
+		s = make(map[string]float64)
+		m.values[name] = s
+	}
+	return s
+}
+
+// WritePrometheus writes every recorded metric in the Prometheus text format
+func (m *Metrics) WritePrometheus(w io.Writer) {
+	if m.parent != nil {
+		m.parent.WritePrometheus(w)
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.values)+len(m.hists))
+	for name := range m.values {
+		names = append(names, name)
+	}
+	for name := range m.hists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if desc, ok := metricDescs[name]; ok {
+			fmt.Fprintf(w, "# HELP %s %s\n", name, desc.help)
+			fmt.Fprintf(w, "# TYPE %s %s\n", name, desc.kind)
+		}
+
+		if hists, ok := m.hists[name]; ok {
+			writeHistogram(w, name, hists)
+			continue
+		}
+
+		series := m.values[name]
+		labelSets := make([]string, 0, len(series))
+		for labels := range series {
+			labelSets = append(labelSets, labels)
+		}
+		sort.Strings(labelSets)
+
+		for _, labels := range labelSets {
+			fmt.Fprintf(w, "%s%s %g\n", name, labels, series[labels])
+		}
+	}
+}
+
+// writeHistogram writes every label set of a histogram as cumulative
+// buckets, a sum and a count
+func writeHistogram(w io.Writer, name string, series map[string]*histogram) {
+	labelSets := make([]string, 0, len(series))
+	for labels := range series {
+		labelSets = append(labelSets, labels)
+	}
+	sort.Strings(labelSets)
+
+	bounds := histogramBuckets[name]
+	for _, labels := range labelSets {
+		h := series[labels]
+		var cumulative uint64
+		for i, count := range h.counts {
+			cumulative += count
+			le := "+Inf"
+			if i < len(bounds) {
+				le = strconv.FormatFloat(bounds[i], 'g', -1, 64)
+			}
+			bucketLabels := append(append([]string(nil), h.labels...), "le", le)
+			fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(bucketLabels), cumulative)
+		}
+		fmt.Fprintf(w, "%s_sum%s %g\n", name, labels, h.sum)
+		fmt.Fprintf(w, "%s_count%s %d\n", name, labels, h.count)
+	}
+}
+
+// formatLabels renders alternating name/value pairs as a Prometheus label set
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", labels[i], labels[i+1]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,42 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrMLBusy is returned when every ML slot is in use and MLQueueDepth
+// requests are already waiting for one
+var ErrMLBusy = errors.New("ML request queue full")
+
+// acquireMLSlot takes one of the MLMaxConcurrency slots, waiting in the
+// bounded MLQueueDepth queue when all are in use. A full queue rejects the
+// request with ErrMLBusy rather than letting waiters pile up during ML
+// slowness. The returned func releases the slot.
+func (b *Bot) acquireMLSlot(ctx context.Context, endpoint string) (func(), error) {
+	release := func() { <-b.mlSlots }
+	select {
+	case b.mlSlots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	select {
+	case b.mlQueue <- struct{}{}:
+	default:
+		b.metrics.AddCounter(metricMLRejected, 1, "endpoint", endpoint)
+		return nil, ErrMLBusy
+	}
+	b.metrics.SetGauge(metricMLQueueDepth, float64(len(b.mlQueue)))
+	defer func() {
+		<-b.mlQueue
+		b.metrics.SetGauge(metricMLQueueDepth, float64(len(b.mlQueue)))
+	}()
+
+	select {
+	case b.mlSlots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
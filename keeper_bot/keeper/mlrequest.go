@@ -0,0 +1,109 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidMLRequest is returned when an ML request is missing required fields
+var ErrInvalidMLRequest = errors.New("invalid ML request")
+
+// MLRequest is a typed payload for an ML engine endpoint
+type MLRequest interface {
+	// Validate reports required fields that are unset
+	Validate() error
+}
+
+// LeverageHealthRequest is the leverage-health payload
+type LeverageHealthRequest struct {
+	TotalCollateral     float64 `json:"totalCollateral"`
+	TotalBorrowed       float64 `json:"totalBorrowed"`
+	CurrentHealthFactor float64 `json:"currentHealthFactor"`
+	AITValue            float64 `json:"aitValue"`
+}
+
+// Validate implements MLRequest. An unborrowed strategy legitimately has
+// zero debt and AIT, so only collateral and health factor are required.
+func (r LeverageHealthRequest) Validate() error {
+	return requireFields([]requiredField{
+		{"totalCollateral", r.TotalCollateral != 0},
+		{"currentHealthFactor", r.CurrentHealthFactor != 0},
+	})
+}
+
+// KYCRequest is the kyc-risk-assessment payload
+type KYCRequest struct {
+	InvestmentAmount     float64 `json:"investmentAmount"`
+	Tier                 int     `json:"tier"`
+	Jurisdiction         string  `json:"jurisdiction"`
+	TransactionFrequency int     `json:"transactionFrequency"`
+	WalletAgeDays        int     `json:"walletAgeDays"`
+	PreviousDefiExposure float64 `json:"previousDefiExposure"`
+}
+
+// Validate implements MLRequest
+func (r KYCRequest) Validate() error {
+	return requireFields([]requiredField{
+		{"investmentAmount", r.InvestmentAmount != 0},
+		{"tier", r.Tier != 0},
+		{"jurisdiction", r.Jurisdiction != ""},
+	})
+}
+
+// NAVRequest is the invoice-nav-prediction payload. Yield and default rate
+// are in basis points.
+type NAVRequest struct {
+	TotalFaceValue   float64 `json:"totalFaceValue"`
+	NumberOfInvoices int     `json:"numberOfInvoices"`
+	WeightedMaturity float64 `json:"weightedMaturity"`
+	ExpectedYield    float64 `json:"expectedYield"`
+	DefaultRate      float64 `json:"defaultRate"`
+	RealizedYield    float64 `json:"realizedYield"`
+	TotalSupply      float64 `json:"totalSupply"`
+}
+
+// Validate implements MLRequest
+func (r NAVRequest) Validate() error {
+	return requireFields([]requiredField{
+		{"totalFaceValue", r.TotalFaceValue != 0},
+		{"numberOfInvoices", r.NumberOfInvoices != 0},
+		{"totalSupply", r.TotalSupply != 0},
+	})
+}
+
+// InvoiceDefaultRequest is the invoice-default-prediction payload, asking for
+// one page of a pool's invoices
+type InvoiceDefaultRequest struct {
+	PoolID string `json:"poolId"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+// Validate implements MLRequest
+func (r InvoiceDefaultRequest) Validate() error {
+	return requireFields([]requiredField{
+		{"poolId", r.PoolID != ""},
+		{"limit", r.Limit != 0},
+	})
+}
+
+// requiredField is a request field and whether it holds a non-zero value
+type requiredField struct {
+	name string
+	set  bool
+}
+
+// requireFields returns ErrInvalidMLRequest naming every field that is not set
+func requireFields(fields []requiredField) error {
+	var missing []string
+	for _, field := range fields {
+		if !field.set {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrInvalidMLRequest, strings.Join(missing, ", "))
+	}
+	return nil
+}
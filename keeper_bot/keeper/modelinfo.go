@@ -0,0 +1,182 @@
+package keeper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MLSchemaVersion is the ML request/response schema this client speaks. A
+// different major version may rename fields, which would otherwise decode
+// silently to zero values.
+const MLSchemaVersion = "1.0"
+
+// Headers advertising the client's schema version on every ML request
+const (
+	acceptVersionHeader = "Accept-Version"
+	clientVersionHeader = "X-Client-Version"
+)
+
+// errModelInfoUnsupported is returned by ML engines predating /model-info
+var errModelInfoUnsupported = errors.New("ML engine does not serve /model-info")
+
+// ErrMLSchemaMismatch is returned when the ML engine advertises a schema
+// major version the client does not speak
+var ErrMLSchemaMismatch = errors.New("incompatible ML schema version")
+
+// ModelInfo describes the model the ML engine is serving
+type ModelInfo struct {
+	Version   string    `json:"model_version"`
+	TrainedAt time.Time `json:"trained_at"` // zero when the engine does not know
+	// SchemaVersion is the engine's request/response schema; empty for
+	// engines that do not advertise one
+	SchemaVersion string `json:"schema_version"`
+}
+
+// negotiateMLSchema checks at startup that the ML engine speaks a compatible
+// schema major version. An engine that is unreachable or advertises no
+// version is only warned about, since the fallback policy covers outages.
+func (b *Bot) negotiateMLSchema(ctx context.Context) error {
+	info, err := b.fetchModelInfo(ctx)
+	if err != nil {
+		b.logger.WithError(err).Warn("Could not read ML schema version at startup, continuing without negotiation")
+		return nil
+	}
+
+	b.mutex.Lock()
+	b.modelInfo = info
+	b.mutex.Unlock()
+
+	logger := b.logger.WithFields(logrus.Fields{
+		"client_schema": MLSchemaVersion,
+		"ml_schema":     info.SchemaVersion,
+	})
+	if info.SchemaVersion == "" {
+		logger.Warn("ML engine does not advertise a schema version, responses cannot be checked for compatibility")
+		return nil
+	}
+	if err := checkSchemaVersion(info.SchemaVersion); err != nil {
+		return err
+	}
+	logger.Info("ML schema version compatible")
+	return nil
+}
+
+// checkSchemaVersion compares the major version of an advertised ML schema
+// with MLSchemaVersion
+func checkSchemaVersion(advertised string) error {
+	want, _, _ := strings.Cut(MLSchemaVersion, ".")
+	got, _, _ := strings.Cut(strings.TrimPrefix(advertised, "v"), ".")
+	if got != want {
+		return fmt.Errorf("%w: ML engine speaks %s, client speaks %s", ErrMLSchemaMismatch, advertised, MLSchemaVersion)
+	}
+	return nil
+}
+
+// checkModelInfo fetches the served model and alerts when it was trained
+// more than MaxModelAge ago. An engine without /model-info is tolerated.
+func (b *Bot) checkModelInfo(ctx context.Context) {
+	config := b.cfg()
+	info, err := b.fetchModelInfo(ctx)
+	if errors.Is(err, errModelInfoUnsupported) {
+		b.logger.Debug("ML engine has no /model-info endpoint, skipping model staleness check")
+		return
+	}
+	if err != nil {
+		b.logger.WithError(err).Warn("Failed to fetch ML model info")
+		return
+	}
+
+	b.mutex.Lock()
+	b.modelInfo = info
+	b.mutex.Unlock()
+
+	// The engine may have been redeployed since startup negotiation
+	if info.SchemaVersion != "" {
+		if err := checkSchemaVersion(info.SchemaVersion); err != nil {
+			b.sendDeduped("ml_schema:"+info.SchemaVersion, Alert{
+				Severity: AlertCritical,
+				Title:    "ML engine schema version incompatible",
+				Fields: map[string]interface{}{
+					"ml_schema":     info.SchemaVersion,
+					"client_schema": MLSchemaVersion,
+				},
+			})
+		}
+	}
+
+	logger := b.logger.WithField("model_version", info.Version)
+	if info.TrainedAt.IsZero() {
+		logger.Info("ML model info: training date unknown")
+		return
+	}
+
+	age := time.Since(info.TrainedAt)
+	logger = logger.WithFields(logrus.Fields{
+		"trained_at": info.TrainedAt,
+		"model_age":  age.Round(time.Hour).String(),
+	})
+	if config.MaxModelAge > 0 && age > config.MaxModelAge {
+		b.sendDeduped("model_stale:"+info.Version, Alert{
+			Severity: AlertWarning,
+			Title:    "ML model is stale",
+			Fields: map[string]interface{}{
+				"model_version": info.Version,
+				"trained_at":    info.TrainedAt,
+				"max_model_age": config.MaxModelAge.String(),
+			},
+		})
+		return
+	}
+	logger.Info("ML model info: OK")
+}
+
+// fetchModelInfo reads the ML engine /model-info endpoint, bounded by
+// HealthCheckTimeout
+func (b *Bot) fetchModelInfo(ctx context.Context) (*ModelInfo, error) {
+	config := b.cfg()
+	infoURL, err := url.JoinPath(config.MLAPIEndpoint, "model-info")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.HealthCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, infoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	b.setMLHeaders(req)
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return nil, errModelInfoUnsupported
+	default:
+		return nil, fmt.Errorf("ML engine returned status %d", resp.StatusCode)
+	}
+
+	body, err := readLimited(resp.Body, config.MaxResponseBytes)
+	if err != nil {
+		return nil, err
+	}
+	var info ModelInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse model info: %w", err)
+	}
+	return &info, nil
+}
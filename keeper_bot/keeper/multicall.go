@@ -0,0 +1,145 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultMulticall3Addr is where Multicall3 is deployed on most EVM chains
+const defaultMulticall3Addr = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+const multicall3ABIJSON = `[
+	{"type":"function","name":"aggregate3","stateMutability":"payable","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}]}],"outputs":[{"name":"returnData","type":"tuple[]","components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}]}]}
+]`
+
+var multicall3ABI = mustParseABI(multicall3ABIJSON)
+
+// multicall3Call and multicall3Result mirror the aggregate3 tuples
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// contractCall is one read-only contract call of a batch
+type contractCall struct {
+	abi      abi.ABI
+	contract common.Address
+	method   string
+	args     []interface{}
+}
+
+// callResult holds the unpacked outputs of a batched call or its error
+type callResult struct {
+	out []interface{}
+	err error
+}
+
+// callRevertError is a batched call that reverted. It carries the revert
+// data like the RPC error of a direct call, so isRevert and revertReason
+// treat both alike.
+type callRevertError struct {
+	data []byte
+}
+
+func (e *callRevertError) Error() string {
+	if reason, err := abi.UnpackRevert(e.data); err == nil {
+		return "execution reverted: " + reason
+	}
+	return "execution reverted"
+}
+
+func (e *callRevertError) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+// detectMulticall enables batched reads when Multicall3Addr holds a
+// contract. Anything else leaves reads one call each, which works on any
+// chain, so a missing or unreachable contract is logged rather than fatal.
+func (b *Bot) detectMulticall(ctx context.Context) error {
+	config := b.cfg()
+	if config.Multicall3Addr == "" {
+		return nil
+	}
+	if !common.IsHexAddress(config.Multicall3Addr) {
+		return fmt.Errorf("invalid Multicall3 address %q", config.Multicall3Addr)
+	}
+
+	address := common.HexToAddress(config.Multicall3Addr)
+	logger := b.logger.WithField("multicall3", address.Hex())
+	code, err := b.eth().CodeAt(ctx, address, nil)
+	switch {
+	case err != nil:
+		logger.WithError(err).Warn("Failed to check for Multicall3, reading contracts one call at a time")
+	case len(code) == 0:
+		logger.Warn("No Multicall3 contract deployed, reading contracts one call at a time")
+	default:
+		logger.Debug("Batching contract reads through Multicall3")
+		b.multicall = &address
+	}
+	return nil
+}
+
+// batchCall executes read-only calls at block (nil for latest), in a single
+// Multicall3 aggregate3 call when available. If the aggregate call itself
+// fails the calls are retried one at a time. A call that reverts fails on
+// its own without failing the batch.
+func (b *Bot) batchCall(ctx context.Context, block *big.Int, calls []contractCall) []callResult {
+	if b.multicall != nil && len(calls) > 1 {
+		results, err := b.aggregateCalls(ctx, block, calls)
+		if err == nil {
+			return results
+		}
+		b.logger.WithError(err).WithField("calls", len(calls)).Warn("Multicall3 batch failed, reading contracts one call at a time")
+		b.metrics.AddCounter(metricMulticallFallbacks, 1)
+	}
+
+	results := make([]callResult, len(calls))
+	for i, call := range calls {
+		results[i].out, results[i].err = b.callContract(ctx, block, call.abi, call.contract, call.method, call.args...)
+	}
+	return results
+}
+
+// aggregateCalls executes calls through Multicall3's aggregate3, allowing
+// each to fail individually
+func (b *Bot) aggregateCalls(ctx context.Context, block *big.Int, calls []contractCall) ([]callResult, error) {
+	packed := make([]multicall3Call, len(calls))
+	for i, call := range calls {
+		data, err := call.abi.Pack(call.method, call.args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack %s: %w", call.method, err)
+		}
+		packed[i] = multicall3Call{Target: call.contract, AllowFailure: true, CallData: data}
+	}
+
+	out, err := b.callContract(ctx, block, multicall3ABI, *b.multicall, "aggregate3", packed)
+	if err != nil {
+		return nil, err
+	}
+	returned := *abi.ConvertType(out[0], new([]multicall3Result)).(*[]multicall3Result)
+	if len(returned) != len(calls) {
+		return nil, fmt.Errorf("aggregate3 returned %d results for %d calls", len(returned), len(calls))
+	}
+
+	results := make([]callResult, len(calls))
+	for i, call := range calls {
+		if !returned[i].Success {
+			results[i].err = fmt.Errorf("%s call failed: %w", call.method, &callRevertError{data: returned[i].ReturnData})
+			continue
+		}
+		results[i].out, results[i].err = call.abi.Unpack(call.method, returned[i].ReturnData)
+	}
+
+	b.logger.WithFields(logrus.Fields{"calls": len(calls)}).Debug("Contract reads batched through Multicall3")
+	return results, nil
+}
@@ -3,64 +3,357 @@ package keeper
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"slices"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/sirupsen/logrus"
 )
 
-func (b *Bot) UpdateInvoiceNAV(ctx context.Context) error {
+// UpdateInvoiceNAV predicts and publishes the NAV of every configured invoice
+// token, returning one result per token. A failure on one token does not
+// stop the others from being updated. Per-invoice default predictions are
+// checked alongside when enabled.
+func (b *Bot) UpdateInvoiceNAV(ctx context.Context) ([]NAVResult, error) {
+	if !b.navEnabled() {
+		return nil, nil
+	}
+	if !b.tryStartRun("nav", &b.navRun) {
+		return nil, nil
+	}
+	defer b.navRun.Unlock()
+
 	b.logger.Info("Updating invoice token NAV...")
 
-	navData := map[string]interface{}{
-		"totalFaceValue":   5000000,
-		"numberOfInvoices": 100,
-		"weightedMaturity": 90,
-		"expectedYield":    800, // 8% in basis points
-		"defaultRate":      300, // 3% in basis points
-		"realizedYield":    200000,
-		"totalSupply":      4800000,
+	results := make([]NAVResult, 0, len(b.invoiceTokens))
+	var errs []error
+	for _, read := range b.readPools(ctx) {
+		token := read.token
+		result, err := b.updateTokenNAV(ctx, read)
+		if err != nil {
+			b.logger.WithError(err).WithField("token", token.Hex()).Error("Invoice token NAV update failed")
+			result.Outcome = "failed"
+			result.Error = err.Error()
+			errs = append(errs, fmt.Errorf("token %s: %w", token.Hex(), err))
+		}
+		b.metrics.AddCounter(metricNAVUpdates, 1, "token", token.Hex(), "result", result.Outcome)
+
+		if b.invoiceDefaultsEnabled() {
+			flagged, err := b.checkInvoiceDefaults(ctx, token)
+			result.DefaultsFlagged = flagged
+			if err != nil {
+				b.logger.WithError(err).WithField("token", token.Hex()).Error("Invoice default check failed")
+				errs = append(errs, fmt.Errorf("token %s defaults: %w", token.Hex(), err))
+			}
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+// updateTokenNAV predicts one invoice token's NAV from its pool data and
+// publishes it if the prediction is confident and moves the on-chain value.
+// An on-chain NAV older than MaxNAVAge is replaced at a relaxed confidence
+// floor even when the prediction leaves it unchanged.
+func (b *Bot) updateTokenNAV(ctx context.Context, read poolRead) (NAVResult, error) {
+	config := b.cfg()
+	token := read.token
+	result := NAVResult{Token: token.Hex()}
+	logger := b.logger.WithField("token", token.Hex())
+
+	// A restart mid-cycle must not publish a second NAV for the same period,
+	// while a NAV consumers have gone too long without is forced through
+	age, err := b.navAge(ctx, token)
+	if err != nil {
+		return result, err
+	}
+	if config.MinNAVUpdateInterval > 0 && age < config.MinNAVUpdateInterval {
+		logger.Info("NAV already updated this period, skipping")
+		result.Outcome = "already_updated"
+		return result, nil
+	}
+	result.Forced = config.MaxNAVAge > 0 && age > config.MaxNAVAge
+
+	if read.err != nil {
+		return result, read.err
 	}
+	block, navData := read.block, read.pool
+	ctx = withIdempotencyKey(ctx, idempotencyKey("nav_update", token, block))
+	result.Pool = &navData
 
-	response, err := b.callMLAPI("/api/v1/invoice-nav-prediction", navData)
+	response, err := b.callMLAPI(ctx, "invoice-nav-prediction", navData)
 	if err != nil {
-		return fmt.Errorf("NAV prediction failed: %w", err)
+		return result, fmt.Errorf("NAV prediction failed: %w", err)
 	}
 
 	var navResp NAVPredictionResponse
 	if err := json.Unmarshal(response, &navResp); err != nil {
-		return fmt.Errorf("failed to parse NAV response: %w", err)
+		return result, fmt.Errorf("failed to parse NAV response: %w", err)
+	}
+
+	b.observeMLClock(navResp.Timestamp)
+	predicted := navResp.PredictedNAV.Rat()
+	result.PredictedNAV = roundTo(navResp.PredictedNAV.Float64(), config.NAVPrecision)
+	result.PredictedNAVWei = formatNAVWei(predicted)
+	result.Confidence = b.score(navResp.Confidence)
+	logger.WithFields(b.navFields("predicted_nav", predicted)).
+		WithField("confidence", result.Confidence).
+		Info("NAV prediction completed")
+
+	// NAV writes are held to the strictest confidence floor, relaxed only
+	// when the on-chain NAV is stale
+	floor := b.degradedFloor(config.MinNAVConfidence)
+	if result.Forced {
+		floor = math.Min(floor, b.degradedFloor(config.ForcedNAVMinConfidence))
+		logger.WithFields(logrus.Fields{
+			"nav_age":        age.Round(time.Second).String(),
+			"max_nav_age":    config.MaxNAVAge.String(),
+			"confidence":     result.Confidence,
+			"min_confidence": floor,
+		}).Warn("On-chain NAV exceeds maximum age, forcing update")
+	}
+	err = checkAssessment(time.Now(), navResp.Timestamp, navResp.Confidence, floor, config.MaxAssessmentAge)
+	if errors.Is(err, ErrStaleAssessment) {
+		b.warnSampled("nav_stale:"+token.Hex(), logger.WithError(err), "Stale NAV prediction, skipping update")
+		result.Outcome = "stale"
+		return result, nil
+	}
+	if err != nil {
+		b.warnSampled("nav_low_confidence:"+token.Hex(), logger.WithError(err), "Low confidence NAV prediction, skipping update")
+		result.Outcome = "low_confidence"
+		return result, nil
 	}
 
-	b.logger.WithFields(logrus.Fields{
-		"predicted_nav": navResp.PredictedNAV,
-		"confidence":    navResp.Confidence,
-	}).Info("NAV prediction completed")
+	newNAV, changed, err := b.smoothNAV(ctx, token, block, predicted)
+	if err != nil {
+		return result, err
+	}
+	if !changed && !result.Forced {
+		result.Outcome = "unchanged"
+		return result, nil
+	}
+	published, _ := newNAV.Float64()
+	result.PublishedNAV = roundTo(published, config.NAVPrecision)
+	result.PublishedNAVWei = formatNAVWei(newNAV)
+
+	if b.degradedBlocks("nav_update") {
+		logger.Warn("Degraded mode: NAV update suspended")
+		b.metrics.AddCounter(metricTxSkipped, 1, "reason", "degraded")
+		result.Outcome = "degraded"
+		return result, nil
+	}
+
+	if config.NAVSubmitMode == NAVSubmitAttest {
+		navWei := toFixedPoint(newNAV, navDecimals)
+		attestation, err := b.attestNAV(ctx, token, navWei, navResp.Confidence, block)
+		if err != nil {
+			return result, err
+		}
+		result.Outcome = "attested"
+		result.Signature = attestation.Signature.String()
+		b.recordPublishedNAV(token, newNAV)
+		b.publishNAVInputs(ctx, token, &result, predicted, newNAV, navResp.Confidence, block)
+		return result, nil
+	}
+
+	tx, err := b.updateNAVOnChain(ctx, token, newNAV)
+	if err != nil {
+		return result, err
+	}
+	result.Outcome = "updated"
+	result.TxHash = tx.Hash().Hex()
+	b.recordPublishedNAV(token, newNAV)
+	b.publishNAVInputs(ctx, token, &result, predicted, newNAV, navResp.Confidence, block)
+	result.EstimatedGas = b.estimateUnsentGas(ctx, tx)
+	return result, nil
+}
 
-	// Update NAV if confidence is high enough
-	if navResp.Confidence > 0.7 {
-		return b.updateNAVOnChain(ctx, navResp.PredictedNAV)
+// publishNAVInputs attests the inputs of a published NAV into result; a
+// failure is logged and leaves the published NAV standing
+func (b *Bot) publishNAVInputs(ctx context.Context, token common.Address, result *NAVResult, predicted, published *big.Rat, confidence float64, block *big.Int) {
+	attestation, err := b.attestNAVInputs(ctx, token, *result.Pool, predicted, published, confidence, block)
+	if err != nil {
+		b.logger.WithError(err).WithField("token", token.Hex()).Warn("NAV published without an input attestation")
+		return
 	}
+	result.InputDigest = attestation.Digest.Hex()
+}
 
-	b.logger.Warn("Low confidence NAV prediction, skipping update")
+// recordPublishedNAV exposes a token's published NAV in human units and wei
+func (b *Bot) recordPublishedNAV(token common.Address, nav *big.Rat) {
+	human, _ := nav.Float64()
+	wei, _ := new(big.Rat).SetInt(toFixedPoint(nav, navDecimals)).Float64()
+	b.metrics.SetGauge(metricInvoiceNAV, roundTo(human, b.cfg().NAVPrecision), "token", token.Hex())
+	b.metrics.SetGauge(metricInvoiceNAVWei, wei, "token", token.Hex())
+}
+
+// navAge returns how long ago the token's on-chain NAV was updated, or zero
+// when neither MinNAVUpdateInterval nor MaxNAVAge needs it. It reads the
+// latest state so a just-mined update is seen even when NAV reads use a
+// lagging block tag.
+func (b *Bot) navAge(ctx context.Context, token common.Address) (time.Duration, error) {
+	config := b.cfg()
+	if config.MinNAVUpdateInterval <= 0 && config.MaxNAVAge <= 0 {
+		return 0, nil
+	}
+	out, err := b.callContract(ctx, nil, b.abis.invoiceToken, token, "lastNavUpdate")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read last NAV update: %w", err)
+	}
+	return time.Since(time.Unix(out[0].(*big.Int).Int64(), 0)), nil
+}
+
+// validateNAVAge checks a forced update can never fall inside the
+// MinNAVUpdateInterval that would skip it
+func validateNAVAge(config *Config) error {
+	if config.MaxNAVAge > 0 && config.MaxNAVAge <= config.MinNAVUpdateInterval {
+		return fmt.Errorf("max NAV age %s must exceed min NAV update interval %s", config.MaxNAVAge, config.MinNAVUpdateInterval)
+	}
 	return nil
 }
 
-// updateNAVOnChain updates NAV on the smart contract
-func (b *Bot) updateNAVOnChain(ctx context.Context, newNAV float64) error {
-	auth, err := b.getTransactOpts(ctx)
+// poolRead is an invoice token's pool and the outcome of reading it
+type poolRead struct {
+	token common.Address
+	block *big.Int
+	pool  NAVRequest
+	err   error
+}
+
+// readPools reads every invoice token's pool at one resolved block,
+// batching all their calls into a single RPC call when Multicall3 is
+// available. A pool that fails to read carries its error.
+func (b *Bot) readPools(ctx context.Context) []poolRead {
+	reads := make([]poolRead, len(b.invoiceTokens))
+	calls := make([][]contractCall, len(b.invoiceTokens))
+	for i, token := range b.invoiceTokens {
+		reads[i].token = token
+		calls[i] = b.poolCalls(token)
+	}
+
+	block, err := b.resolveBlock(ctx, b.navBlock)
 	if err != nil {
-		return err
+		for i := range reads {
+			reads[i].err = err
+		}
+		return reads
+	}
+
+	results := b.batchCall(ctx, block, slices.Concat(calls...))
+	for i := range reads {
+		n := len(calls[i])
+		reads[i].block = block
+		reads[i].pool, reads[i].err = b.decodePool(reads[i].token, results[:n])
+		if reads[i].err != nil {
+			reads[i].err = fmt.Errorf("failed to read pool data: %w", reads[i].err)
+		}
+		results = results[n:]
 	}
+	return reads
+}
+
+// readPool reads an invoice token's underlying pool at block into a NAV request
+func (b *Bot) readPool(ctx context.Context, token common.Address, block *big.Int) (NAVRequest, error) {
+	return b.decodePool(token, b.batchCall(ctx, block, b.poolCalls(token)))
+}
 
-	// Convert to wei (assuming 6 decimals for USDC compatibility)
-	navWei := big.NewInt(int64(newNAV * 1e6))
+// poolCalls are the contract reads making up a NAV request
+func (b *Bot) poolCalls(token common.Address) []contractCall {
+	return []contractCall{
+		{abi: b.abis.invoiceToken, contract: token, method: "pool"},
+		{abi: b.abis.invoiceToken, contract: token, method: "totalSupply"},
+	}
+}
+
+// decodePool builds a NAV request from the results of its poolCalls,
+// failing with the first call's error
+func (b *Bot) decodePool(token common.Address, results []callResult) (NAVRequest, error) {
+	for _, result := range results {
+		if result.err != nil {
+			return NAVRequest{}, result.err
+		}
+	}
+	pool, supply := results[0].out, results[1].out
+
+	// Pool amounts share the token's decimals; the contract derives NAV
+	// from face value over supply
+	decimals := b.tokenDecimals[token]
+	return NAVRequest{
+		TotalFaceValue:   scaleAmount(pool[1].(*big.Int), decimals),
+		NumberOfInvoices: int(pool[2].(*big.Int).Int64()),
+		WeightedMaturity: float64(pool[3].(*big.Int).Int64()),
+		ExpectedYield:    float64(pool[4].(*big.Int).Int64()),
+		RealizedYield:    scaleAmount(pool[5].(*big.Int), decimals),
+		DefaultRate:      float64(pool[6].(*big.Int).Int64()),
+		TotalSupply:      scaleAmount(supply[0].(*big.Int), decimals),
+	}, nil
+}
 
-	// In production: call updateNav on VeritasInvoiceToken contract
-	b.logger.WithFields(logrus.Fields{
-		"nav_wei": navWei.String(),
-		"nonce":   auth.Nonce,
+// smoothNAV blends a predicted NAV with the current on-chain NAV using
+// NAVSmoothingAlpha and reports whether the result moves the on-chain value
+// by at least MinNAVChange. With smoothing and the change floor both
+// disabled the prediction is passed through without reading the chain.
+// Arithmetic is exact so the published value carries no float rounding.
+func (b *Bot) smoothNAV(ctx context.Context, token common.Address, block *big.Int, predicted *big.Rat) (*big.Rat, bool, error) {
+	alpha := b.cfg().NAVSmoothingAlpha
+	smoothingEnabled := alpha > 0 && alpha < 1
+	minChange := b.degradedMinNAVChange()
+	if !smoothingEnabled && minChange <= 0 {
+		return predicted, true, nil
+	}
+
+	out, err := b.callContract(ctx, block, b.abis.invoiceToken, token, "navPerToken")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read on-chain NAV: %w", err)
+	}
+	current := fromFixedPoint(out[0].(*big.Int), navDecimals)
+
+	smoothed := predicted
+	if smoothingEnabled {
+		weight := new(big.Rat).SetFloat64(alpha)
+		smoothed = new(big.Rat).Sub(predicted, current)
+		smoothed.Mul(smoothed, weight).Add(smoothed, current)
+	}
+	change := new(big.Rat).Sub(smoothed, current)
+	change.Abs(change)
+
+	logger := b.logger.WithFields(logrus.Fields{
+		"token":  token.Hex(),
+		"alpha":  alpha,
+		"change": b.formatNAV(change),
+	}).
+		WithFields(b.navFields("onchain_nav", current)).
+		WithFields(b.navFields("predicted_nav", predicted)).
+		WithFields(b.navFields("smoothed_nav", smoothed))
+
+	if change.Cmp(new(big.Rat).SetFloat64(minChange)) < 0 {
+		logger.Info("Smoothed NAV change below minimum, skipping on-chain update")
+		return smoothed, false, nil
+	}
+
+	logger.Info("Smoothed NAV computed")
+	return smoothed, true, nil
+}
+
+// updateNAVOnChain updates an invoice token's NAV on the smart contract
+func (b *Bot) updateNAVOnChain(ctx context.Context, token common.Address, newNAV *big.Rat) (*types.Transaction, error) {
+	// NAV is stored with 6 decimals for USDC compatibility
+	navWei := toFixedPoint(newNAV, navDecimals)
+
+	tx, err := b.sendTx(ctx, "nav_update", b.abis.invoiceToken, token, "updateNav", navWei)
+	if err != nil {
+		return nil, err
+	}
+
+	b.logger.WithFields(b.navFields("nav", newNAV)).WithFields(logrus.Fields{
+		"token": token.Hex(),
+		"tx":    tx.Hash().Hex(),
 	}).Info("NAV update transaction sent")
 
-	return nil
+	return tx, nil
 }
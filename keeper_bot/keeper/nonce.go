@@ -0,0 +1,26 @@
+package keeper
+
+// nextNonce returns the nonce to sign with: the node's pending nonce, or the
+// one after the keeper's last broadcast when the node has not seen it yet.
+// Without this, a transaction sent right after another (e.g. an action
+// after its approval) can be signed with the same nonce and replace it.
+func (b *Bot) nextNonce(pending uint64) uint64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return max(pending, b.localNonce)
+}
+
+// noteNonceUsed records that a transaction with nonce was broadcast
+func (b *Bot) noteNonceUsed(nonce uint64) {
+	b.mutex.Lock()
+	b.localNonce = max(b.localNonce, nonce+1)
+	b.mutex.Unlock()
+}
+
+// resetNonce forgets locally tracked nonces so the next transaction is
+// signed with the node's pending nonce, e.g. after the node rejected one
+func (b *Bot) resetNonce() {
+	b.mutex.Lock()
+	b.localNonce = 0
+	b.mutex.Unlock()
+}
@@ -0,0 +1,87 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Monitor names accepted by PauseMonitor and ResumeMonitor
+const (
+	MonitorLeverage = "leverage"
+	MonitorNAV      = "nav"
+	MonitorKYC      = "kyc"
+	MonitorHealth   = "health"
+)
+
+// ErrUnknownMonitor is returned when pausing or resuming an unknown monitor
+var ErrUnknownMonitor = errors.New("unknown monitor")
+
+// MonitorState is a monitor's scheduling state as reported on /status
+type MonitorState struct {
+	Name string `json:"name"`
+	// Enabled is false when the monitor's contracts are not configured
+	Enabled bool `json:"enabled"`
+	// Paused is set by an operator through the admin API, or when the
+	// monitor reaches MaxConsecutiveFailures with FailureAction disable
+	Paused bool `json:"paused"`
+}
+
+// PauseMonitor stops the scheduled runs of a monitor until ResumeMonitor.
+// A run already in progress finishes.
+func (b *Bot) PauseMonitor(name string) error {
+	return b.setMonitorPaused(name, true)
+}
+
+// ResumeMonitor restarts the scheduled runs of a paused monitor
+func (b *Bot) ResumeMonitor(name string) error {
+	return b.setMonitorPaused(name, false)
+}
+
+// setMonitorPaused records an operator pause or resume of a monitor
+func (b *Bot) setMonitorPaused(name string, paused bool) error {
+	if !isMonitor(name) {
+		return fmt.Errorf("%w: %q (want leverage, nav, kyc or health)", ErrUnknownMonitor, name)
+	}
+
+	b.mutex.Lock()
+	b.pausedMonitors[name] = paused
+	if !paused {
+		// A monitor disabled by MaxConsecutiveFailures gets a fresh streak
+		b.resetFailures(name)
+	}
+	b.mutex.Unlock()
+
+	if paused {
+		b.logger.WithField("monitor", name).Warn("Monitor paused by operator")
+	} else {
+		b.logger.WithField("monitor", name).Info("Monitor resumed by operator")
+	}
+	return nil
+}
+
+// monitorPaused reports whether an operator has paused a monitor
+func (b *Bot) monitorPaused(name string) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.pausedMonitors[name]
+}
+
+// monitorStates lists every monitor's state; callers must hold mutex
+func (b *Bot) monitorStates() []MonitorState {
+	return []MonitorState{
+		{Name: MonitorLeverage, Enabled: b.leverageEnabled(), Paused: b.pausedMonitors[MonitorLeverage]},
+		{Name: MonitorNAV, Enabled: b.navEnabled(), Paused: b.pausedMonitors[MonitorNAV]},
+		{Name: MonitorKYC, Enabled: b.kycEnabled(), Paused: b.pausedMonitors[MonitorKYC]},
+		{Name: MonitorHealth, Enabled: true, Paused: b.pausedMonitors[MonitorHealth]},
+	}
+}
+
+// isMonitor reports whether name is a pausable monitor
+func isMonitor(name string) bool {
+	switch name {
+	case MonitorLeverage, MonitorNAV, MonitorKYC, MonitorHealth:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,126 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+// pendingPollInterval is how often the confirmed nonce is polled while
+// waiting for recovered transactions
+const pendingPollInterval = 5 * time.Second
+
+// recoveredTx is a keeper transaction a previous run left in the mempool
+type recoveredTx struct {
+	Hash  common.Hash     `json:"hash"`
+	To    *common.Address `json:"to"`
+	Nonce uint64          `json:"-"`
+}
+
+// recoverPendingTxs adopts transactions a previous run left pending, found as
+// the gap between the keeper's pending and confirmed nonces. Their in-flight
+// slots are held and it blocks until they confirm or TxConfirmTimeout
+// elapses, so new actions are not scheduled on top of them.
+func (b *Bot) recoverPendingTxs(ctx context.Context) error {
+	config := b.cfg()
+	if !config.RecoverPendingTx {
+		return nil
+	}
+
+	address := b.keeperAddress()
+	confirmed, err := b.eth().NonceAt(ctx, address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read confirmed nonce: %w", err)
+	}
+	pending, err := b.eth().PendingNonceAt(ctx, address)
+	if err != nil {
+		return fmt.Errorf("failed to read pending nonce: %w", err)
+	}
+	if pending <= confirmed {
+		return nil
+	}
+
+	logger := b.logger.WithFields(logrus.Fields{
+		"confirmed_nonce": confirmed,
+		"pending_nonce":   pending,
+		"count":           pending - confirmed,
+	})
+	logger.Warn("Recovered pending transactions from a previous run")
+	for _, tx := range b.pendingFromTxpool(ctx, address) {
+		entry := b.logger.WithFields(logrus.Fields{"nonce": tx.Nonce, "tx": tx.Hash.Hex()})
+		if tx.To != nil {
+			entry = entry.WithField("to", tx.To.Hex())
+		}
+		entry.Warn("Recovered pending transaction")
+	}
+
+	// Count them against MaxInFlightTx while they are outstanding
+	held := 0
+	for i := confirmed; i < pending && b.acquireTxSlot(); i++ {
+		held++
+	}
+	defer func() {
+		for ; held > 0; held-- {
+			b.releaseTxSlot()
+		}
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, config.TxConfirmTimeout)
+	defer cancel()
+	for {
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			b.alerter.Send(Alert{
+				Severity: AlertWarning,
+				Title:    "Pending transactions from a previous run did not confirm",
+				Fields: map[string]interface{}{
+					"address":         address.Hex(),
+					"confirmed_nonce": confirmed,
+					"pending_nonce":   pending,
+					"timeout":         config.TxConfirmTimeout.String(),
+				},
+			})
+			return fmt.Errorf("%d pending transactions unconfirmed after %s", pending-confirmed, config.TxConfirmTimeout)
+		case <-time.After(pendingPollInterval):
+		}
+
+		nonce, err := b.eth().NonceAt(waitCtx, address, nil)
+		if err != nil {
+			b.logger.WithError(err).Debug("Confirmed nonce poll failed")
+			continue
+		}
+		if nonce >= pending {
+			logger.WithField("confirmed_nonce", nonce).Info("Recovered pending transactions confirmed")
+			return nil
+		}
+	}
+}
+
+// pendingFromTxpool lists the keeper's pending transactions through the
+// txpool_contentFrom RPC, ordered by nonce. Nodes without the txpool API
+// return nothing; recovery then works from nonces alone.
+func (b *Bot) pendingFromTxpool(ctx context.Context, address common.Address) []recoveredTx {
+	var content struct {
+		Pending map[string]recoveredTx `json:"pending"`
+	}
+	if err := b.eth().Client().CallContext(ctx, &content, "txpool_contentFrom", address); err != nil {
+		b.logger.WithError(err).Debug("txpool_contentFrom unavailable, pending transaction hashes unknown")
+		return nil
+	}
+
+	txs := make([]recoveredTx, 0, len(content.Pending))
+	for nonce, tx := range content.Pending {
+		tx.Nonce, _ = strconv.ParseUint(nonce, 10, 64)
+		txs = append(txs, tx)
+	}
+	sort.Slice(txs, func(i, j int) bool { return txs[i].Nonce < txs[j].Nonce })
+	return txs
+}
@@ -0,0 +1,96 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+// Receipt polling starts fast right after broadcast and backs off towards the cap
+const (
+	receiptPollMin     = time.Second
+	receiptPollMax     = 15 * time.Second
+	receiptPollBackoff = 1.5
+)
+
+// waitForConfirmations polls for a transaction's receipt until it is
+// TxConfirmations blocks deep on the canonical chain, or ctx expires. If the
+// including block is reorganized out, waiting resumes until the transaction
+// is re-included. It returns the final receipt and its confirmation depth.
+func (b *Bot) waitForConfirmations(ctx context.Context, tx *types.Transaction) (*types.Receipt, uint64, error) {
+	required := b.cfg().TxConfirmations
+	if required == 0 {
+		required = 1
+	}
+
+	logger := b.logger.WithField("tx", tx.Hash().Hex())
+	interval := receiptPollMin
+	var seen *types.Receipt
+
+	for {
+		receipt, depth, err := b.receiptDepth(ctx, tx)
+		switch {
+		case errors.Is(err, ethereum.NotFound):
+			if seen != nil {
+				logger.WithField("block", seen.BlockNumber).Warn("Transaction reorganized out, waiting for re-inclusion")
+				seen = nil
+			}
+		case err != nil:
+			logger.WithError(err).Debug("Receipt poll failed")
+		default:
+			if seen != nil && seen.BlockHash != receipt.BlockHash {
+				logger.WithFields(logrus.Fields{
+					"old_block": seen.BlockHash.Hex(),
+					"new_block": receipt.BlockHash.Hex(),
+				}).Warn("Transaction re-included after reorg")
+			}
+			seen = receipt
+			if depth >= required {
+				return receipt, depth, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		interval = time.Duration(float64(interval) * receiptPollBackoff)
+		if interval > receiptPollMax {
+			interval = receiptPollMax
+		}
+	}
+}
+
+// receiptDepth fetches a receipt and its confirmation depth, returning
+// ethereum.NotFound if the receipt's block is no longer canonical
+func (b *Bot) receiptDepth(ctx context.Context, tx *types.Transaction) (*types.Receipt, uint64, error) {
+	receipt, err := b.eth().TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	head, err := b.eth().BlockNumber(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	header, err := b.eth().HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return nil, 0, err
+	}
+	if header.Hash() != receipt.BlockHash {
+		return nil, 0, ethereum.NotFound
+	}
+
+	included := receipt.BlockNumber.Uint64()
+	if head < included {
+		return receipt, 0, nil
+	}
+	return receipt, head - included + 1, nil
+}
@@ -0,0 +1,140 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	reconnectMinBackoff = time.Second
+	reconnectMaxBackoff = time.Minute
+)
+
+// dialChain connects to MantleRPC and refuses an RPC for a different
+// network than configured
+func dialChain(ctx context.Context, config *Config) (*ethclient.Client, error) {
+	client, err := ethclient.DialContext(ctx, config.MantleRPC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Mantle: %w", err)
+	}
+
+	rpcChainID, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to query chain ID from RPC: %w", err)
+	}
+	if rpcChainID.Cmp(big.NewInt(config.ChainID)) != 0 {
+		client.Close()
+		return nil, fmt.Errorf("chain ID mismatch: config expects %d but RPC %s reports %s",
+			config.ChainID, config.MantleRPC, rpcChainID)
+	}
+	return client, nil
+}
+
+// eth returns the current chain client, which reconnect may replace
+func (b *Bot) eth() *ethclient.Client {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.client
+}
+
+// txBackend is the part of the chain client that prices, numbers and sends
+// transactions
+type txBackend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// txClient returns the backend transactions are sent through: txNode when
+// set, otherwise the current chain client
+func (b *Bot) txClient() txBackend {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.txNode != nil {
+		return b.txNode
+	}
+	return b.client
+}
+
+// connectionLost reports whether err means the client's connection is gone
+// for good, as opposed to a single failed request
+func connectionLost(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, rpc.ErrClientQuit) ||
+		errors.Is(err, net.ErrClosed) ||
+		strings.Contains(err.Error(), "websocket: close")
+}
+
+// noteRPCError starts a background reconnect if err shows the chain client
+// connection was lost. Concurrent failures share a single reconnect.
+func (b *Bot) noteRPCError(err error) {
+	if !connectionLost(err) {
+		return
+	}
+
+	b.mutex.Lock()
+	if b.reconnecting || b.shuttingDown {
+		b.mutex.Unlock()
+		return
+	}
+	b.reconnecting = true
+	b.mutex.Unlock()
+	b.updateDegraded()
+
+	b.logger.WithError(err).Warn("RPC connection lost, reconnecting")
+	b.goBackground(b.reconnect)
+}
+
+// reconnect re-dials MantleRPC with backoff until it succeeds, then swaps
+// the new client in and closes the old one
+func (b *Bot) reconnect(ctx context.Context) {
+	defer func() {
+		b.mutex.Lock()
+		b.reconnecting = false
+		b.mutex.Unlock()
+		b.updateDegraded()
+	}()
+
+	backoff := reconnectMinBackoff
+	for attempt := 1; ; attempt++ {
+		dialCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		client, err := dialChain(dialCtx, b.cfg())
+		cancel()
+		if err == nil {
+			b.mutex.Lock()
+			old := b.client
+			b.client = client
+			b.mutex.Unlock()
+			old.Close()
+
+			b.metrics.AddCounter(metricRPCReconnects, 1)
+			b.logger.WithField("attempts", attempt).Info("RPC connection re-established")
+			return
+		}
+
+		b.logger.WithError(err).WithField("retry_in", backoff.String()).Warn("RPC reconnect failed")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package keeper
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// sensitiveKeys are JSON field names whose values never appear in debug logs
+var sensitiveKeys = []string{"apikey", "api_key", "token", "secret", "password", "authorization", "privatekey", "private_key"}
+
+// redactPayload returns a copy of a JSON document with sensitive field values
+// replaced. Non-JSON input is returned as a placeholder rather than verbatim.
+func redactPayload(raw []byte) string {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return "[unparseable payload]"
+	}
+
+	redacted, err := json.Marshal(redactValue(doc))
+	if err != nil {
+		return "[unparseable payload]"
+	}
+	return string(redacted)
+}
+
+// redactValue walks a decoded JSON value, masking sensitive object fields
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key, child := range val {
+			if isSensitiveKey(key) {
+				val[key] = "[REDACTED]"
+			} else {
+				val[key] = redactValue(child)
+			}
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = redactValue(child)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// isSensitiveKey reports whether a field name looks like a credential
+func isSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(lower, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL masks the password of a URL for logging
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return parsed.Redacted()
+}
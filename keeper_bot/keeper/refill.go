@@ -0,0 +1,109 @@
+package keeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fundingABIJSON is the interface expected of a gas top-up funding contract
+const fundingABIJSON = `[
+	{"type":"function","name":"requestTopUp","stateMutability":"nonpayable","inputs":[{"name":"keeper","type":"address"}],"outputs":[]}
+]`
+
+var fundingABI = mustParseABI(fundingABIJSON)
+
+// handleLowBalance alerts on a low keeper balance and requests a top-up when
+// a refill mechanism is configured
+func (b *Bot) handleLowBalance(ctx context.Context, balance *big.Int) {
+	config := b.cfg()
+	b.sendDeduped("low_balance:"+b.keeperAddress().Hex(), Alert{
+		Severity: AlertWarning,
+		Title:    "Low keeper account balance",
+		Fields: map[string]interface{}{
+			"address":     b.keeperAddress().Hex(),
+			"balance_wei": balance.String(),
+		},
+	})
+
+	if config.FundingURL == "" && config.FundingContractAddr == "" {
+		return
+	}
+
+	if err := b.refillBalance(ctx, balance); err != nil {
+		b.logger.WithError(err).Error("Balance top-up request failed")
+	}
+}
+
+// refillBalance asks the configured funding endpoint or contract for a gas
+// top-up, at most once per RefillCooldown
+func (b *Bot) refillBalance(ctx context.Context, balance *big.Int) error {
+	config := b.cfg()
+	b.mutex.Lock()
+	if time.Since(b.lastRefill) < config.RefillCooldown {
+		b.mutex.Unlock()
+		b.logger.Info("Balance top-up requested recently, waiting for cooldown")
+		return nil
+	}
+	b.lastRefill = time.Now()
+	b.mutex.Unlock()
+
+	var err error
+	if config.FundingURL != "" {
+		err = b.requestRefillHTTP(ctx, balance)
+	} else {
+		_, err = b.sendTx(ctx, "balance_refill", fundingABI,
+			common.HexToAddress(config.FundingContractAddr), "requestTopUp", b.keeperAddress())
+	}
+
+	result := "requested"
+	if err != nil {
+		result = "failed"
+	}
+	b.metrics.AddCounter(metricRefillAttempts, 1, "result", result)
+	if err != nil {
+		return err
+	}
+
+	b.logger.WithField("balance_wei", balance.String()).Info("Balance top-up requested")
+	return nil
+}
+
+// requestRefillHTTP posts a top-up request to the funding relayer
+func (b *Bot) requestRefillHTTP(ctx context.Context, balance *big.Int) error {
+	config := b.cfg()
+	body, err := json.Marshal(map[string]string{
+		"address":     b.keeperAddress().Hex(),
+		"balance_wei": balance.String(),
+		"chain_id":    b.chainID.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.MLRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.FundingURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("funding endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
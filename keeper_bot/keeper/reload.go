@@ -0,0 +1,175 @@
+package keeper
+
+import (
+	"fmt"
+	"slices"
+)
+
+// validateReloadable checks the hot-reloadable settings of config
+func validateReloadable(config *Config) error {
+	for name, value := range map[string]float64{
+		"critical risk threshold":    config.CriticalRisk,
+		"high risk threshold":        config.HighRisk,
+		"max LTV":                    config.MaxLTV,
+		"min NAV confidence":         config.MinNAVConfidence,
+		"min deleverage confidence":  config.MinDeleverageConfidence,
+		"forced NAV min confidence":  config.ForcedNAVMinConfidence,
+		"degraded confidence margin": config.DegradedConfidenceMargin,
+	} {
+		if value < 0 || value > 1 {
+			return fmt.Errorf("%s %g must be between 0 and 1", name, value)
+		}
+	}
+	if config.DegradedNAVChangeMultiplier < 0 {
+		return fmt.Errorf("degraded NAV change multiplier %g must not be negative", config.DegradedNAVChangeMultiplier)
+	}
+	if config.HighRisk > config.CriticalRisk {
+		return fmt.Errorf("high risk threshold %g must not exceed critical risk threshold %g", config.HighRisk, config.CriticalRisk)
+	}
+	for name, minutes := range map[string]int{
+		"leverage monitor interval": config.LeverageMonitorInterval,
+		"NAV update interval":       config.NAVUpdateInterval,
+		"KYC monitor interval":      config.KYCMonitorInterval,
+		"health check interval":     config.HealthCheckInterval,
+		"role check interval":       config.RoleCheckInterval,
+	} {
+		if minutes < 1 {
+			return fmt.Errorf("%s must be at least 1 minute, got %d", name, minutes)
+		}
+	}
+	if config.MaxConsecutiveFailures < 0 {
+		return fmt.Errorf("max consecutive failures %d must not be negative", config.MaxConsecutiveFailures)
+	}
+	if err := validateFailureAction(config.FailureAction); err != nil {
+		return err
+	}
+	if err := validateRecommendationPolicy(config.RecommendationPolicy); err != nil {
+		return err
+	}
+	if err := validateUnknownRiskLevelAs(config.UnknownRiskLevelAs); err != nil {
+		return err
+	}
+	if err := validateNAVAge(config); err != nil {
+		return err
+	}
+	return validateAlertRouting(config.AlertRouting)
+}
+
+// restartRequired lists the settings that differ between two configs but
+// only take effect on restart
+func restartRequired(current, next *Config) []string {
+	var changed []string
+	for name, differs := range map[string]bool{
+		"MANTLE_RPC":              current.MantleRPC != next.MantleRPC,
+		"CHAIN_ID":                current.ChainID != next.ChainID,
+		"KEEPER_PRIVATE_KEY":      current.PrivateKey != next.PrivateKey,
+		"KEYSTORE_PATH":           current.KeystorePath != next.KeystorePath,
+		"KEYSTORE_PASSWORD_FILE":  current.KeystorePasswordFile != next.KeystorePasswordFile,
+		"LEVERAGED_STRATEGY_ADDR": !slices.Equal(current.LeveragedStrategyAddrs, next.LeveragedStrategyAddrs),
+		"INVOICE_TOKEN_ADDR":      !slices.Equal(current.InvoiceTokenAddrs, next.InvoiceTokenAddrs),
+		"KYC_VERIFIER_ADDR":       current.KYCVerifierAddr != next.KYCVerifierAddr,
+		"CHAINS_PATH":             current.ChainsPath != next.ChainsPath,
+	} {
+		if differs {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// Reload applies the hot-reloadable settings of next: risk thresholds,
+// confidence floors, degraded-mode thresholds, alert routing, monitor
+// schedules and the consecutive failure cap. next is validated
+// first and nothing is applied if it is invalid. Changed settings that need
+// a restart (keys, RPC, chain ID, contract addresses) are logged, left
+// unchanged and returned.
+func (b *Bot) Reload(next *Config) ([]string, error) {
+	if err := validateReloadable(next); err != nil {
+		return nil, fmt.Errorf("invalid configuration, nothing reloaded: %w", err)
+	}
+
+	// Readers load the config without the mutex, so the reloaded settings
+	// are published as a new snapshot rather than written in place; the
+	// mutex only serializes concurrent reloads
+	b.mutex.Lock()
+	current := b.cfg()
+	restart := restartRequired(current, next)
+	reloaded := *current
+	reloaded.CriticalRisk = next.CriticalRisk
+	reloaded.HighRisk = next.HighRisk
+	reloaded.MaxLTV = next.MaxLTV
+	reloaded.MinHealthFactor = next.MinHealthFactor
+	reloaded.MinLiquidity = next.MinLiquidity
+	reloaded.ThresholdOverride = next.ThresholdOverride
+	reloaded.RecommendationPolicy = next.RecommendationPolicy
+	reloaded.UnknownRiskLevelAs = next.UnknownRiskLevelAs
+	reloaded.InvoiceDefaultThreshold = next.InvoiceDefaultThreshold
+	reloaded.MinNAVConfidence = next.MinNAVConfidence
+	reloaded.MinDeleverageConfidence = next.MinDeleverageConfidence
+	reloaded.ForcedNAVMinConfidence = next.ForcedNAVMinConfidence
+	reloaded.MaxNAVAge = next.MaxNAVAge
+	reloaded.DegradedConfidenceMargin = next.DegradedConfidenceMargin
+	reloaded.DegradedNAVChangeMultiplier = next.DegradedNAVChangeMultiplier
+	reloaded.DegradedBlockRoutineTx = next.DegradedBlockRoutineTx
+	reloaded.AlertRouting = next.AlertRouting
+	reloaded.LeverageMonitorInterval = next.LeverageMonitorInterval
+	reloaded.NAVUpdateInterval = next.NAVUpdateInterval
+	reloaded.KYCMonitorInterval = next.KYCMonitorInterval
+	reloaded.HealthCheckInterval = next.HealthCheckInterval
+	reloaded.RoleCheckInterval = next.RoleCheckInterval
+	reloaded.MaxConsecutiveFailures = next.MaxConsecutiveFailures
+	reloaded.FailureAction = next.FailureAction
+	b.config.Store(&reloaded)
+	b.mutex.Unlock()
+
+	b.alerter.SetRouting(next.AlertRouting)
+	var rescheduled []string
+	for name, minutes := range map[string]int{
+		MonitorLeverage: next.LeverageMonitorInterval,
+		MonitorNAV:      next.NAVUpdateInterval,
+		MonitorKYC:      next.KYCMonitorInterval,
+		MonitorHealth:   next.HealthCheckInterval,
+		"role_check":    next.RoleCheckInterval,
+	} {
+		if b.reschedule(name, minutes) {
+			rescheduled = append(rescheduled, name)
+		}
+	}
+	slices.Sort(rescheduled)
+
+	if len(restart) > 0 {
+		b.logger.WithField("settings", restart).Warn("Changed settings need a restart, left unchanged")
+	}
+	b.logger.WithField("rescheduled", rescheduled).Info("Configuration reloaded")
+	return restart, nil
+}
+
+// Reload applies the hot-reloadable settings of config to every chain's bot,
+// validating it first. Chains added to or removed from ChainsPath need a
+// restart.
+func (f *Fleet) Reload(config *Config) error {
+	if err := validateReloadable(config); err != nil {
+		return fmt.Errorf("invalid configuration, nothing reloaded: %w", err)
+	}
+	chains, err := loadChains(config.ChainsPath)
+	if err != nil {
+		return fmt.Errorf("invalid configuration, nothing reloaded: %w", err)
+	}
+
+	for _, bot := range f.bots {
+		next := config
+		if bot.Chain() != "" {
+			i := slices.IndexFunc(chains, func(chain ChainConfig) bool { return chain.Name == bot.Chain() })
+			if i < 0 {
+				bot.logger.Warn("Chain no longer configured, restart required to remove it")
+				continue
+			}
+			next = config.forChain(chains[i])
+		}
+		if _, err := bot.Reload(next); err != nil {
+			return f.label(bot, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package keeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Report is the machine-readable summary of the monitor runs made by this
+// process, written by --report-out after --once
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Chain       string    `json:"chain,omitempty"`
+	Address     string    `json:"address"`
+	Profile     string    `json:"profile"`
+	// DryRun is true when no transaction was broadcast, so the actions and
+	// gas estimates are what would have been done
+	DryRun bool         `json:"dry_run"`
+	Runs   []MonitorRun `json:"runs"`
+}
+
+// Report summarizes every monitor run recorded so far
+func (b *Bot) Report() Report {
+	config := b.cfg()
+	return Report{
+		GeneratedAt: time.Now().UTC(),
+		Chain:       b.Chain(),
+		Address:     b.keeperAddress().Hex(),
+		Profile:     config.Profile,
+		DryRun:      config.DryRun || b.InStartupGrace(),
+		Runs:        b.History(),
+	}
+}
+
+// WriteReport writes a report to path as indented JSON
+func WriteReport(path string, report Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// estimateUnsentGas estimates the gas of a transaction that was signed but
+// not broadcast (dry run or startup grace) so reports show its cost; it
+// returns 0 for broadcast transactions or when estimation fails
+func (b *Bot) estimateUnsentGas(ctx context.Context, tx *types.Transaction) uint64 {
+	if !b.cfg().DryRun && !b.InStartupGrace() {
+		return 0
+	}
+	msg := ethereum.CallMsg{From: b.keeperAddress(), To: tx.To(), Data: tx.Data()}
+	gas, err := b.eth().EstimateGas(ctx, msg)
+	if err != nil {
+		b.logger.WithError(err).WithField("tx", tx.Hash().Hex()).Debug("Gas estimation for unsent transaction failed")
+		return 0
+	}
+	return gas
+}
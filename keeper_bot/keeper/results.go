@@ -0,0 +1,123 @@
+package keeper
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// historySize is the number of monitor runs kept in the history buffer
+const historySize = 100
+
+// LeverageResult is the decision made for one strategy in a leverage cycle
+type LeverageResult struct {
+	Strategy     string            `json:"strategy"`
+	Account      string            `json:"account,omitempty"`
+	Position     *StrategyPosition `json:"position,omitempty"`
+	HealthFactor float64           `json:"health_factor"`
+	LTV          float64           `json:"ltv"`
+	RiskLevel    string            `json:"risk_level,omitempty"`
+	Score        float64           `json:"score"`
+	Confidence   *float64          `json:"confidence,omitempty"`
+	Fallback     bool              `json:"fallback,omitempty"`
+	// LocalModel marks assessments made by the local model, see LocalModelPath
+	LocalModel   bool   `json:"local_model,omitempty"`
+	ActionTaken  string `json:"action_taken,omitempty"`
+	TxHash       string `json:"tx_hash,omitempty"`
+	EstimatedGas uint64 `json:"estimated_gas,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// NAVResult is the decision made for one invoice token in a NAV cycle
+type NAVResult struct {
+	Token string      `json:"token"`
+	Pool  *NAVRequest `json:"pool,omitempty"`
+	// NAVs are in human units rounded to NAVPrecision, and exactly in wei
+	PredictedNAV    float64 `json:"predicted_nav"`
+	PredictedNAVWei string  `json:"predicted_nav_wei,omitempty"`
+	Confidence      float64 `json:"confidence"`
+	PublishedNAV    float64 `json:"published_nav,omitempty"`
+	PublishedNAVWei string  `json:"published_nav_wei,omitempty"`
+	Outcome         string  `json:"outcome"`
+	// Forced marks updates pushed because on-chain NAV exceeded MaxNAVAge
+	Forced       bool   `json:"forced,omitempty"`
+	TxHash       string `json:"tx_hash,omitempty"`
+	EstimatedGas uint64 `json:"estimated_gas,omitempty"`
+	Signature    string `json:"signature,omitempty"`
+	// InputDigest is the EIP-712 digest of the NAV input attestation
+	InputDigest string `json:"input_digest,omitempty"`
+	// DefaultsFlagged counts invoices predicted to default past
+	// InvoiceDefaultThreshold, see checkInvoiceDefaults
+	DefaultsFlagged int    `json:"defaults_flagged,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// KYCResult summarizes a KYC compliance cycle
+type KYCResult struct {
+	Assessed int `json:"assessed"`
+	Failed   int `json:"failed"`
+	HighRisk int `json:"high_risk"`
+	// RuleViolations counts investments breaching a jurisdiction rule
+	RuleViolations int `json:"rule_violations"`
+}
+
+// MonitorRun is one monitor execution as kept in the history buffer
+type MonitorRun struct {
+	Monitor  string           `json:"monitor"`
+	At       time.Time        `json:"at"`
+	Leverage []LeverageResult `json:"leverage,omitempty"`
+	NAV      []NAVResult      `json:"nav,omitempty"`
+	KYC      *KYCResult       `json:"kyc,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// recordRun logs a completed monitor run and appends it to the history buffer
+func (b *Bot) recordRun(run MonitorRun, err error) {
+	run.At = time.Now()
+	entry := b.logger.WithFields(logrus.Fields{
+		"monitor":  run.Monitor,
+		"leverage": run.Leverage,
+		"nav":      run.NAV,
+		"kyc":      run.KYC,
+	})
+	if err != nil {
+		run.Error = err.Error()
+		entry.WithError(err).Error("Monitor run failed")
+	} else {
+		entry.Info("Monitor run completed")
+	}
+
+	// Skipped runs carry no results and must not count as proof of life
+	if err == nil && (run.Leverage != nil || run.NAV != nil || run.KYC != nil) {
+		b.noteSuccessfulCycle()
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.history = append(b.history, run)
+	if len(b.history) > historySize {
+		b.history = b.history[len(b.history)-historySize:]
+	}
+}
+
+// latestResults returns the results of the most recent leverage and NAV runs
+// in the history buffer; callers must hold b.mutex
+func (b *Bot) latestResults() (leverage []LeverageResult, nav []NAVResult) {
+	for i := len(b.history) - 1; i >= 0 && (leverage == nil || nav == nil); i-- {
+		run := b.history[i]
+		if leverage == nil && run.Leverage != nil {
+			leverage = run.Leverage
+		}
+		if nav == nil && run.NAV != nil {
+			nav = run.NAV
+		}
+	}
+	return leverage, nav
+}
+
+// History returns recent monitor runs, oldest first
+func (b *Bot) History() []MonitorRun {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]MonitorRun(nil), b.history...)
+}
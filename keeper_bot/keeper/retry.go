@@ -0,0 +1,176 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+// httpStatusError is a non-200 response from an HTTP dependency
+type httpStatusError struct {
+	StatusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("API returned status %d", e.StatusCode)
+}
+
+// retryableStatus reports whether a status is worth retrying: rate limiting and
+// server errors, but not client errors
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// isTransient reports whether err is likely to succeed on retry: timeouts,
+// dropped connections and 429/5xx responses. Cancellation of the caller's
+// context is never transient.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return retryableStatus(statusErr.StatusCode)
+	}
+	var rpcHTTPErr rpc.HTTPError
+	if errors.As(err, &rpcHTTPErr) {
+		return retryableStatus(rpcHTTPErr.StatusCode)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || connectionLost(err) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// ErrRetryBudgetExhausted is returned with the last error of a call that
+// was not retried because its cycle's RetryBudget was spent
+var ErrRetryBudgetExhausted = errors.New("cycle retry budget exhausted")
+
+// retryBudget is the retry time left to one scheduled run, shared by all
+// its calls
+type retryBudget struct {
+	job string
+
+	mu        sync.Mutex
+	remaining time.Duration
+	exhausted bool
+}
+
+type retryBudgetKey struct{}
+
+// withRetryBudget gives the calls made with ctx a shared retry allowance
+func withRetryBudget(ctx context.Context, job string, allowance time.Duration) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, &retryBudget{job: job, remaining: allowance})
+}
+
+// retryBudgetFrom returns ctx's retry budget, or nil when retries are
+// unbudgeted
+func retryBudgetFrom(ctx context.Context) *retryBudget {
+	budget, _ := ctx.Value(retryBudgetKey{}).(*retryBudget)
+	return budget
+}
+
+// reserve takes d from the budget if that much is left. The first refusal
+// reports first so exhaustion is logged once per run.
+func (r *retryBudget) reserve(d time.Duration) (ok, first bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.exhausted || d > r.remaining {
+		first = !r.exhausted
+		r.exhausted = true
+		return false, first
+	}
+	r.remaining -= d
+	return true, false
+}
+
+// charge takes the time a retried attempt ran from the budget; an attempt
+// already under way is allowed to overrun it
+func (r *retryBudget) charge(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remaining = max(r.remaining-d, 0)
+}
+
+// withRetry runs op, retrying transient failures up to RetryAttempts extra
+// times with jittered exponential backoff between RetryBaseDelay and
+// RetryMaxDelay. It stops early when ctx is done or when the cycle's
+// retry budget, if ctx has one, cannot cover the next backoff.
+func (b *Bot) withRetry(ctx context.Context, name string, op func(ctx context.Context) error) error {
+	config := b.cfg()
+	budget := retryBudgetFrom(ctx)
+	delay := config.RetryBaseDelay
+	for attempt := 0; ; attempt++ {
+		started := time.Now()
+		err := op(ctx)
+		if attempt > 0 && budget != nil {
+			budget.charge(time.Since(started))
+		}
+		b.noteRPCError(err)
+		if err == nil || attempt >= config.RetryAttempts || !isTransient(err) || ctx.Err() != nil {
+			return err
+		}
+
+		// Jitter of ±50% spreads retries from keepers sharing one provider
+		wait := delay
+		if wait > 0 {
+			wait = time.Duration(rand.Int64N(int64(wait))) + wait/2
+		}
+		if budget != nil {
+			if ok, first := budget.reserve(wait); !ok {
+				if first {
+					b.logger.WithFields(logrus.Fields{
+						"job":       budget.job,
+						"operation": name,
+					}).WithError(err).Warn("Cycle retry budget exhausted, failing fast")
+					b.metrics.AddCounter(metricRetryBudgetExhausted, 1, "job", budget.job)
+				}
+				return fmt.Errorf("%w: %w", ErrRetryBudgetExhausted, err)
+			}
+		}
+		b.logger.WithFields(logrus.Fields{
+			"operation": name,
+			"attempt":   attempt + 1,
+			"retry_in":  wait.String(),
+		}).WithError(err).Warn("Transient error, retrying")
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+
+		delay *= 2
+		if delay > config.RetryMaxDelay {
+			delay = config.RetryMaxDelay
+		}
+	}
+}
+
+// retryValue is withRetry for operations that return a value
+func retryValue[T any](ctx context.Context, b *Bot, name string, op func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	err := b.withRetry(ctx, name, func(ctx context.Context) error {
+		var err error
+		value, err = op(ctx)
+		return err
+	})
+	return value, err
+}
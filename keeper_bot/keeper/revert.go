@@ -0,0 +1,146 @@
+package keeper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// commonErrorsABIJSON declares custom errors of widely used OpenZeppelin
+// contracts, decoded even when a contract's ABI does not list them
+const commonErrorsABIJSON = `[
+	{"type":"error","name":"AccessControlUnauthorizedAccount","inputs":[{"name":"account","type":"address"},{"name":"neededRole","type":"bytes32"}]},
+	{"type":"error","name":"OwnableUnauthorizedAccount","inputs":[{"name":"account","type":"address"}]},
+	{"type":"error","name":"EnforcedPause","inputs":[]},
+	{"type":"error","name":"ReentrancyGuardReentrantCall","inputs":[]},
+	{"type":"error","name":"ERC20InsufficientBalance","inputs":[{"name":"sender","type":"address"},{"name":"balance","type":"uint256"},{"name":"needed","type":"uint256"}]},
+	{"type":"error","name":"ERC20InsufficientAllowance","inputs":[{"name":"spender","type":"address"},{"name":"allowance","type":"uint256"},{"name":"needed","type":"uint256"}]},
+	{"type":"error","name":"SafeERC20FailedOperation","inputs":[{"name":"token","type":"address"}]}
+]`
+
+var commonErrorsABI = mustParseABI(commonErrorsABIJSON)
+
+// revertData returns the revert payload carried by a call error, if the
+// node returned one
+func revertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	return data, decodeErr == nil && len(data) >= 4
+}
+
+// decodeRevert renders a revert payload: an Error(string) or Panic(uint256)
+// as its message, and a custom error declared by contractABI or
+// commonErrorsABI as its name and arguments
+func decodeRevert(contractABI abi.ABI, data []byte) (string, bool) {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason, true
+	}
+	if len(data) < 4 {
+		return "", false
+	}
+	selector := data[:4]
+
+	for _, declared := range []map[string]abi.Error{contractABI.Errors, commonErrorsABI.Errors} {
+		for _, customErr := range declared {
+			if !bytes.Equal(customErr.ID[:4], selector) {
+				continue
+			}
+			values, err := customErr.Inputs.Unpack(data[4:])
+			if err != nil {
+				return customErr.Name + "(<undecodable>)", true
+			}
+			args := make([]string, len(values))
+			for i, value := range values {
+				args[i] = formatErrorArg(value)
+			}
+			return customErr.Name + "(" + strings.Join(args, ", ") + ")", true
+		}
+	}
+	return "", false
+}
+
+// formatErrorArg renders a custom error argument, fixed-size byte arrays
+// such as role hashes as hex
+func formatErrorArg(value interface{}) string {
+	switch v := value.(type) {
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	case []byte:
+		return hexutil.Encode(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// revertReason extracts a human-readable reason from a call error
+func revertReason(err error) string {
+	return revertReasonFor(abi.ABI{}, err)
+}
+
+// revertReasonFor extracts a human-readable reason from a call error,
+// decoding custom errors against contractABI. Undecodable payloads are
+// rendered as hex so the selector can still be looked up.
+func revertReasonFor(contractABI abi.ABI, err error) string {
+	if data, ok := revertData(err); ok {
+		if reason, ok := decodeRevert(contractABI, data); ok {
+			return reason
+		}
+		return "unknown error " + hexutil.Encode(data)
+	}
+	return strings.TrimPrefix(err.Error(), "execution reverted: ")
+}
+
+// explainRevert returns the decoded revert reason of a failed send and its
+// error with the reason added; other errors are returned unchanged
+func explainRevert(contractABI abi.ABI, err error) (string, error) {
+	if err == nil || !isRevert(err) {
+		return "", err
+	}
+	reason := revertReasonFor(contractABI, err)
+	return reason, fmt.Errorf("%w (revert reason: %s)", err, reason)
+}
+
+// replayRevert recovers why a mined transaction reverted by re-executing it
+// as an eth_call against the state before its block: the block's own state
+// already has whatever the transaction depended on applied. Transactions
+// earlier in the same block may still differ, so an empty reason is
+// returned when the replay does not revert.
+func (b *Bot) replayRevert(ctx context.Context, contractABI abi.ABI, tx *types.Transaction, receipt *types.Receipt) string {
+	from, err := types.Sender(types.LatestSignerForChainID(b.chainID), tx)
+	if err != nil {
+		return ""
+	}
+	msg := ethereum.CallMsg{
+		From:     from,
+		To:       tx.To(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}
+	var parent *big.Int
+	if receipt.BlockNumber != nil && receipt.BlockNumber.Sign() > 0 {
+		parent = new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	}
+	_, err = b.eth().CallContract(ctx, msg, parent)
+	if err == nil || !isRevert(err) {
+		return ""
+	}
+	return revertReasonFor(contractABI, err)
+}
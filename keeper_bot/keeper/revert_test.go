@@ -0,0 +1,122 @@
+package keeper
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// revertingContractABI is a mock contract declaring its own custom error
+var revertingContractABI = mustParseABI(`[
+	{"type":"error","name":"SlippageTooHigh","inputs":[{"name":"minOut","type":"uint256"},{"name":"actual","type":"uint256"}]}
+]`)
+
+// rpcRevertError is a node's execution-reverted error carrying revert data,
+// as returned for a failed eth_call or eth_estimateGas
+type rpcRevertError struct {
+	data []byte
+}
+
+func (e rpcRevertError) Error() string          { return "execution reverted" }
+func (e rpcRevertError) ErrorCode() int         { return 3 }
+func (e rpcRevertError) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+// revertPayload ABI-encodes a revert with the given signature and arguments
+func revertPayload(t *testing.T, signature string, args abi.Arguments, values ...interface{}) []byte {
+	t.Helper()
+	encoded, err := args.Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(crypto.Keccak256([]byte(signature))[:4], encoded...)
+}
+
+// abiArgs builds unnamed arguments of the given types
+func abiArgs(t *testing.T, types ...string) abi.Arguments {
+	t.Helper()
+	args := make(abi.Arguments, len(types))
+	for i, name := range types {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args[i] = abi.Argument{Type: typ}
+	}
+	return args
+}
+
+func TestDecodeRevert(t *testing.T) {
+	account := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	role := crypto.Keccak256Hash([]byte("KEEPER_ROLE"))
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "Error(string)",
+			data: revertPayload(t, "Error(string)", abiArgs(t, "string"), "health factor too low"),
+			want: "health factor too low",
+		},
+		{
+			name: "Panic(uint256)",
+			data: revertPayload(t, "Panic(uint256)", abiArgs(t, "uint256"), big.NewInt(0x11)),
+			want: "arithmetic underflow or overflow",
+		},
+		{
+			name: "common custom error",
+			data: revertPayload(t, "AccessControlUnauthorizedAccount(address,bytes32)", abiArgs(t, "address", "bytes32"), account, [32]byte(role)),
+			want: "AccessControlUnauthorizedAccount(" + account.Hex() + ", " + role.Hex() + ")",
+		},
+		{
+			name: "contract custom error",
+			data: revertPayload(t, "SlippageTooHigh(uint256,uint256)", abiArgs(t, "uint256", "uint256"), big.NewInt(990), big.NewInt(900)),
+			want: "SlippageTooHigh(990, 900)",
+		},
+		{
+			name: "argument-less custom error",
+			data: revertPayload(t, "EnforcedPause()", nil),
+			want: "EnforcedPause()",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := decodeRevert(revertingContractABI, tt.data)
+			if !ok || got != tt.want {
+				t.Fatalf("decodeRevert = %q, %t, want %q", got, ok, tt.want)
+			}
+
+			reason, err := explainRevert(revertingContractABI, rpcRevertError{data: tt.data})
+			if reason != tt.want {
+				t.Fatalf("explainRevert reason = %q, want %q", reason, tt.want)
+			}
+			if !strings.Contains(err.Error(), "revert reason: "+tt.want) {
+				t.Fatalf("explainRevert err = %q, want the reason appended", err)
+			}
+		})
+	}
+}
+
+func TestExplainRevertUnknownSelector(t *testing.T) {
+	data := []byte{0xde, 0xad, 0xbe, 0xef, 0x01}
+	if _, ok := decodeRevert(revertingContractABI, data); ok {
+		t.Fatal("decodeRevert decoded an undeclared selector")
+	}
+	reason, _ := explainRevert(revertingContractABI, rpcRevertError{data: data})
+	if want := "unknown error 0xdeadbeef01"; reason != want {
+		t.Fatalf("reason = %q, want %q", reason, want)
+	}
+
+	// Errors that are not reverts pass through unchanged
+	sendErr := errors.New("connection refused")
+	if reason, err := explainRevert(revertingContractABI, sendErr); reason != "" || err != sendErr {
+		t.Fatalf("explainRevert(%v) = %q, %v, want it unchanged", sendErr, reason, err)
+	}
+}
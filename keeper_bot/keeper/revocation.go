@@ -0,0 +1,176 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrRoleRevoked is returned instead of sending a transaction to a contract
+// on which the keeper's role has been revoked
+var ErrRoleRevoked = errors.New("keeper role revoked on contract")
+
+// accessControlUnauthorizedSelector is the selector of OpenZeppelin 5's
+// AccessControlUnauthorizedAccount(address,bytes32) custom error
+const accessControlUnauthorizedSelector = "0xe2517d3f"
+
+// isUnauthorizedRevert reports whether a revert is an access-control
+// rejection of the caller
+func isUnauthorizedRevert(err error) bool {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok && strings.HasPrefix(data, accessControlUnauthorizedSelector) {
+			return true
+		}
+	}
+	reason := strings.ToLower(revertReason(err))
+	return strings.Contains(reason, "accesscontrol") || strings.Contains(reason, "missing role") || strings.Contains(reason, "unauthorized")
+}
+
+// roleFor returns the role the keeper needs on contract
+func (b *Bot) roleFor(contract common.Address) (roleRequirement, bool) {
+	for _, req := range b.requiredRoles() {
+		if req.contract == contract {
+			return req, true
+		}
+	}
+	return roleRequirement{}, false
+}
+
+// checkNotRevoked refuses transactions to a contract whose role was revoked
+func (b *Bot) checkNotRevoked(contract common.Address) error {
+	b.mutex.Lock()
+	_, revoked := b.revokedRoles[contract]
+	b.mutex.Unlock()
+	if revoked {
+		b.metrics.AddCounter(metricTxSkipped, 1, "reason", "role_revoked")
+		return ErrRoleRevoked
+	}
+	return nil
+}
+
+// verifyRoleAfterRevert re-checks the keeper's role on a contract that
+// rejected one of its calls, marking the role revoked if it is gone
+func (b *Bot) verifyRoleAfterRevert(ctx context.Context, contract common.Address) {
+	req, ok := b.roleFor(contract)
+	if !ok {
+		return
+	}
+	b.noteRoleCheck(req, b.checkRole(ctx, req))
+}
+
+// roleCheck is a conclusive role query: the role held (err nil) or missing
+type roleCheck struct {
+	checkedAt time.Time
+	err       error
+}
+
+// noteRoleCheck updates the revoked-role state and cache from a role check
+// result; query failures leave both unchanged
+func (b *Bot) noteRoleCheck(req roleRequirement, err error) {
+	if err != nil && !errors.Is(err, ErrRoleMissing) {
+		return
+	}
+	b.mutex.Lock()
+	b.roleChecks[req.contract] = roleCheck{checkedAt: time.Now(), err: err}
+	b.mutex.Unlock()
+
+	if err == nil {
+		b.markRoleRestored(req)
+	} else {
+		b.markRoleRevoked(req)
+	}
+}
+
+// markRoleRevoked stops actions against a contract and raises a critical
+// alert the first time its role is found missing
+func (b *Bot) markRoleRevoked(req roleRequirement) {
+	b.mutex.Lock()
+	_, already := b.revokedRoles[req.contract]
+	b.revokedRoles[req.contract] = req.roleName
+	b.mutex.Unlock()
+	if already {
+		return
+	}
+
+	b.sendDeduped("role_revoked:"+req.contract.Hex(), Alert{
+		Severity: AlertCritical,
+		Title:    "Keeper role revoked, actions on contract suspended",
+		Fields: map[string]interface{}{
+			"keeper":   b.keeperAddress().Hex(),
+			"contract": req.contract.Hex(),
+			"role":     req.roleName,
+		},
+	})
+}
+
+// markRoleRestored resumes actions against a contract whose role is back
+func (b *Bot) markRoleRestored(req roleRequirement) {
+	b.mutex.Lock()
+	_, revoked := b.revokedRoles[req.contract]
+	delete(b.revokedRoles, req.contract)
+	b.mutex.Unlock()
+	if !revoked {
+		return
+	}
+	b.clearDeduped("role_revoked:" + req.contract.Hex())
+
+	b.logger.WithFields(logrus.Fields{
+		"contract": req.contract.Hex(),
+		"role":     req.roleName,
+	}).Info("Keeper role restored, resuming actions on contract")
+}
+
+// refreshRoles returns the result of every required role, in requiredRoles
+// order. Results younger than RoleCheckInterval are reused unless force is
+// set; the rest are re-queried in one batch, so a revocation is caught and
+// a role granted again resumes actions on its contract.
+func (b *Bot) refreshRoles(ctx context.Context, force bool) []error {
+	required := b.requiredRoles()
+	results := make([]error, len(required))
+	maxAge := time.Duration(b.cfg().RoleCheckInterval) * time.Minute
+
+	var stale []int
+	b.mutex.Lock()
+	for i, req := range required {
+		cached, ok := b.roleChecks[req.contract]
+		if !force && ok && time.Since(cached.checkedAt) < maxAge {
+			results[i] = cached.err
+			continue
+		}
+		stale = append(stale, i)
+	}
+	b.mutex.Unlock()
+	if len(stale) == 0 {
+		return results
+	}
+
+	account := b.keeperAddress()
+	calls := make([]contractCall, len(stale))
+	for j, i := range stale {
+		calls[j] = roleCall(required[i], account)
+	}
+	for j, call := range b.batchCall(ctx, nil, calls) {
+		req := required[stale[j]]
+		err := b.roleResult(req, account, call.out, call.err)
+		b.noteRoleCheck(req, err)
+		results[stale[j]] = err
+	}
+	return results
+}
+
+// revokedContracts lists contracts with a revoked role; caller holds mutex
+func (b *Bot) revokedContracts() []string {
+	contracts := make([]string, 0, len(b.revokedRoles))
+	for contract := range b.revokedRoles {
+		contracts = append(contracts, contract.Hex())
+	}
+	sort.Strings(contracts)
+	return contracts
+}
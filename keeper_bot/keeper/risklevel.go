@@ -0,0 +1,96 @@
+package keeper
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Risk levels the ML engine may report for a leverage assessment
+const (
+	RiskLevelLow      = "LOW"
+	RiskLevelMedium   = "MEDIUM"
+	RiskLevelHigh     = "HIGH"
+	RiskLevelCritical = "CRITICAL"
+)
+
+var knownRiskLevels = []string{RiskLevelLow, RiskLevelMedium, RiskLevelHigh, RiskLevelCritical}
+
+// validateUnknownRiskLevelAs checks UnknownRiskLevelAs names a known risk level
+func validateUnknownRiskLevelAs(level string) error {
+	if !slices.Contains(knownRiskLevels, strings.ToUpper(level)) {
+		return fmt.Errorf("unknown risk level as %q must be one of %s", level, strings.Join(knownRiskLevels, ", "))
+	}
+	return nil
+}
+
+// normalizeRiskLevel upper-cases an ML assessment's risk level and replaces
+// one outside knownRiskLevels with UnknownRiskLevelAs, returning the
+// replaced value or "" when the level was recognized
+func (b *Bot) normalizeRiskLevel(assessment *LeverageHealthResponse) string {
+	level := strings.ToUpper(strings.TrimSpace(assessment.RiskLevel))
+	if slices.Contains(knownRiskLevels, level) {
+		assessment.RiskLevel = level
+		return ""
+	}
+	unknown := assessment.RiskLevel
+	assessment.RiskLevel = strings.ToUpper(b.cfg().UnknownRiskLevelAs)
+	return unknown
+}
+
+// riskLevelRecommendation returns the protective recommendation a risk level
+// calls for on its own, if any
+func riskLevelRecommendation(level string) (string, bool) {
+	switch level {
+	case RiskLevelCritical:
+		return RecEmergencyDeleverage, true
+	case RiskLevelHigh:
+		return RecReduceLeverage, true
+	default:
+		return "", false
+	}
+}
+
+// overrideUnknownRiskLevel chooses the action implied by UnknownRiskLevelAs
+// for an assessment with an unrecognized risk level that recommends none, so
+// a changed ML contract errs on the side of reducing risk. Unborrowed
+// positions have nothing to reduce and are left alone.
+func (b *Bot) overrideUnknownRiskLevel(position *StrategyPosition, decision *leverageDecision) {
+	if position.TotalBorrowed == 0 {
+		return
+	}
+	recommendation, ok := riskLevelRecommendation(decision.assessment.RiskLevel)
+	if !ok {
+		return
+	}
+	chosen, action, _, _, ok := b.selectRiskAction([]string{recommendation})
+	if !ok {
+		return
+	}
+	decision.chosen, decision.action, decision.ok = chosen, action, true
+	decision.overrideReasons = []string{"unknown_risk_level"}
+}
+
+// maxReportedRiskLevel bounds how much of an unrecognized risk level is
+// echoed into an alert
+const maxReportedRiskLevel = 64
+
+// alertUnknownRiskLevel alerts when the ML engine reports an unrecognized
+// risk level, which likely means its response contract changed. The levels
+// come from the engine, so they share one dedup key rather than keeping
+// state per level: the first alerts and the rest are suppressed for
+// AlertDedupRetention.
+func (b *Bot) alertUnknownRiskLevel(strategy, level string) {
+	if len(level) > maxReportedRiskLevel {
+		level = level[:maxReportedRiskLevel] + "..."
+	}
+	b.sendDeduped("unknown_risk_level", Alert{
+		Severity: AlertWarning,
+		Title:    "ML engine returned an unknown risk level, ML contract may have changed",
+		Fields: map[string]interface{}{
+			"strategy":   strategy,
+			"risk_level": level,
+			"treated_as": strings.ToUpper(b.cfg().UnknownRiskLevelAs),
+		},
+	})
+}
@@ -0,0 +1,77 @@
+package keeper
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnknownRiskLevel(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	borrowed := &StrategyPosition{TotalBorrowed: 100, HealthFactor: 2, LTV: 0.4}
+	unborrowed := &StrategyPosition{TotalCollateral: 100, HealthFactor: 2}
+	response := func(level string) []byte {
+		return fmt.Appendf(nil, `{"composite_risk_score":0.1,"risk_level":%q,"action_required":false,"recommendations":[],"confidence":0.9,"timestamp":%d}`,
+			level, now.Unix())
+	}
+
+	tests := []struct {
+		name        string
+		level       string
+		treatAs     string
+		position    *StrategyPosition
+		wantUnknown string
+		wantLevel   string
+		wantAction  string
+	}{
+		{"known level is normalized", " high ", "CRITICAL", borrowed, "", RiskLevelHigh, ""},
+		{"unknown treated as high", "SEVERE", "high", borrowed, "SEVERE", RiskLevelHigh, RecReduceLeverage},
+		{"unknown treated as critical", "SEVERE", "CRITICAL", borrowed, "SEVERE", RiskLevelCritical, RecEmergencyDeleverage},
+		{"unknown treated as medium", "SEVERE", "MEDIUM", borrowed, "SEVERE", RiskLevelMedium, ""},
+		{"unborrowed position left alone", "SEVERE", "CRITICAL", unborrowed, "SEVERE", RiskLevelCritical, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig(t)
+			config.UnknownRiskLevelAs = tt.treatAs
+			bot, _ := newTestBot(t, config, nil)
+
+			decision, err := bot.decideRiskAction(now, tt.position, response(tt.level))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decision.unknownLevel != tt.wantUnknown || decision.assessment.RiskLevel != tt.wantLevel {
+				t.Fatalf("unknown level %q treated as %q, want %q treated as %q",
+					decision.unknownLevel, decision.assessment.RiskLevel, tt.wantUnknown, tt.wantLevel)
+			}
+			if decision.chosen != tt.wantAction || decision.ok != (tt.wantAction != "") {
+				t.Fatalf("chosen = %q (ok %t), want %q", decision.chosen, decision.ok, tt.wantAction)
+			}
+			if tt.wantAction != "" && !slices.Equal(decision.overrideReasons, []string{"unknown_risk_level"}) {
+				t.Fatalf("override reasons = %q, want unknown_risk_level", decision.overrideReasons)
+			}
+		})
+	}
+}
+
+func TestAlertUnknownRiskLevelIsBounded(t *testing.T) {
+	bot, sink := newTestBot(t, testConfig(t), nil)
+
+	// An engine returning a new level every cycle alerts once and keeps
+	// no state per level
+	for i := range 100 {
+		bot.alertUnknownRiskLevel("0xstrategy", fmt.Sprintf("LEVEL_%d_%s", i, strings.Repeat("X", 1000)))
+	}
+	bot.alerter.Wait()
+	if got := sink.titles(); len(got) != 1 {
+		t.Fatalf("alerts = %q, want one", got)
+	}
+	if level := sink.alerts[0].Fields["risk_level"].(string); len(level) > maxReportedRiskLevel+3 {
+		t.Fatalf("alerted risk level is %d bytes, want it truncated", len(level))
+	}
+	if len(bot.alertsSent) != 1 {
+		t.Fatalf("dedup state holds %d keys, want 1", len(bot.alertsSent))
+	}
+}
@@ -0,0 +1,79 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrRoleMissing is returned when an account does not hold a required role
+var ErrRoleMissing = errors.New("unauthorized")
+
+// accessControlABIJSON is the OpenZeppelin AccessControl role query
+const accessControlABIJSON = `[
+	{"type":"function","name":"hasRole","stateMutability":"view","inputs":[{"name":"role","type":"bytes32"},{"name":"account","type":"address"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+var accessControlABI = mustParseABI(accessControlABIJSON)
+
+// Roles the keeper needs on the contracts it acts on
+var (
+	roleKeeper = crypto.Keccak256Hash([]byte("KEEPER_ROLE")) // LeveragedRWAStrategy
+	roleOracle = crypto.Keccak256Hash([]byte("ORACLE_ROLE")) // VeritasInvoiceToken.updateNav
+)
+
+// roleRequirement is an on-chain role the keeper must hold
+type roleRequirement struct {
+	name     string
+	contract common.Address
+	roleName string
+	role     common.Hash
+}
+
+// requiredRoles lists every role the keeper needs for its configured contracts
+func (b *Bot) requiredRoles() []roleRequirement {
+	var required []roleRequirement
+	for _, strategy := range b.leveragedStrategies {
+		required = append(required, roleRequirement{"strategy_" + strategy.Hex(), strategy, "KEEPER_ROLE", roleKeeper})
+	}
+	for _, token := range b.invoiceTokens {
+		required = append(required, roleRequirement{"invoice_token_" + token.Hex(), token, "ORACLE_ROLE", roleOracle})
+	}
+	return required
+}
+
+// checkRole reports an error if the keeper address lacks a required role
+func (b *Bot) checkRole(ctx context.Context, req roleRequirement) error {
+	return b.checkRoleFor(ctx, req, b.keeperAddress())
+}
+
+// checkRoleFor reports an error if account lacks a required role
+func (b *Bot) checkRoleFor(ctx context.Context, req roleRequirement, account common.Address) error {
+	out, err := b.callContract(ctx, nil, accessControlABI, req.contract, "hasRole", req.role, account)
+	return b.roleResult(req, account, out, err)
+}
+
+// roleCall is the hasRole query for a required role
+func roleCall(req roleRequirement, account common.Address) contractCall {
+	return contractCall{abi: accessControlABI, contract: req.contract, method: "hasRole", args: []interface{}{req.role, account}}
+}
+
+// roleResult interprets the outcome of a hasRole query for account
+func (b *Bot) roleResult(req roleRequirement, account common.Address, out []interface{}, err error) error {
+	if err != nil {
+		return fmt.Errorf("role query failed: %w", err)
+	}
+	if !out[0].(bool) {
+		b.logger.WithFields(logrus.Fields{
+			"keeper":   account.Hex(),
+			"contract": req.contract.Hex(),
+			"role":     req.roleName,
+		}).Error("Keeper address is not authorized on contract; its transactions will revert")
+		return fmt.Errorf("%w: keeper %s lacks %s on %s", ErrRoleMissing, account.Hex(), req.roleName, req.contract.Hex())
+	}
+	return nil
+}
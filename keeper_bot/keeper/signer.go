@@ -0,0 +1,210 @@
+package keeper
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrUnexpectedKeeperAddress is returned when the configured key does not
+// belong to ExpectedKeeperAddress
+var ErrUnexpectedKeeperAddress = errors.New("keeper key does not match expected keeper address")
+
+// signerSource is where a signing key comes from: a raw hex key or an
+// encrypted V3 keystore with its password or password file
+type signerSource struct {
+	privateKey   string
+	keystorePath string
+	password     *string // cleared once the keystore is decrypted
+	passwordFile string
+}
+
+// loadPrivateKey derives the keeper signing key from exactly one of
+// Config.PrivateKey or an encrypted V3 keystore at Config.KeystorePath
+func loadPrivateKey(config *Config) (*ecdsa.PrivateKey, error) {
+	return loadSigner(signerSource{
+		privateKey:   config.PrivateKey,
+		keystorePath: config.KeystorePath,
+		password:     &config.KeystorePassword,
+		passwordFile: config.KeystorePasswordFile,
+	}, "KEEPER_PRIVATE_KEY or KEYSTORE_PATH")
+}
+
+// checkExpectedAddress fails when ExpectedKeeperAddress is set and the
+// signing key derives a different address, as with a key env var pointing
+// at the wrong account
+func checkExpectedAddress(expected string, address common.Address) error {
+	if expected == "" {
+		return nil
+	}
+	if !common.IsHexAddress(expected) {
+		return fmt.Errorf("invalid expected keeper address %q", expected)
+	}
+	if want := common.HexToAddress(expected); want != address {
+		return fmt.Errorf("%w: key derives %s, expected %s", ErrUnexpectedKeeperAddress, address.Hex(), want.Hex())
+	}
+	return nil
+}
+
+// loadIncomingPrivateKey derives the incoming signer used by RotateSigner
+func loadIncomingPrivateKey(config *Config) (*ecdsa.PrivateKey, error) {
+	return loadSigner(signerSource{
+		privateKey:   config.IncomingPrivateKey,
+		keystorePath: config.IncomingKeystorePath,
+		password:     &config.IncomingKeystorePassword,
+		passwordFile: config.IncomingKeystorePasswordFile,
+	}, "INCOMING_PRIVATE_KEY or INCOMING_KEYSTORE_PATH")
+}
+
+// loadSigner loads a key from exactly one of a raw key or a keystore; envHint
+// names the settings to use when neither is configured
+func loadSigner(source signerSource, envHint string) (*ecdsa.PrivateKey, error) {
+	hasRaw := source.privateKey != ""
+	hasKeystore := source.keystorePath != ""
+
+	switch {
+	case hasRaw && hasKeystore:
+		return nil, errors.New("configure either a private key or a keystore, not both")
+	case hasRaw:
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(source.privateKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		return privateKey, nil
+	case hasKeystore:
+		return decryptKeystore(source)
+	default:
+		return nil, fmt.Errorf("no signer configured: set %s", envHint)
+	}
+}
+
+// decryptKeystore decrypts a V3 keystore file, wiping the file contents and
+// password from memory once the key is recovered
+func decryptKeystore(source signerSource) (*ecdsa.PrivateKey, error) {
+	keyJSON, err := os.ReadFile(source.keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	defer zeroBytes(keyJSON)
+
+	password := []byte(*source.password)
+	if source.passwordFile != "" {
+		if *source.password != "" {
+			return nil, errors.New("configure either a keystore password or a password file, not both")
+		}
+		password, err = os.ReadFile(source.passwordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore password file: %w", err)
+		}
+		password = []byte(strings.TrimRight(string(password), "\r\n"))
+	}
+	defer zeroBytes(password)
+
+	key, err := keystore.DecryptKey(keyJSON, string(password))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+
+	// Drop the plaintext password from config now that it is no longer needed
+	*source.password = ""
+	return key.PrivateKey, nil
+}
+
+// zeroBytes overwrites a buffer holding secret material
+func zeroBytes(buf []byte) {
+	for i := range buf {
+		buf[i] = 0
+	}
+}
+
+// cfg returns the current configuration snapshot. Callers reading several
+// related settings, such as a cycle's thresholds, should load it once so a
+// concurrent Reload cannot mix old and new values.
+func (b *Bot) cfg() *Config {
+	return b.config.Load()
+}
+
+// signer returns the current signing key and keeper address
+func (b *Bot) signer() (*ecdsa.PrivateKey, common.Address) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.privateKey, b.address
+}
+
+// keeperAddress returns the current keeper address
+func (b *Bot) keeperAddress() common.Address {
+	_, address := b.signer()
+	return address
+}
+
+// ErrRotationInFlight is returned when the signer cannot be rotated because
+// transactions from the current key are unconfirmed
+var ErrRotationInFlight = errors.New("cannot rotate signer with transactions in flight")
+
+// RotateSigner switches the keeper to the incoming signer. The new address
+// must already hold every required on-chain role. All in-flight slots are
+// held during the switch so no transaction is signed by a half-rotated bot,
+// and the new account's nonce is synced from chain before cutting over.
+func (b *Bot) RotateSigner(ctx context.Context) (oldAddress, newAddress common.Address, err error) {
+	newKey, err := loadIncomingPrivateKey(b.cfg())
+	if err != nil {
+		return oldAddress, newAddress, err
+	}
+	newAddress = crypto.PubkeyToAddress(newKey.PublicKey)
+	oldAddress = b.keeperAddress()
+	if newAddress == oldAddress {
+		return oldAddress, newAddress, fmt.Errorf("incoming signer %s is already active", newAddress.Hex())
+	}
+
+	for _, req := range b.requiredRoles() {
+		if err := b.checkRoleFor(ctx, req, newAddress); err != nil {
+			return oldAddress, newAddress, fmt.Errorf("incoming signer not authorized: %w", err)
+		}
+	}
+
+	held := 0
+	defer func() {
+		for ; held > 0; held-- {
+			b.releaseTxSlot()
+		}
+	}()
+	for held < cap(b.txSlots) {
+		if !b.acquireTxSlot() {
+			return oldAddress, newAddress, ErrRotationInFlight
+		}
+		held++
+	}
+
+	nonce, err := retryValue(ctx, b, "pending_nonce", func(ctx context.Context) (uint64, error) {
+		return b.eth().PendingNonceAt(ctx, newAddress)
+	})
+	if err != nil {
+		return oldAddress, newAddress, fmt.Errorf("failed to sync incoming signer nonce: %w", err)
+	}
+
+	b.mutex.Lock()
+	b.privateKey = newKey
+	b.address = newAddress
+	// The incoming key was verified to hold every role
+	clear(b.revokedRoles)
+	clear(b.roleChecks)
+	b.mutex.Unlock()
+
+	b.alerter.Send(Alert{
+		Severity: AlertWarning,
+		Title:    "Keeper signer rotated",
+		Fields: map[string]interface{}{
+			"old_address": oldAddress.Hex(),
+			"new_address": newAddress.Hex(),
+			"nonce":       nonce,
+		},
+	})
+	return oldAddress, newAddress, nil
+}
@@ -0,0 +1,39 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrSimulationReverted is returned when a pre-flight eth_call of a
+// transaction reverts, so the transaction is not sent
+var ErrSimulationReverted = errors.New("transaction simulation reverted")
+
+// simulateTx dry-runs a contract call from the keeper address against the
+// latest state. A revert is returned as ErrSimulationReverted carrying the
+// decoded reason; other failures are returned as they are.
+func (b *Bot) simulateTx(ctx context.Context, contractABI abi.ABI, to common.Address, method string, args ...interface{}) error {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+
+	msg := ethereum.CallMsg{From: b.keeperAddress(), To: &to, Data: data}
+	_, err = b.eth().CallContract(ctx, msg, nil)
+	if err == nil {
+		return nil
+	}
+	if isRevert(err) {
+		if isUnauthorizedRevert(err) {
+			b.verifyRoleAfterRevert(ctx, to)
+		}
+		return fmt.Errorf("%w: %s", ErrSimulationReverted, revertReasonFor(contractABI, err))
+	}
+	b.noteRPCError(err)
+	return fmt.Errorf("%s simulation failed: %w", method, err)
+}
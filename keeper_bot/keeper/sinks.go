@@ -0,0 +1,145 @@
+package keeper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Alert sink names, as used in Config.AlertRouting
+const (
+	SinkSlack     = "slack"
+	SinkDiscord   = "discord"
+	SinkTelegram  = "telegram"
+	SinkPagerDuty = "pagerduty"
+	SinkWebhook   = "webhook"
+)
+
+// Default endpoints of the hosted alerting APIs
+const (
+	telegramAPIURL    = "https://api.telegram.org"
+	pagerDutyEventURL = "https://events.pagerduty.com/v2/enqueue"
+)
+
+// AlertSink delivers alerts to one notification service
+type AlertSink interface {
+	Name() string
+	Send(alert Alert) error
+}
+
+// newAlertSinks creates a sink for every service configured in config
+func newAlertSinks(config *Config, client *http.Client) []AlertSink {
+	var sinks []AlertSink
+	if config.SlackWebhookURL != "" {
+		sinks = append(sinks, &slackSink{client: client, url: config.SlackWebhookURL})
+	}
+	if config.DiscordWebhookURL != "" {
+		sinks = append(sinks, &discordSink{client: client, url: config.DiscordWebhookURL})
+	}
+	if config.TelegramBotToken != "" && config.TelegramChatID != "" {
+		sinks = append(sinks, &telegramSink{client: client, token: config.TelegramBotToken, chatID: config.TelegramChatID})
+	}
+	if config.PagerDutyRoutingKey != "" {
+		sinks = append(sinks, &pagerDutySink{client: client, routingKey: config.PagerDutyRoutingKey})
+	}
+	if config.AlertWebhookURL != "" {
+		sinks = append(sinks, &webhookSink{client: client, url: config.AlertWebhookURL})
+	}
+	return sinks
+}
+
+// slackSink posts alerts to a Slack incoming webhook
+type slackSink struct {
+	client *http.Client
+	url    string
+}
+
+func (s *slackSink) Name() string { return SinkSlack }
+
+func (s *slackSink) Send(alert Alert) error {
+	return postAlertJSON(s.client, s.url, map[string]string{"text": alert.text()})
+}
+
+// discordSink posts alerts to a Discord webhook
+type discordSink struct {
+	client *http.Client
+	url    string
+}
+
+func (s *discordSink) Name() string { return SinkDiscord }
+
+func (s *discordSink) Send(alert Alert) error {
+	return postAlertJSON(s.client, s.url, map[string]string{"content": alert.text()})
+}
+
+// telegramSink sends alerts to a Telegram chat through a bot
+type telegramSink struct {
+	client *http.Client
+	token  string
+	chatID string
+}
+
+func (s *telegramSink) Name() string { return SinkTelegram }
+
+func (s *telegramSink) Send(alert Alert) error {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", telegramAPIURL, s.token)
+	return postAlertJSON(s.client, url, map[string]string{"chat_id": s.chatID, "text": alert.text()})
+}
+
+// pagerDutySink triggers PagerDuty incidents through the Events API v2
+type pagerDutySink struct {
+	client     *http.Client
+	routingKey string
+}
+
+func (s *pagerDutySink) Name() string { return SinkPagerDuty }
+
+func (s *pagerDutySink) Send(alert Alert) error {
+	severity := "warning"
+	if alert.Severity == AlertCritical {
+		severity = "critical"
+	}
+	return postAlertJSON(s.client, pagerDutyEventURL, map[string]interface{}{
+		"routing_key":  s.routingKey,
+		"event_action": "trigger",
+		"payload": map[string]interface{}{
+			"summary":        alert.Title,
+			"source":         "veritas-keeper",
+			"severity":       severity,
+			"timestamp":      alert.Time,
+			"custom_details": alert.Fields,
+		},
+	})
+}
+
+// webhookSink posts the alert as JSON to a generic webhook
+type webhookSink struct {
+	client *http.Client
+	url    string
+}
+
+func (s *webhookSink) Name() string { return SinkWebhook }
+
+func (s *webhookSink) Send(alert Alert) error {
+	return postAlertJSON(s.client, s.url, alert)
+}
+
+// postAlertJSON posts payload as JSON, treating any non-2xx status as failure
+func postAlertJSON(client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrSlippageExceeded is returned when an emergency deleverage would sell
+// AIT for less than MaxDeleverageSlippageBps below its oracle value
+var ErrSlippageExceeded = errors.New("deleverage slippage exceeds maximum")
+
+// Strategy methods that take a minimum USDC output alongside the AIT to
+// sell. They are optional: when the strategy ABI defines them the bound is
+// enforced on-chain, otherwise only the quote check below guards the swap.
+const (
+	deleverageMinOutMethod           = "emergencyDeleverageWithMinOut"
+	deleverageSubAccountMinOutMethod = "emergencyDeleverageSubAccountWithMinOut"
+)
+
+const deleverageQuoterABIJSON = `[
+	{"type":"function","name":"quote","stateMutability":"view","inputs":[{"name":"tokenIn","type":"address"},{"name":"tokenOut","type":"address"},{"name":"amountIn","type":"uint256"}],"outputs":[{"name":"amountOut","type":"uint256"}]}
+]`
+
+var deleverageQuoterABI = mustParseABI(deleverageQuoterABIJSON)
+
+// validateDeleverageSlippage checks MaxDeleverageSlippageBps is a fraction
+// of the sale and that something can enforce it: a quoter to check before
+// sending or a strategy method taking a minimum output
+func validateDeleverageSlippage(config *Config, abis contractABIs) error {
+	if config.MaxDeleverageSlippageBps == 0 {
+		return nil
+	}
+	if config.MaxDeleverageSlippageBps < 0 || config.MaxDeleverageSlippageBps >= 10000 {
+		return fmt.Errorf("max deleverage slippage %d bps must be between 0 and 10000", config.MaxDeleverageSlippageBps)
+	}
+	if config.DeleverageQuoterAddr != "" && !common.IsHexAddress(config.DeleverageQuoterAddr) {
+		return fmt.Errorf("invalid deleverage quoter address %q", config.DeleverageQuoterAddr)
+	}
+	if _, ok := abis.strategy.Methods[deleverageMinOutMethod]; !ok && config.DeleverageQuoterAddr == "" {
+		return fmt.Errorf("max deleverage slippage needs DELEVERAGE_QUOTER_ADDR or a strategy ABI defining %s", deleverageMinOutMethod)
+	}
+	return nil
+}
+
+// warnSlippageUnenforced logs an error and alerts when the slippage guard is
+// enabled but the strategy ABI lacks a method taking a minimum output. The
+// quoter still refuses a bad quote, but the swap itself is sent unbounded and
+// can fill below it if the price moves before inclusion.
+func (b *Bot) warnSlippageUnenforced() {
+	config := b.cfg()
+	if config.MaxDeleverageSlippageBps == 0 {
+		return
+	}
+	var missing []string
+	for _, method := range []string{deleverageMinOutMethod, deleverageSubAccountMinOutMethod} {
+		if _, ok := b.abis.strategy.Methods[method]; !ok {
+			missing = append(missing, method)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	b.logger.WithField("missing_methods", missing).Error("Deleverage slippage bound is not enforced on-chain: emergency deleverages are sent without a minimum output")
+	b.alerter.Send(Alert{
+		Severity: AlertCritical,
+		Title:    "Deleverage slippage not enforced on-chain",
+		Fields: map[string]interface{}{
+			"missing_methods": missing,
+			"quoter":          config.DeleverageQuoterAddr,
+		},
+	})
+}
+
+// deleverageOracleValue is the USDC an AIT sale is worth at the AIT token's
+// on-chain NAV, in the debt token's decimals
+func (b *Bot) deleverageOracleValue(ctx context.Context, strategy common.Address, aitToSell *big.Int) (*big.Int, error) {
+	tokens := b.strategyDecimals[strategy]
+	out, err := b.callContract(ctx, nil, b.abis.invoiceToken, tokens.aitToken, "navPerToken")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read AIT NAV: %w", err)
+	}
+	value := new(big.Rat).Mul(
+		fromFixedPoint(aitToSell, tokens.ait),
+		fromFixedPoint(out[0].(*big.Int), navDecimals),
+	)
+	return toFixedPoint(value, tokens.debt), nil
+}
+
+// minDeleverageOutput returns the least USDC an AIT sale may return:
+// its oracle value less MaxDeleverageSlippageBps, rounded down
+func minDeleverageOutput(expected *big.Int, slippageBps int) *big.Int {
+	bound := new(big.Int).Mul(expected, big.NewInt(int64(10000-slippageBps)))
+	return bound.Quo(bound, big.NewInt(10000))
+}
+
+// checkDeleverageQuote returns ErrSlippageExceeded when quoted, the USDC a
+// sale would fetch now, is below minOut
+func checkDeleverageQuote(quoted, minOut *big.Int) error {
+	if quoted.Cmp(minOut) < 0 {
+		return fmt.Errorf("%w: quoted %s below minimum %s", ErrSlippageExceeded, quoted, minOut)
+	}
+	return nil
+}
+
+// deleverageMinOut computes the minimum output of an emergency deleverage
+// selling aitToSell and, when a quoter is configured, refuses a sale whose
+// quote falls below it. It returns nil when the guard is disabled.
+func (b *Bot) deleverageMinOut(ctx context.Context, strategy common.Address, aitToSell *big.Int) (*big.Int, error) {
+	config := b.cfg()
+	if config.MaxDeleverageSlippageBps == 0 {
+		return nil, nil
+	}
+	expected, err := b.deleverageOracleValue(ctx, strategy, aitToSell)
+	if err != nil {
+		return nil, err
+	}
+	minOut := minDeleverageOutput(expected, config.MaxDeleverageSlippageBps)
+	if config.DeleverageQuoterAddr == "" {
+		return minOut, nil
+	}
+
+	tokens := b.strategyDecimals[strategy]
+	out, err := b.callContract(ctx, nil, deleverageQuoterABI, common.HexToAddress(config.DeleverageQuoterAddr),
+		"quote", tokens.aitToken, tokens.debtToken, aitToSell)
+	if err != nil {
+		return nil, fmt.Errorf("failed to quote deleverage: %w", err)
+	}
+	if err := checkDeleverageQuote(out[0].(*big.Int), minOut); err != nil {
+		return nil, err
+	}
+	return minOut, nil
+}
+
+// deleverageCall returns the strategy method and arguments of an emergency
+// deleverage, passing minOut when the strategy accepts one
+func (b *Bot) deleverageCall(account common.Address, subAccount bool, aitToSell, minOut *big.Int) (string, []interface{}) {
+	if minOut != nil {
+		if subAccount {
+			if _, ok := b.abis.strategy.Methods[deleverageSubAccountMinOutMethod]; ok {
+				return deleverageSubAccountMinOutMethod, []interface{}{account, aitToSell, minOut}
+			}
+		} else if _, ok := b.abis.strategy.Methods[deleverageMinOutMethod]; ok {
+			return deleverageMinOutMethod, []interface{}{aitToSell, minOut}
+		}
+	}
+	if subAccount {
+		return "emergencyDeleverageSubAccount", []interface{}{account, aitToSell}
+	}
+	return "emergencyDeleverage", []interface{}{aitToSell}
+}
@@ -0,0 +1,56 @@
+package keeper
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+)
+
+func TestCheckDeleverageQuote(t *testing.T) {
+	// 10,000 USDC of AIT at 100 bps slippage may return no less than 9,900
+	minOut := minDeleverageOutput(big.NewInt(10_000_000_000), 100)
+	if want := big.NewInt(9_900_000_000); minOut.Cmp(want) != 0 {
+		t.Fatalf("minDeleverageOutput = %s, want %s", minOut, want)
+	}
+
+	tests := []struct {
+		name   string
+		quoted int64
+		want   error
+	}{
+		{"above minimum", 9_950_000_000, nil},
+		{"at minimum", 9_900_000_000, nil},
+		{"below minimum", 9_899_999_999, ErrSlippageExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDeleverageQuote(big.NewInt(tt.quoted), minOut)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("checkDeleverageQuote(%d) = %v, want %v", tt.quoted, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWarnSlippageUnenforced(t *testing.T) {
+	config := testConfig(t)
+	config.MaxDeleverageSlippageBps = 100
+	config.DeleverageQuoterAddr = "0x00000000000000000000000000000000000000cc"
+	bot, sink := newTestBot(t, config, nil)
+
+	// The stock strategy ABI has no min-out deleverage methods
+	bot.warnSlippageUnenforced()
+	bot.alerter.Wait()
+	if got, want := sink.titles(), []string{"Deleverage slippage not enforced on-chain"}; !slices.Equal(got, want) {
+		t.Fatalf("alerts = %q, want %q", got, want)
+	}
+
+	config.MaxDeleverageSlippageBps = 0
+	quiet, sink := newTestBot(t, config, nil)
+	quiet.warnSlippageUnenforced()
+	quiet.alerter.Wait()
+	if got := sink.titles(); len(got) != 0 {
+		t.Fatalf("alerts with guard disabled = %q, want none", got)
+	}
+}
@@ -0,0 +1,65 @@
+package keeper
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// StatsDSink pushes metrics to a StatsD agent over UDP in the DogStatsD
+// format, labels becoming name:value tags. Sends are fire-and-forget: a
+// missing agent loses metrics but never blocks or fails the keeper.
+type StatsDSink struct {
+	conn net.Conn
+}
+
+// NewStatsDSink creates a sink sending to the agent at addr (host:port)
+func NewStatsDSink(addr string) (*StatsDSink, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open StatsD connection: %w", err)
+	}
+	return &StatsDSink{conn: conn}, nil
+}
+
+// Gauge implements MetricSink
+func (s *StatsDSink) Gauge(name string, value float64, labels []string) {
+	s.send(name, value, "g", labels)
+}
+
+// Count implements MetricSink
+func (s *StatsDSink) Count(name string, delta float64, labels []string) {
+	s.send(name, delta, "c", labels)
+}
+
+// Histogram implements HistogramSink
+func (s *StatsDSink) Histogram(name string, value float64, labels []string) {
+	s.send(name, value, "h", labels)
+}
+
+// Close closes the UDP connection
+func (s *StatsDSink) Close() error {
+	return s.conn.Close()
+}
+
+// send writes one metric datagram
+func (s *StatsDSink) send(name string, value float64, kind string, labels []string) {
+	line := name + ":" + strconv.FormatFloat(value, 'f', -1, 64) + "|" + kind
+	if tags := statsdTags(labels); tags != "" {
+		line += "|#" + tags
+	}
+	s.conn.Write([]byte(line))
+}
+
+// statsdTagReplacer strips the DogStatsD separators from tag text
+var statsdTagReplacer = strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_")
+
+// statsdTags renders alternating name/value pairs as DogStatsD tags
+func statsdTags(labels []string) string {
+	tags := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		tags = append(tags, statsdTagReplacer.Replace(labels[i])+":"+statsdTagReplacer.Replace(labels[i+1]))
+	}
+	return strings.Join(tags, ",")
+}
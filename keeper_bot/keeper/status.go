@@ -0,0 +1,66 @@
+package keeper
+
+// Status is the operator-facing snapshot served on /status
+type Status struct {
+	Address       string `json:"address"`
+	Profile       string `json:"profile"`
+	EmergencyMode bool   `json:"emergency_mode"`
+	// EmergencyPending are emergency deleverages awaiting confirmation
+	EmergencyPending []PendingEmergency `json:"emergency_pending"`
+	// Degraded is set while a keeper role is revoked or degraded mode is
+	// active, for DegradedReasons
+	Degraded         bool              `json:"degraded"`
+	DegradedReasons  []string          `json:"degraded_reasons,omitempty"`
+	RevokedRoles     []string          `json:"revoked_roles"`
+	InStartupGrace   bool              `json:"in_startup_grace"`
+	InFlightTx       int               `json:"in_flight_tx"`
+	GasSpentWei      string            `json:"gas_spent_wei"`
+	GasSpentByAction map[string]string `json:"gas_spent_by_action_wei"`
+	Health           *HealthReport     `json:"health,omitempty"`
+	ModelVersion     string            `json:"model_version,omitempty"`
+	Cooldowns        []ActiveCooldown  `json:"cooldowns"`
+	Monitors         []MonitorState    `json:"monitors"`
+	Jobs             []JobStatus       `json:"jobs"`
+	// Leverage and NAV are the results of the last run of each monitor
+	Leverage []LeverageResult `json:"leverage,omitempty"`
+	NAV      []NAVResult      `json:"nav,omitempty"`
+}
+
+// Status returns a snapshot of the bot's operational state
+func (b *Bot) Status() Status {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	byAction := make(map[string]string, len(b.gasSpentByAction))
+	for action, spent := range b.gasSpentByAction {
+		byAction[action] = spent.String()
+	}
+
+	var modelVersion string
+	if b.modelInfo != nil {
+		modelVersion = b.modelInfo.Version
+	}
+
+	leverage, nav := b.latestResults()
+
+	return Status{
+		Address:          b.address.Hex(),
+		Profile:          b.cfg().Profile,
+		EmergencyMode:    b.emergencyMode,
+		EmergencyPending: b.pendingEmergencyList(),
+		Degraded:         len(b.revokedRoles) > 0 || len(b.degraded) > 0,
+		DegradedReasons:  b.degraded,
+		RevokedRoles:     b.revokedContracts(),
+		InStartupGrace:   b.inStartupGrace(),
+		InFlightTx:       len(b.txSlots),
+		GasSpentWei:      b.gasSpent.String(),
+		GasSpentByAction: byAction,
+		Health:           b.lastHealth,
+		ModelVersion:     modelVersion,
+		Cooldowns:        b.activeCooldowns(),
+		Monitors:         b.monitorStates(),
+		Jobs:             b.jobStatuses(),
+		Leverage:         leverage,
+		NAV:              nav,
+	}
+}
@@ -0,0 +1,159 @@
+package keeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// State backends selectable via Config.StateBackend
+const (
+	StateBackendMemory = "memory"
+	StateBackendFile   = "file"
+)
+
+// Store persists raw bot state by name. Use Load and Save with a typed
+// StoreKey rather than calling Get and Set directly.
+type Store interface {
+	Get(name string) ([]byte, bool, error)
+	Set(name string, data []byte) error
+}
+
+// StoreKey names a state value of type T
+type StoreKey[T any] struct {
+	name string
+}
+
+// State persisted through the Store
+var (
+	keyEmergencyMode = StoreKey[bool]{"emergency_mode"}
+	keyTxBudget      = StoreKey[txBudget]{"tx_budget"}
+	keyAlertsSent    = StoreKey[map[string]time.Time]{"alerts_sent"}
+)
+
+// Load reads a typed value; ok is false if it has never been saved
+func Load[T any](store Store, key StoreKey[T]) (value T, ok bool, err error) {
+	data, ok, err := store.Get(key.name)
+	if err != nil || !ok {
+		return value, false, err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, false, fmt.Errorf("failed to decode state %q: %w", key.name, err)
+	}
+	return value, true, nil
+}
+
+// Save writes a typed value
+func Save[T any](store Store, key StoreKey[T], value T) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode state %q: %w", key.name, err)
+	}
+	return store.Set(key.name, data)
+}
+
+// newStore creates the state backend selected by config
+func newStore(config *Config) (Store, error) {
+	switch config.StateBackend {
+	case "", StateBackendMemory:
+		return NewMemoryStore(), nil
+	case StateBackendFile:
+		return NewFileStore(config.StatePath)
+	default:
+		return nil, fmt.Errorf("unknown state backend %q", config.StateBackend)
+	}
+}
+
+// MemoryStore is a Store that lives only as long as the process
+type MemoryStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: make(map[string][]byte)}
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(name string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.values[name]
+	return data, ok, nil
+}
+
+// Set implements Store
+func (s *MemoryStore) Set(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[name] = data
+	return nil
+}
+
+// FileStore is a Store backed by a single JSON file, rewritten atomically on each Set
+type FileStore struct {
+	mu     sync.Mutex
+	path   string
+	values map[string]json.RawMessage
+}
+
+// NewFileStore opens (or creates on first write) a JSON state file
+func NewFileStore(path string) (*FileStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file state backend requires a state path")
+	}
+
+	store := &FileStore{path: path, values: make(map[string]json.RawMessage)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	if err := json.Unmarshal(data, &store.values); err != nil {
+		return nil, fmt.Errorf("corrupt state file %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// Get implements Store
+func (s *FileStore) Get(name string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.values[name]
+	return data, ok, nil
+}
+
+// Set implements Store
+func (s *FileStore) Set(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[name] = data
+
+	encoded, err := json.MarshalIndent(s.values, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".state-*")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(encoded); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
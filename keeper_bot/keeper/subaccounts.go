@@ -0,0 +1,90 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+type subAccountCtx struct{}
+
+// withSubAccount targets risk actions taken with ctx at one sub-account
+// instead of the whole strategy
+func withSubAccount(ctx context.Context, account common.Address) context.Context {
+	return context.WithValue(ctx, subAccountCtx{}, account)
+}
+
+// subAccountFrom returns the sub-account targeted by ctx, if any
+func subAccountFrom(ctx context.Context) (common.Address, bool) {
+	account, ok := ctx.Value(subAccountCtx{}).(common.Address)
+	return account, ok
+}
+
+// monitoredAccounts lists the positions of a strategy to assess: its
+// sub-accounts when SubAccountMonitoring is on and the contract exposes them,
+// otherwise the zero address standing for the aggregate position
+func (b *Bot) monitoredAccounts(ctx context.Context, strategy common.Address) []common.Address {
+	aggregate := []common.Address{{}}
+	if !b.cfg().SubAccountMonitoring {
+		return aggregate
+	}
+
+	b.mutex.Lock()
+	unsupported := b.noSubAccounts[strategy]
+	b.mutex.Unlock()
+	if unsupported {
+		return aggregate
+	}
+
+	logger := b.logger.WithField("strategy", strategy.Hex())
+	out, err := b.callContract(ctx, b.leverageBlock, b.abis.strategy, strategy, "getSubAccounts")
+	if err != nil {
+		if isRevert(err) {
+			logger.Info("Strategy does not expose sub-accounts, monitoring aggregate position")
+			b.mutex.Lock()
+			b.noSubAccounts[strategy] = true
+			b.mutex.Unlock()
+		} else {
+			logger.WithError(err).Warn("Failed to list sub-accounts, monitoring aggregate position")
+		}
+		return aggregate
+	}
+
+	accounts := out[0].([]common.Address)
+	if len(accounts) == 0 {
+		return aggregate
+	}
+	logger.WithField("sub_accounts", len(accounts)).Debug("Monitoring sub-accounts")
+	return accounts
+}
+
+// positionFields are the log fields identifying a strategy position
+func positionFields(strategy, account common.Address) logrus.Fields {
+	fields := logrus.Fields{"strategy": strategy.Hex()}
+	if account != (common.Address{}) {
+		fields["account"] = account.Hex()
+	}
+	return fields
+}
+
+// positionLabels are the metric labels identifying a strategy position
+func positionLabels(strategy, account common.Address) []string {
+	if account == (common.Address{}) {
+		return []string{"strategy", strategy.Hex()}
+	}
+	return []string{"strategy", strategy.Hex(), "account", account.Hex()}
+}
+
+// isRevert reports whether a contract call failed because the call reverted,
+// as it does for a method the contract does not implement
+func isRevert(err error) bool {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		return true
+	}
+	return strings.Contains(err.Error(), "execution reverted")
+}
@@ -0,0 +1,76 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const erc20DecimalsABIJSON = `[
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}
+]`
+
+var erc20DecimalsABI = mustParseABI(erc20DecimalsABIJSON)
+
+// strategyTokens are the decimals of the tokens a strategy's position
+// amounts are denominated in
+type strategyTokens struct {
+	collateral int // mETH supplied
+	debt       int // USDC borrowed
+	ait        int // invoice token held, also the scale of its value
+
+	debtToken common.Address // USDC, whose balance repays the debt
+	aitToken  common.Address // invoice token sold by an emergency deleverage
+}
+
+// loadTokenDecimals reads and caches the decimals of every token the monitors
+// scale amounts of: each strategy's collateral, debt and invoice tokens and
+// each configured invoice token. Decimals are immutable, so they are read
+// once; a failure is returned because scaling with a guess is unsafe.
+func (b *Bot) loadTokenDecimals(ctx context.Context) error {
+	cache := make(map[common.Address]int)
+	decimalsOf := func(token common.Address) (int, error) {
+		if decimals, ok := cache[token]; ok {
+			return decimals, nil
+		}
+		out, err := b.callContract(ctx, nil, erc20DecimalsABI, token, "decimals")
+		if err != nil {
+			return 0, fmt.Errorf("failed to read decimals of token %s: %w", token.Hex(), err)
+		}
+		decimals := int(out[0].(uint8))
+		cache[token] = decimals
+		return decimals, nil
+	}
+
+	for _, strategy := range b.leveragedStrategies {
+		var tokens [3]int
+		var addresses [3]common.Address
+		for i, getter := range []string{"mETH", "usdc", "ait"} {
+			out, err := b.callContract(ctx, nil, b.abis.strategy, strategy, getter)
+			if err != nil {
+				return fmt.Errorf("failed to read %s token of strategy %s: %w", getter, strategy.Hex(), err)
+			}
+			addresses[i] = out[0].(common.Address)
+			if tokens[i], err = decimalsOf(addresses[i]); err != nil {
+				return fmt.Errorf("strategy %s: %w", strategy.Hex(), err)
+			}
+		}
+		b.strategyDecimals[strategy] = strategyTokens{
+			collateral: tokens[0],
+			debt:       tokens[1],
+			ait:        tokens[2],
+			debtToken:  addresses[1],
+			aitToken:   addresses[2],
+		}
+	}
+
+	for _, token := range b.invoiceTokens {
+		decimals, err := decimalsOf(token)
+		if err != nil {
+			return err
+		}
+		b.tokenDecimals[token] = decimals
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrTooManyInFlight is returned when MaxInFlightTx unconfirmed transactions are already pending
+var ErrTooManyInFlight = errors.New("too many unconfirmed transactions in flight")
+
+// sendTx packs and broadcasts a contract call, holding an in-flight slot until
+// the transaction confirms or TxConfirmTimeout elapses. In dry-run mode and
+// during the startup grace period the transaction is signed and audited but
+// never broadcast.
+func (b *Bot) sendTx(ctx context.Context, action string, contractABI abi.ABI, to common.Address, method string, args ...interface{}) (*types.Transaction, error) {
+	if err := b.checkNotRevoked(to); err != nil {
+		return nil, fmt.Errorf("%s transaction not sent: %w", action, err)
+	}
+	if b.degradedBlocks(action) {
+		b.metrics.AddCounter(metricTxSkipped, 1, "reason", "degraded")
+		return nil, fmt.Errorf("%s transaction not sent: %w", action, ErrDegraded)
+	}
+
+	inGrace := b.InStartupGrace()
+	record := AuditRecord{
+		Action:   action,
+		DryRun:   b.cfg().DryRun || inGrace,
+		Contract: to.Hex(),
+		Method:   method,
+		Inputs:   auditInputs(args),
+
+		IdempotencyKey: idempotencyKeyFrom(ctx),
+	}
+
+	if record.DryRun {
+		tx, err := b.signTx(ctx, urgencyOf(action), contractABI, to, method, args...)
+		if err != nil {
+			record.RevertReason, err = explainRevert(contractABI, err)
+			record.Outcome, record.Error = AuditFailed, err.Error()
+			b.audit(record)
+			return nil, fmt.Errorf("%s transaction failed: %w", action, err)
+		}
+		record.Outcome = AuditDryRun
+		b.audit(record.withTx(tx))
+		logger := b.logger.WithFields(logrus.Fields{
+			"action": action,
+			"tx":     tx.Hash().Hex(),
+		})
+		if inGrace {
+			b.metrics.AddCounter(metricTxSkipped, 1, "reason", "startup_grace")
+			logger.Warn("Startup grace period: transaction signed but not broadcast")
+		} else {
+			logger.Info("Dry run: transaction signed but not broadcast")
+		}
+		return tx, nil
+	}
+
+	if !b.acquireTxSlot() {
+		b.logger.WithFields(logrus.Fields{
+			"action":    action,
+			"in_flight": len(b.txSlots),
+		}).Warn("In-flight transaction limit reached, skipping action")
+		b.metrics.AddCounter(metricTxSkipped, 1, "reason", "in_flight_limit")
+		return nil, ErrTooManyInFlight
+	}
+
+	tx, err := b.signAndBroadcast(ctx, action, contractABI, to, method, args...)
+	sentAt := time.Now()
+	if errors.Is(err, ErrDailyBudgetExceeded) {
+		b.releaseTxSlot()
+		b.metrics.AddCounter(metricTxSkipped, 1, "reason", "daily_budget")
+		b.sendDeduped("tx_budget:"+action, Alert{
+			Severity: AlertCritical,
+			Title:    "Daily transaction budget exhausted, refusing to send",
+			Fields:   map[string]interface{}{"action": action},
+		})
+		return nil, err
+	}
+	if tx != nil {
+		record = record.withTx(tx)
+	}
+	if err != nil {
+		b.releaseTxSlot()
+		record.RevertReason, err = explainRevert(contractABI, err)
+		record.Outcome, record.Error = AuditFailed, err.Error()
+		b.audit(record)
+		return nil, fmt.Errorf("%s transaction failed: %w", action, err)
+	}
+	b.recordBudget(action, tx)
+	record.Outcome = AuditSent
+	b.audit(record)
+
+	b.logger.WithFields(logrus.Fields{
+		"action": action,
+		"tx":     tx.Hash().Hex(),
+		"nonce":  tx.Nonce(),
+	}).Info("Transaction sent")
+
+	b.goBackground(func(ctx context.Context) { b.awaitConfirmation(ctx, contractABI, record, tx, sentAt) })
+	return tx, nil
+}
+
+// signTx builds and signs a contract call transaction
+func (b *Bot) signTx(ctx context.Context, urgency TxUrgency, contractABI abi.ABI, to common.Address, method string, args ...interface{}) (*types.Transaction, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+
+	auth, err := b.getTransactOpts(ctx, urgency)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    auth.Nonce.Uint64(),
+		GasPrice: auth.GasPrice,
+		Gas:      auth.GasLimit,
+		To:       &to,
+		Value:    auth.Value,
+		Data:     data,
+	})
+
+	signed, err := auth.Signer(auth.From, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signed, nil
+}
+
+// signAndBroadcast signs and broadcasts a contract call. A nonce the node
+// rejects as too low or too high means the pending nonce moved since it was
+// read, e.g. another send raced it or a load-balanced node lagged, so the
+// call is re-signed with a nonce resynced from PendingNonceAt and sent once
+// more. Each signed transaction is checked against the daily budget before it
+// is broadcast, and none is returned when the budget rejects it. Otherwise the
+// last signed transaction is returned even when sending fails.
+func (b *Bot) signAndBroadcast(ctx context.Context, action string, contractABI abi.ABI, to common.Address, method string, args ...interface{}) (*types.Transaction, error) {
+	tx, err := b.signTx(ctx, urgencyOf(action), contractABI, to, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.checkBudget(action, txFeeCap(tx)); err != nil {
+		return nil, err
+	}
+	sendErr := b.broadcast(ctx, action, tx)
+	if !nonceMismatch(sendErr) {
+		if sendErr == nil {
+			b.noteNonceUsed(tx.Nonce())
+		}
+		return tx, sendErr
+	}
+
+	stale := tx
+	b.resetNonce()
+	tx, err = b.signTx(ctx, urgencyOf(action), contractABI, to, method, args...)
+	if err != nil {
+		return stale, fmt.Errorf("failed to resync nonce: %w", err)
+	}
+	if err := b.checkBudget(action, txFeeCap(tx)); err != nil {
+		return nil, err
+	}
+	b.logger.WithFields(logrus.Fields{
+		"action":    action,
+		"old_nonce": stale.Nonce(),
+		"new_nonce": tx.Nonce(),
+	}).WithError(sendErr).Warn("Nonce out of sync, resynced and retrying send")
+	if err := b.broadcast(ctx, action, tx); err != nil {
+		return tx, err
+	}
+	b.noteNonceUsed(tx.Nonce())
+	return tx, nil
+}
+
+// nonceMismatch reports whether a send failed because its nonce was already
+// used or is ahead of the account's next nonce
+func nonceMismatch(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "nonce too high")
+}
+
+// broadcast submits a signed transaction through the private relay when one
+// is configured for action, otherwise to the public mempool. A relay failure
+// is returned rather than falling back, so a protected transaction is never
+// silently exposed to front-running.
+func (b *Bot) broadcast(ctx context.Context, action string, tx *types.Transaction) error {
+	if b.privateRelay == nil || !b.usesPrivateRelay(action) {
+		return b.txClient().SendTransaction(ctx, tx)
+	}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	var result interface{}
+	params := map[string]string{"tx": hexutil.Encode(raw)}
+	if err := b.privateRelay.CallContext(ctx, &result, "eth_sendPrivateTransaction", params); err != nil {
+		return fmt.Errorf("private relay submission failed: %w", err)
+	}
+
+	b.logger.WithFields(logrus.Fields{
+		"action": action,
+		"tx":     tx.Hash().Hex(),
+	}).Info("Transaction submitted via private relay")
+	return nil
+}
+
+// usesPrivateRelay reports whether action is configured for private submission
+func (b *Bot) usesPrivateRelay(action string) bool {
+	for _, private := range b.cfg().PrivateTxActions {
+		if private == action {
+			return true
+		}
+	}
+	return false
+}
+
+// awaitConfirmation waits for a transaction receipt, audits the outcome and
+// frees its in-flight slot
+func (b *Bot) awaitConfirmation(ctx context.Context, contractABI abi.ABI, record AuditRecord, tx *types.Transaction, sentAt time.Time) {
+	defer b.releaseTxSlot()
+	action := record.Action
+
+	ctx, cancel := context.WithTimeout(ctx, b.cfg().TxConfirmTimeout)
+	defer cancel()
+
+	logger := b.logger.WithFields(logrus.Fields{
+		"action": action,
+		"tx":     tx.Hash().Hex(),
+	})
+
+	receipt, depth, err := b.waitForConfirmations(ctx, tx)
+	if errors.Is(err, context.Canceled) {
+		logger.Debug("Stopped waiting for transaction on shutdown")
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Warn("Transaction not confirmed before timeout, releasing in-flight slot")
+		record.Outcome, record.Error = AuditUnconfirmed, err.Error()
+		b.audit(record)
+		return
+	}
+
+	b.recordGasSpent(action, receipt)
+	b.recordConfirmationTime(ctx, action, receipt, sentAt)
+	record = record.withReceipt(receipt)
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		record.RevertReason = b.replayRevert(ctx, contractABI, tx, receipt)
+	}
+	b.audit(record)
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		logger.WithFields(logrus.Fields{
+			"block":         receipt.BlockNumber,
+			"revert_reason": record.RevertReason,
+		}).Error("Transaction reverted")
+		// A revoked role makes every later action revert too
+		b.verifyRoleAfterRevert(ctx, *tx.To())
+		return
+	}
+	logger.WithFields(logrus.Fields{
+		"block":         receipt.BlockNumber,
+		"confirmations": depth,
+	}).Info("Transaction confirmed")
+}
+
+// recordGasSpent accumulates the fee paid by a mined transaction, reverted or not
+func (b *Bot) recordGasSpent(action string, receipt *types.Receipt) {
+	if receipt.EffectiveGasPrice == nil {
+		return
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+
+	b.mutex.Lock()
+	b.gasSpent.Add(b.gasSpent, fee)
+	if _, ok := b.gasSpentByAction[action]; !ok {
+		b.gasSpentByAction[action] = new(big.Int)
+	}
+	b.gasSpentByAction[action].Add(b.gasSpentByAction[action], fee)
+	b.mutex.Unlock()
+
+	feeFloat, _ := new(big.Float).SetInt(fee).Float64()
+	b.metrics.AddCounter(metricGasSpentWei, feeFloat, "action", action)
+}
+
+// recordConfirmationTime observes how long a mined transaction took from
+// broadcast to inclusion, taking inclusion as its block's timestamp so the
+// receipt polling interval does not inflate it
+func (b *Bot) recordConfirmationTime(ctx context.Context, action string, receipt *types.Receipt, sentAt time.Time) {
+	header, err := b.eth().HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		b.logger.WithError(err).WithField("action", action).Debug("Failed to read including block, confirmation time not recorded")
+		return
+	}
+	// Block timestamps have second resolution and may trail the local clock
+	elapsed := max(time.Unix(int64(header.Time), 0).Sub(sentAt), 0)
+
+	b.metrics.ObserveHistogram(metricTxConfirm, elapsed.Seconds(), "action", action)
+	b.logger.WithFields(logrus.Fields{
+		"action":            action,
+		"tx":                receipt.TxHash.Hex(),
+		"block":             receipt.BlockNumber,
+		"time_to_inclusion": elapsed.Round(time.Second).String(),
+	}).Info("Transaction included")
+}
+
+// acquireTxSlot reserves an in-flight slot without blocking
+func (b *Bot) acquireTxSlot() bool {
+	select {
+	case b.txSlots <- struct{}{}:
+		b.metrics.SetGauge(metricInFlightTx, float64(len(b.txSlots)))
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseTxSlot frees an in-flight slot
+func (b *Bot) releaseTxSlot() {
+	<-b.txSlots
+	b.metrics.SetGauge(metricInFlightTx, float64(len(b.txSlots)))
+}
+
+// InFlightTxCount returns the number of unconfirmed keeper transactions
+func (b *Bot) InFlightTxCount() int {
+	return len(b.txSlots)
+}
+
+// Metrics returns the bot's metrics registry
+func (b *Bot) Metrics() *Metrics {
+	return b.metrics
+}
@@ -0,0 +1,92 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// fakeTxNode is a txBackend whose pending nonce and send failures the test
+// controls. A send failing with a nonce error moves the pending nonce on, as
+// when another sender raced the keeper.
+type fakeTxNode struct {
+	mu         sync.Mutex
+	pending    uint64
+	sendErrs   []error
+	nonceReads int
+	sent       []*types.Transaction
+}
+
+func (n *fakeTxNode) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.nonceReads++
+	return n.pending, nil
+}
+
+func (n *fakeTxNode) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return big.NewInt(1_000_000_000), nil
+}
+
+func (n *fakeTxNode) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, tx)
+	if len(n.sendErrs) > 0 {
+		err := n.sendErrs[0]
+		n.sendErrs = n.sendErrs[1:]
+		if nonceMismatch(err) {
+			n.pending++
+		}
+		return err
+	}
+	n.pending = tx.Nonce() + 1
+	return nil
+}
+
+func TestSignAndBroadcastResyncsNonce(t *testing.T) {
+	bot, _ := newTestBot(t, testConfig(t), nil)
+	node := &fakeTxNode{pending: 5, sendErrs: []error{errors.New("nonce too low")}}
+	bot.txNode = node
+	strategy := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	tx, err := bot.signAndBroadcast(context.Background(), "reduce_leverage", strategyABI, strategy, "repayDebt", big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(node.sent) != 2 {
+		t.Fatalf("sent %d transactions, want 2", len(node.sent))
+	}
+	if node.nonceReads != 2 {
+		t.Fatalf("read the pending nonce %d times, want one read and one resync", node.nonceReads)
+	}
+	if got := node.sent[0].Nonce(); got != 5 {
+		t.Fatalf("first send nonce = %d, want 5", got)
+	}
+	if tx.Hash() != node.sent[1].Hash() || tx.Nonce() != 6 {
+		t.Fatalf("returned tx nonce %d, want the resent tx with nonce 6", tx.Nonce())
+	}
+	if got := bot.nextNonce(0); got != 7 {
+		t.Fatalf("next local nonce = %d, want 7", got)
+	}
+}
+
+func TestSignAndBroadcastGivesUpAfterOneResync(t *testing.T) {
+	bot, _ := newTestBot(t, testConfig(t), nil)
+	node := &fakeTxNode{pending: 5, sendErrs: []error{errors.New("nonce too low"), errors.New("nonce too high")}}
+	bot.txNode = node
+	strategy := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	_, err := bot.signAndBroadcast(context.Background(), "reduce_leverage", strategyABI, strategy, "repayDebt", big.NewInt(1))
+	if !nonceMismatch(err) {
+		t.Fatalf("err = %v, want the second nonce error", err)
+	}
+	if len(node.sent) != 2 {
+		t.Fatalf("sent %d transactions, want 2", len(node.sent))
+	}
+}
@@ -1,53 +1,586 @@
 package keeper
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"math/big"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 )
 
 type Config struct {
-	MantleRPC string
-	ChainID   int64
-	LeveragedStrategyAddr string
-	InvoiceTokenAddr      string
-	KYCVerifierAddr       string
+	// Profile names the baseline defaults (mainnet, testnet or local)
+	Profile string
+
+	MantleRPC              string
+	ChainID                int64
+	LeveragedStrategyAddrs []string
+	InvoiceTokenAddrs      []string
+	KYCVerifierAddr        string
+
+	// ChainsPath is a JSON file listing chains to monitor at once, each with
+	// its own RPC, chain ID, contract addresses and optionally signer (see
+	// ChainConfig); the settings above are then ignored. ChainName is set
+	// on each chain's derived config and labels its logs, metrics and alerts.
+	ChainsPath string
+	ChainName  string
+
+	// Optional ABI JSON files (a bare ABI or a compiler artifact) replacing
+	// the built-in minimal ABIs, e.g. after a contract upgrade
+	StrategyABIPath string
+	InvoiceABIPath  string
+	KYCABIPath      string
 
 	MLAPIEndpoint string
+	MLAPIBasePath string
+
+	// AllowLocalMLEndpoint permits a localhost ML engine on the mainnet
+	// profile, e.g. a sidecar, which is otherwise refused as a leftover
+	// development default
+	AllowLocalMLEndpoint bool
+
+	// MLEndpoints routes individual ML endpoints (e.g. kyc-risk-assessment)
+	// to their own engine base URL, such as a canary, overriding
+	// MLAPIEndpoint for them; an ensemble still assesses leverage itself
+	MLEndpoints map[string]string
+
+	// Per-request ML timeouts: a short one for /health probes, which also
+	// bounds each HealthCheck as a whole, and a longer one for each
+	// prediction call attempt
+	HealthCheckTimeout time.Duration
+	MLRequestTimeout   time.Duration
+
+	// MaxModelAge alerts when the ML engine's model was trained longer ago
+	// than this (0 disables)
+	MaxModelAge time.Duration
+
+	// MLHeaders are added to every ML engine request (they never replace the
+	// Content-Type, idempotency or schema version headers); MLProxyURL routes
+	// ML traffic through an HTTP proxy
+	MLHeaders  map[string]string
+	MLProxyURL string
+
+	// MLEnsembleEndpoints maps model names to ML engine base URLs; when set,
+	// leverage is assessed by all of them instead of MLAPIEndpoint and their
+	// answers are combined by MLEnsembleWeights (1 for unlisted models).
+	// EMERGENCY_DELEVERAGE needs MLEnsembleQuorum models to agree (0 means a
+	// majority of the ensemble).
+	MLEnsembleEndpoints map[string]string
+	MLEnsembleWeights   map[string]float64
+	MLEnsembleQuorum    int
+
+	// MaxResponseBytes caps the size of an ML response body (0 disables)
+	MaxResponseBytes int64
+
+	// MLMaxConcurrency caps in-flight ML requests across all monitors;
+	// KYCConcurrency is the number of KYC assessment workers
+	MLMaxConcurrency int
+	KYCConcurrency   int
+	// MLQueueDepth caps requests waiting for an ML slot; past it requests
+	// fail fast with ErrMLBusy (0 rejects whenever every slot is busy)
+	MLQueueDepth int
+
+	// KYC jurisdiction fast paths: investments from blocked jurisdictions are
+	// flagged high risk without an ML call, and allowlisted low-risk ones
+	// reuse their last ML assessment for KYCAllowedReassessInterval
+	KYCAllowedJurisdictions    []string
+	KYCBlockedJurisdictions    []string
+	KYCAllowedReassessInterval time.Duration
+
+	// KYCRulesPath is a JSON file of hard compliance rules keyed by
+	// jurisdiction, checked independently of the ML score; violations are
+	// alerted at KYCRuleViolationSeverity
+	KYCRulesPath             string
+	KYCRuleViolationSeverity string
+
+	// Retries for transient RPC and ML failures: RetryAttempts extra tries
+	// with jittered backoff doubling from RetryBaseDelay up to RetryMaxDelay
+	RetryAttempts  int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+
+	// RetryBudget is the total time all ML and RPC calls of one scheduled
+	// run may spend retrying: backoff waits plus the retried attempts. Once
+	// spent, failing calls are not retried and the run fails fast. It is
+	// separate from CycleTimeout, which bounds the whole run including
+	// first attempts; keep it well below CycleTimeout so a flaky dependency
+	// ends the run with its error before the timeout cancels it (0 disables)
+	RetryBudget time.Duration
+
+	// DebugMLPayloads logs redacted ML request/response bodies at debug level.
+	// Payloads can contain investor data, so keep this off in production.
+	DebugMLPayloads bool
+
+	// LogLevel is a logrus level name; defaults to info
+	LogLevel string
+
+	// Decimal places for risk scores, confidence, health factor and LTV, and
+	// for NAV in human units, in logs, /status, /history and /metrics
+	ScorePrecision int
+	NAVPrecision   int
+
+	// WarnSampleInterval logs a warning that repeats every cycle at most once
+	// per interval, with a count of the repeats suppressed (0 disables)
+	WarnSampleInterval time.Duration
 
 	MaxGasPrice *big.Int
 	GasLimit    uint64
 
-	PrivateKey string
+	// Gas price multipliers applied to the node's suggestion by action
+	// urgency. Routine transactions are capped by MaxGasPrice; emergency
+	// ones may exceed it up to EmergencyMaxGasPrice (nil or 0 uncaps).
+	RoutineGasMultiplier   float64
+	EmergencyGasMultiplier float64
+	EmergencyMaxGasPrice   *big.Int
+
+	// Signer: set PrivateKey or KeystorePath (V3 JSON), never both. The
+	// keystore password comes from KeystorePassword or KeystorePasswordFile.
+	PrivateKey           string
+	KeystorePath         string
+	KeystorePassword     string
+	KeystorePasswordFile string
+
+	// ExpectedKeeperAddress, when set, makes startup fail unless the signer
+	// above derives this address; a rotated-in signer is not checked
+	ExpectedKeeperAddress string
+
+	// Incoming signer that /admin/rotate-signer switches to, configured the
+	// same way as the active one
+	IncomingPrivateKey           string
+	IncomingKeystorePath         string
+	IncomingKeystorePassword     string
+	IncomingKeystorePasswordFile string
+
+	// Transaction safety
+	MaxInFlightTx      int
+	TxConfirmTimeout   time.Duration
+	TxConfirmations    uint64
+	DeleverageFraction float64
+
+	// RecoverPendingTx adopts transactions a previous run left pending at
+	// startup and waits for them before scheduling new actions
+	RecoverPendingTx bool
+
+	// SimulateBeforeSend eth_calls an emergency deleverage first and skips
+	// the transaction, alerting instead, if the simulation reverts
+	SimulateBeforeSend bool
+
+	// MaxDeleverageSlippageBps bounds how far below the AIT token's NAV an
+	// emergency deleverage may sell, in basis points; 0 disables the guard.
+	// The bound is passed to the strategy when its ABI defines
+	// emergencyDeleverageWithMinOut, and a quote from DeleverageQuoterAddr
+	// below it skips the deleverage and alerts. Without the min-out methods
+	// the bound is not enforced on-chain and startup alerts.
+	MaxDeleverageSlippageBps int
+	DeleverageQuoterAddr     string
+
+	// DeleverageApprovalSpender, when set, is the address an emergency
+	// deleverage pulls AIT from the keeper through; the keeper approves it
+	// for the amount sold first if its allowance is short, or for the
+	// maximum amount with UseInfiniteApproval
+	DeleverageApprovalSpender string
+	UseInfiniteApproval       bool
+
+	// Rolling 24h transaction budget; zero/nil disables a cap. Emergency
+	// deleverages only count against MaxDailyEmergencyTx (0 = exempt).
+	MaxDailyTx          int
+	MaxDailyGasWei      *big.Int
+	MaxDailyEmergencyTx int
+
+	// StateBackend selects where bot state is persisted (file or memory);
+	// the file backend, the default outside the local profile, writes JSON
+	// to StatePath
+	StateBackend string
+	StatePath    string
+
+	// Keeper gas balance: below MinKeeperBalanceWei the bot alerts and, if a
+	// FundingURL (HTTP relayer) or FundingContractAddr is set, requests a
+	// top-up at most once per RefillCooldown
+	MinKeeperBalanceWei *big.Int
+	FundingURL          string
+	FundingContractAddr string
+	RefillCooldown      time.Duration
+
+	// PrivateTxRelayURL accepts eth_sendPrivateTransaction (e.g. Flashbots
+	// Protect); actions listed in PrivateTxActions are submitted through it
+	PrivateTxRelayURL string
+	PrivateTxActions  []string
+
+	// AdminToken is the bearer token for /admin endpoints on the health
+	// port; empty disables them
+	AdminToken string
+	// EnablePprof serves net/http/pprof under /debug/pprof on the health
+	// port, behind AdminToken; off by default
+	EnablePprof bool
+
+	// Alert sinks; each is enabled by its own settings and alerts fan out to
+	// all of them. With none set alerts are logged only.
+	SlackWebhookURL     string
+	DiscordWebhookURL   string
+	TelegramBotToken    string
+	TelegramChatID      string
+	PagerDutyRoutingKey string
+	AlertWebhookURL     string
+
+	// AlertRouting maps a sink name to the lowest severity it receives
+	// (warning or critical); unlisted sinks receive everything
+	AlertRouting map[string]string
+
+	// AlertDedupRetention sends an alert about a persisting condition (low
+	// balance, revoked role, stale model...) once per retention; when the
+	// state backend persists, the keys survive a restart (0 disables)
+	AlertDedupRetention time.Duration
 
 	CriticalRisk    float64
 	HighRisk        float64
 	MaxLTV          float64
 	MinHealthFactor float64
-	MinLiquidity    float64
+	// MinLiquidity is the lowest liquidity score, the strategy's stablecoin
+	// balance over its debt, at which leverage is reduced; below it the
+	// reduction is deferred and new positions are paused instead (0 disables)
+	MinLiquidity float64
+
+	// ThresholdOverride cross-checks ML assessments that recommend no action
+	// against the thresholds above, acting anyway when the position breaches
+	// MinHealthFactor or MaxLTV or the risk score reaches HighRisk or
+	// CriticalRisk. Assessments too stale or unconfident to act on are still
+	// checked against MinHealthFactor and MaxLTV (see overrideNotActionable).
+	ThresholdOverride bool
+
+	// RecommendationPolicy picks the one recommendation acted on when an
+	// assessment makes several: most_aggressive (highest severity),
+	// least_aggressive (lowest severity) or ml_order (first as returned)
+	RecommendationPolicy string
+
+	// UnknownRiskLevelAs is the risk level (LOW, MEDIUM, HIGH or CRITICAL)
+	// assumed when the ML engine reports an unrecognized one, which is also
+	// alerted. HIGH and CRITICAL reduce or emergency deleverage a borrowed
+	// position the assessment recommends no action for.
+	UnknownRiskLevelAs string
+
+	// EnableFallbackPolicy lets the leverage monitor reduce leverage based on
+	// MinHealthFactor and MaxLTV alone when the ML engine is unavailable
+	EnableFallbackPolicy bool
+
+	// LocalModelPath is an ONNX model scoring leverage risk in-process; with
+	// EnableFallbackPolicy it replaces the rule-based fallback while the ML
+	// engine is unavailable. Requires a build with -tags onnx and the ONNX
+	// Runtime shared library, found at ONNXRuntimeLibPath if set.
+	LocalModelPath     string
+	ONNXRuntimeLibPath string
+
+	// AlwaysActRecommendations are acted on even when the ML engine reports
+	// action_required=false; other recommendations are then only advisory
+	AlwaysActRecommendations []string
+
+	// Per-action cooldowns: once a risk action fires for a position, the same
+	// action is suppressed for that position until the cooldown elapses so a
+	// reduction still taking effect is not repeated (0 disables)
+	ReduceLeverageCooldown      time.Duration
+	PauseNewPositionsCooldown   time.Duration
+	EmergencyDeleverageCooldown time.Duration
+
+	// SubAccountMonitoring assesses each sub-account of a strategy that
+	// exposes them and targets risk actions at the unhealthy account;
+	// strategies without sub-accounts are monitored in aggregate
+	SubAccountMonitoring bool
+
+	// Confidence floors by action risk: NAV writes need MinNAVConfidence,
+	// risk-reducing leverage actions MinDeleverageConfidence. Assessments
+	// older than MaxAssessmentAge are rejected before confidence is
+	// considered (0 disables the age check).
+	MinNAVConfidence        float64
+	MinDeleverageConfidence float64
+	MaxAssessmentAge        time.Duration
+
+	// MaxClockSkew is the largest tolerated drift of ML response and block
+	// timestamps from the local clock before warning (0 disables)
+	MaxClockSkew time.Duration
+
+	// NAV smoothing: NAVSmoothingAlpha in (0,1) weights the new prediction
+	// against the on-chain NAV (0 or 1 disables smoothing). Updates moving
+	// NAV by less than MinNAVChange (per token, USDC) are skipped.
+	NAVSmoothingAlpha float64
+	MinNAVChange      float64
+
+	// MinNAVUpdateInterval skips a token whose on-chain NAV was updated more
+	// recently than this, so a re-run cycle cannot publish twice (0 disables)
+	MinNAVUpdateInterval time.Duration
+
+	// MaxNAVAge forces an update of a token whose on-chain NAV is older than
+	// this, accepting predictions down to ForcedNAVMinConfidence and
+	// publishing even below MinNAVChange (0 disables). It must exceed
+	// MinNAVUpdateInterval.
+	MaxNAVAge              time.Duration
+	ForcedNAVMinConfidence float64
+
+	// Degraded mode, entered while the last health check found the ML
+	// engine or chain unhealthy, the RPC client is reconnecting or a clock
+	// is skewed: confidence floors rise by DegradedConfidenceMargin,
+	// MinNAVChange is multiplied by DegradedNAVChangeMultiplier and, with
+	// DegradedBlockRoutineTx, writes other than emergency deleverages,
+	// leverage reductions and gas top-ups are suspended
+	DegradedConfidenceMargin    float64
+	DegradedNAVChangeMultiplier float64
+	DegradedBlockRoutineTx      bool
+
+	// NAVSubmitMode selects how NAV updates reach the chain: send (keeper
+	// transactions) or attest (EIP-712 attestations POSTed to
+	// AttestationRelayURL for a relayer to submit)
+	NAVSubmitMode       string
+	AttestationRelayURL string
+
+	// AttestationSinkURL receives a POST of every keeper-signed NAV input
+	// attestation, for consumers verifying published NAVs; they are always
+	// written to the audit log
+	AttestationSinkURL string
+
+	// Per-invoice default predictions, requested each NAV cycle when
+	// InvoiceDefaultThreshold is above zero. Invoices whose predicted default
+	// probability exceeds it are alerted and, when InvoiceImpairMethod names
+	// an invoice token method taking the invoice ID, marked impaired
+	// on-chain. Predictions are fetched InvoiceDefaultPageSize invoices at a
+	// time on InvoiceDefaultConcurrency workers.
+	InvoiceDefaultThreshold   float64
+	InvoiceDefaultPageSize    int
+	InvoiceDefaultConcurrency int
+	InvoiceImpairMethod       string
+
+	// Event-driven triggering of leverage monitoring (requires a websocket RPC)
+	EventTriggerEnabled bool
+	TriggerEvents       []string
+	EventDebounce       time.Duration
+
+	// Reorg safety for cursor-based event scans: only blocks at least
+	// ReorgSafetyBlocks below head are processed, and processed blocks within
+	// ReorgLookbackBlocks of the cursor are re-checked for hash changes
+	ReorgSafetyBlocks   uint64
+	ReorgLookbackBlocks uint64
+
+	// HTTP listeners: health/status on HealthListenAddr; metrics on
+	// MetricsListenAddr if set (e.g. a private interface), otherwise on the
+	// health port. MetricsEnabled=false serves no metrics at all.
+	HealthListenAddr  string
+	MetricsListenAddr string
+	MetricsEnabled    bool
+
+	// StatsDAddr (host:port) also pushes every metric to a StatsD or
+	// DogStatsD agent over UDP, with labels as tags
+	StatsDAddr string
+
+	// BlockTag (latest, safe or finalized) is the block contract reads use;
+	// LeverageBlockTag and NAVBlockTag override it per monitor when set
+	BlockTag         string
+	LeverageBlockTag string
+	NAVBlockTag      string
+
+	// Multicall3Addr batches each monitoring cycle's contract reads into one
+	// aggregate3 call; empty, or no contract deployed there, reads each
+	// field with its own call
+	Multicall3Addr string
+
+	// StartupGracePeriod after Start during which monitors run and log but
+	// transactions are only signed and audited, never broadcast
+	StartupGracePeriod time.Duration
+
+	// RequireHealthyStart holds Start until the initial health check passes,
+	// retrying with backoff, and fails it after StartupHealthTimeout.
+	// Otherwise the bot starts degraded after logging the failed checks.
+	RequireHealthyStart  bool
+	StartupHealthTimeout time.Duration
+
+	// AuditLogPath is the append-only JSON-lines record of every on-chain
+	// action (empty disables it); DryRun signs and audits transactions
+	// without broadcasting them
+	AuditLogPath string
+	DryRun       bool
+
+	// DecisionLogPath records every leverage decision as JSON lines for
+	// replay with --compare (empty disables it)
+	DecisionLogPath string
+
+	// ReadinessMaxAge is how long a passing health check keeps the bot ready
+	ReadinessMaxAge time.Duration
+
+	// MaxCycleAge fails /health when no monitor has completed successfully
+	// for this long (0 disables); HeartbeatURL is pinged after each
+	// successful monitor run for an external dead man's switch
+	MaxCycleAge  time.Duration
+	HeartbeatURL string
+
+	// CycleTimeout cancels a scheduled job that runs longer than this
+	// (0 disables)
+	CycleTimeout time.Duration
+
+	// MaxConsecutiveFailures is how many scheduled runs of a monitor may fail
+	// in a row before a critical alert and FailureAction (0 disables):
+	// "shutdown" stops the bot, "disable" pauses the monitor until an
+	// operator resumes it
+	MaxConsecutiveFailures int
+	FailureAction          string
+
+	// Monitor schedules in minutes. Intervals dividing an hour, or a whole
+	// hour, run on the clock (15 runs at :00, :15, :30 and :45); others run
+	// every interval from startup.
+	LeverageMonitorInterval int
+	NAVUpdateInterval       int
+	KYCMonitorInterval      int
+	HealthCheckInterval     int
+
+	// RoleCheckInterval in minutes re-queries every role the keeper needs,
+	// so a revocation is caught and a restored role resumes actions; health
+	// checks reuse role results younger than this. Token decimals are
+	// immutable and stay cached for the process lifetime.
+	RoleCheckInterval int
+
+	// EnvFile is a KEY=VALUE file read for settings the process environment
+	// leaves unset, re-read on SIGHUP to reload the hot-reloadable settings
+	EnvFile string
 }
 
 type Bot struct {
-	config        *Config
-	client        *ethclient.Client
-	privateKey    *ecdsa.PrivateKey
-	address       common.Address
-	chainID       *big.Int
-	logger        *logrus.Logger
-	httpClient    *http.Client
-	cron          *cron.Cron
-	emergencyMode bool
-	mutex         sync.Mutex
-
-	leveragedStrategy common.Address
-	invoiceToken      common.Address
-	kycVerifier       common.Address
+	// config is replaced whole by Reload, never modified in place; read it
+	// through cfg
+	config atomic.Pointer[Config]
+	client *ethclient.Client
+	// txNode, when set, stands in for client when signing and sending
+	// transactions; see txClient
+	txNode       txBackend
+	privateRelay *rpc.Client
+	privateKey   *ecdsa.PrivateKey
+	address      common.Address
+	chainID      *big.Int
+	logger       *logrus.Logger
+	httpClient   *http.Client
+	cron         *cron.Cron
+	// cronJobs are the scheduled jobs, by name: rescheduled by a config
+	// reload, reported on /status and triggered through the admin API
+	cronJobs map[string]*cronJob
+	// emergencyMode is set once an emergency deleverage has confirmed and
+	// cleared by an operator through ClearEmergencyMode; pendingEmergencies
+	// are those sent but not yet confirmed
+	emergencyMode      bool
+	pendingEmergencies map[common.Hash]PendingEmergency
+	mutex              sync.Mutex
+	metrics            *Metrics
+	statsd             *StatsDSink
+	localScorer        LocalScorer
+	txSlots            chan struct{}
+	mlSlots            chan struct{}
+	mlQueue            chan struct{}
+	riskActions        map[string]RiskAction
+
+	// Background goroutines (event watchers, tx confirmation) stop on bgCtx
+	// cancellation and are awaited via bgWG during Close
+	bgCtx    context.Context
+	bgCancel context.CancelFunc
+	bgWG     sync.WaitGroup
+
+	// Per-monitor run locks so overlapping triggers skip instead of stacking
+	leverageRun sync.Mutex
+	navRun      sync.Mutex
+	kycRun      sync.Mutex
+
+	alerter          *Alerter
+	audits           *AuditLog
+	decisions        *DecisionLog
+	store            Store
+	budget           txBudget
+	lastRefill       time.Time
+	gasSpent         *big.Int
+	gasSpentByAction map[string]*big.Int
+
+	lastHealth   *HealthReport
+	modelInfo    *ModelInfo
+	history      []MonitorRun
+	shuttingDown bool
+	startedAt    time.Time
+	createdAt    time.Time
+	reconnecting bool
+
+	// lastCycleAt is the UnixNano time of the last successful monitor run,
+	// read without the mutex so /health answers even if the bot is stuck
+	lastCycleAt atomic.Int64
+
+	// noSubAccounts caches strategies whose contract does not expose sub-accounts
+	noSubAccounts map[common.Address]bool
+
+	// cooldowns maps risk actions against a position to when they may fire again
+	cooldowns map[cooldownKey]time.Time
+
+	// kycCache holds the last assessment of allowlisted-jurisdiction investments
+	kycCache map[string]kycCacheEntry
+	// kycRules are the jurisdiction rules from KYCRulesPath, by upper-case code
+	kycRules map[string]JurisdictionRule
+	// flaggedInvoices holds invoices already alerted for a predicted default,
+	// true once marked impaired on-chain
+	flaggedInvoices map[invoiceKey]bool
+
+	// clockSkewed records which clock skew sources are over MaxClockSkew
+	clockSkewed map[string]bool
+	// degraded are the reasons for degraded mode, empty when healthy
+	degraded []string
+	// pendingApprovals are approvals sent but possibly not yet mined
+	pendingApprovals map[approvalKey]pendingApproval
+	// localNonce is the nonce after the keeper's last broadcast
+	localNonce uint64
+
+	// warnSamples tracks repeating warnings for log sampling
+	warnSamples map[string]*warnSample
+	// alertsSent maps dedup keys to when their alert was last sent;
+	// alertsSaveMu orders its saves so an older snapshot never overwrites a
+	// newer one
+	alertsSent   map[string]time.Time
+	alertsSaveMu sync.Mutex
+
+	// pausedMonitors are the monitors an operator paused via the admin API,
+	// or that MaxConsecutiveFailures disabled
+	pausedMonitors map[string]bool
+	// halt receives the error stopping the bot on its own, ending Start
+	halt chan error
+
+	// revokedRoles maps contracts whose keeper role was revoked mid-run to
+	// the role name; actions against them are suspended until it returns
+	revokedRoles map[common.Address]string
+	// roleChecks caches the last conclusive role query per contract
+	roleChecks map[common.Address]roleCheck
+
+	// abis pack and unpack contract calls
+	abis contractABIs
+
+	// multicall is the Multicall3 contract batched reads go through, nil
+	// when reads are made one call each. Read-only after New.
+	multicall *common.Address
+
+	// Token decimals read at startup: per strategy position token and per
+	// invoice token. Read-only after New.
+	strategyDecimals map[common.Address]strategyTokens
+	tokenDecimals    map[common.Address]int
+
+	// Blocks monitors read contract state at (nil for latest)
+	leverageBlock *big.Int
+	navBlock      *big.Int
+
+	leveragedStrategies []common.Address
+	invoiceTokens       []common.Address
+	kycVerifier         common.Address
+}
+
+// StrategyPosition is a leveraged strategy's position as read from chain
+type StrategyPosition struct {
+	TotalCollateral float64 `json:"total_collateral"`
+	TotalBorrowed   float64 `json:"total_borrowed"`
+	LTV             float64 `json:"ltv"`
+	HealthFactor    float64 `json:"health_factor"`
+	AITValue        float64 `json:"ait_value"`
 }
 
 type LeverageHealthResponse struct {
@@ -56,6 +589,8 @@ type LeverageHealthResponse struct {
 	ActionRequired     bool     `json:"action_required"`
 	Recommendations    []string `json:"recommendations"`
 	Timestamp          int64    `json:"timestamp"`
+	// Confidence is optional; engines that omit it are fully trusted
+	Confidence *float64 `json:"confidence,omitempty"`
 }
 
 type KYCRiskResponse struct {
@@ -67,9 +602,21 @@ type KYCRiskResponse struct {
 }
 
 type NAVPredictionResponse struct {
-	PredictedNAV           float64 `json:"predicted_nav"`
+	PredictedNAV           Decimal `json:"predicted_nav"`
 	Confidence             float64 `json:"confidence"`
 	ExpectedCollectionRate float64 `json:"expected_collection_rate"`
 	RiskAdjustedYield      float64 `json:"risk_adjusted_yield"`
 	Timestamp              int64   `json:"timestamp"`
 }
+
+// InvoiceDefaultResponse is one page of per-invoice default predictions
+type InvoiceDefaultResponse struct {
+	Predictions []InvoiceDefaultPrediction `json:"predictions"`
+	Timestamp   int64                      `json:"timestamp"`
+}
+
+// InvoiceDefaultPrediction is the predicted default probability of one invoice
+type InvoiceDefaultPrediction struct {
+	InvoiceID          uint64  `json:"invoice_id"`
+	DefaultProbability float64 `json:"default_probability"`
+}
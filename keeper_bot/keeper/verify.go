@@ -0,0 +1,140 @@
+package keeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+// mlSchemaCheck is a representative request for an ML endpoint and the
+// response fields it must return
+type mlSchemaCheck struct {
+	endpoint string
+	payload  MLRequest
+	required []string
+}
+
+var mlSchemaChecks = []mlSchemaCheck{
+	{
+		endpoint: "leverage-health",
+		payload: LeverageHealthRequest{
+			TotalCollateral:     1000000,
+			TotalBorrowed:       600000,
+			CurrentHealthFactor: 1.5,
+			AITValue:            650000,
+		},
+		required: []string{"composite_risk_score", "risk_level", "action_required", "recommendations", "timestamp"},
+	},
+	{
+		endpoint: "kyc-risk-assessment",
+		payload: KYCRequest{
+			InvestmentAmount:     500000,
+			Tier:                 2,
+			Jurisdiction:         "US",
+			TransactionFrequency: 5,
+			WalletAgeDays:        100,
+		},
+		required: []string{"kyc_risk_score", "risk_classification", "verification_required", "compliance_flags", "timestamp"},
+	},
+	{
+		endpoint: "invoice-nav-prediction",
+		payload: NAVRequest{
+			TotalFaceValue:   5000000,
+			NumberOfInvoices: 100,
+			WeightedMaturity: 90,
+			ExpectedYield:    800,
+			DefaultRate:      300,
+			RealizedYield:    200000,
+			TotalSupply:      4800000,
+		},
+		required: []string{"predicted_nav", "confidence", "expected_collection_rate", "risk_adjusted_yield", "timestamp"},
+	},
+}
+
+// Verify runs a one-shot deployment self-test: ML engine health and response
+// schemas, RPC chain ID, and read-only contract calls. It never sends
+// transactions. Each check is logged and any failures are returned together.
+func (b *Bot) Verify(ctx context.Context) error {
+	report := &HealthReport{CheckedAt: time.Now()}
+
+	report.add("ml_health", b.probeMLHealth(ctx))
+
+	for _, check := range mlSchemaChecks {
+		report.add("ml_"+check.endpoint, b.verifyMLSchema(ctx, check))
+	}
+
+	report.add("rpc_chain_id", b.verifyChainID(ctx))
+
+	for _, strategy := range b.leveragedStrategies {
+		_, err := b.readPosition(ctx, strategy, common.Address{}, b.leverageBlock)
+		report.add("contract_strategy_"+strategy.Hex(), err)
+	}
+
+	for _, token := range b.invoiceTokens {
+		_, err := b.readPool(ctx, token, b.navBlock)
+		report.add("contract_invoice_token_"+token.Hex(), err)
+	}
+
+	for _, req := range b.requiredRoles() {
+		report.add("role_"+req.name, b.checkRole(ctx, req))
+	}
+
+	for _, check := range report.Checks {
+		entry := b.logger.WithField("check", check.Name)
+		if check.OK {
+			entry.Info("Verify: PASS")
+		} else {
+			entry.WithField("error", check.Error).Error("Verify: FAIL")
+		}
+	}
+
+	if failed := report.Failed(); len(failed) > 0 {
+		return fmt.Errorf("%d of %d checks failed: %s", len(failed), len(report.Checks), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// verifyMLSchema calls an ML endpoint and checks the response has every required field
+func (b *Bot) verifyMLSchema(ctx context.Context, check mlSchemaCheck) error {
+	response, err := b.callMLAPI(ctx, check.endpoint, check.payload)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(response, &fields); err != nil {
+		return fmt.Errorf("response is not a JSON object: %w", err)
+	}
+
+	var missing []string
+	for _, field := range check.required {
+		if _, ok := fields[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("response missing fields: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// verifyChainID checks the RPC reports the configured chain ID
+func (b *Bot) verifyChainID(ctx context.Context) error {
+	config := b.cfg()
+	chainID, err := b.eth().ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	if chainID.Cmp(big.NewInt(config.ChainID)) != 0 {
+		return fmt.Errorf("expected chain ID %d, RPC reports %s", config.ChainID, chainID)
+	}
+
+	b.logger.WithFields(logrus.Fields{"chain_id": chainID}).Debug("Chain ID verified")
+	return nil
+}
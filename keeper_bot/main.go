@@ -3,96 +3,124 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"errors"
+	"flag"
 	"log"
-	"math/big"
-	"net/http"
 	"os"
-	"time"
+	"os/signal"
+	"syscall"
 
 	"github.com/veritas/keeper-bot/keeper"
 )
 
-// HealthServer handles HTTP health check endpoints
-type HealthServer struct {
-	bot *keeper.Bot
-}
-
-// ServeHTTP implements http.Handler interface
-func (h *HealthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if r.URL.Path == "/health" {
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]string{
-			"status": "healthy",
-			"time":   time.Now().Format(time.RFC3339),
-		})
-		return
-	}
+func main() {
+	verify := flag.Bool("verify", false, "run a one-shot connectivity and schema self-test, then exit")
+	once := flag.Bool("once", false, "run every enabled monitor once, then exit non-zero if any failed")
+	compare := flag.String("compare", "", "replay a decision log through the current decision logic, then exit non-zero if any decision diverged")
+	reportOut := flag.String("report-out", "", "with --once, write a JSON report of the inputs, decisions and actions to this path")
+	flag.Parse()
 
-	if r.URL.Path == "/metrics" {
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, "# Veritas Keeper Bot Metrics\n")
-		fmt.Fprintf(w, "veritas_keeper_uptime_seconds %d\n", time.Now().Unix())
-		return
+	if *reportOut != "" && !*once {
+		log.Fatalf("--report-out requires --once")
 	}
 
-	w.WriteHeader(http.StatusNotFound)
-}
-
-func main() {
 	// Load configuration
-	config := &keeper.Config{
-		MantleRPC:             getEnv("MANTLE_RPC", "https://rpc.mantle.xyz"),
-		ChainID:               5000, // Mantle Mainnet
-		LeveragedStrategyAddr: os.Getenv("LEVERAGED_STRATEGY_ADDR"),
-		InvoiceTokenAddr:      os.Getenv("INVOICE_TOKEN_ADDR"),
-		KYCVerifierAddr:       os.Getenv("KYC_VERIFIER_ADDR"),
-		MLAPIEndpoint:         getEnv("ML_API_ENDPOINT", "http://localhost:5000"),
-		PrivateKey:            os.Getenv("KEEPER_PRIVATE_KEY"),
-		MaxGasPrice:           big.NewInt(5000000000), // 5 Gwei
-		GasLimit:              500000,
-
-		// Risk thresholds
-		CriticalRisk:    0.8,
-		HighRisk:        0.6,
-		MaxLTV:          0.65,
-		MinHealthFactor: 1.3,
-		MinLiquidity:    0.3,
+	config, err := keeper.LoadConfig()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
 	}
 
-	// Validate required config
-	if config.PrivateKey == "" {
-		log.Fatal("KEEPER_PRIVATE_KEY environment variable required")
+	// Replay needs neither the chain nor the ML engine
+	if *compare != "" {
+		os.Exit(compareDecisions(config, *compare))
 	}
 
-	// Initialize keeper bot
-	bot, err := keeper.New(config)
+	// Initialize a keeper bot per configured chain
+	fleet, err := keeper.NewFleet(config)
 	if err != nil {
 		log.Fatalf("Failed to initialize keeper bot: %v", err)
 	}
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Start health check server
-	healthServer := &HealthServer{bot: bot}
-	go func() {
-		log.Println("Starting health check server on :8080")
-		if err := http.ListenAndServe(":8080", healthServer); err != nil {
-			log.Printf("Health server error: %v", err)
+	if *verify {
+		err := fleet.Verify(ctx)
+		fleet.Close()
+		if err != nil {
+			log.Fatalf("Verification failed: %v", err)
 		}
-	}()
+		log.Println("Verification passed")
+		return
+	}
 
-	// Start keeper bot
-	if err := bot.Start(ctx); err != nil {
+	if *once {
+		err := fleet.RunOnce(ctx)
+		fleet.Close()
+		if *reportOut != "" {
+			if reportErr := fleet.WriteReports(*reportOut); reportErr != nil {
+				log.Fatalf("Report failed: %v", reportErr)
+			}
+		}
+		if err != nil {
+			log.Fatalf("Monitor run failed: %v", err)
+		}
+		log.Println("Monitor run completed")
+		return
+	}
+
+	// Start health (and metrics) servers
+	waitServers := serveHTTP(ctx, fleet, config)
+
+	go reloadOnHangup(ctx, fleet)
+
+	// Start keeper bots; returns once a shutdown signal cancels ctx
+	err = fleet.Start(ctx)
+	waitServers()
+	if err != nil && !errors.Is(err, context.Canceled) {
 		log.Fatalf("Keeper bot error: %v", err)
 	}
 }
 
-// getEnv gets environment variable with default fallback
-func getEnv(key, defaultVal string) string {
-	if val := os.Getenv(key); val != "" {
-		return val
+// reloadOnHangup re-reads the configuration on each SIGHUP and applies its
+// hot-reloadable settings, until ctx is cancelled
+func reloadOnHangup(ctx context.Context, fleet *keeper.Fleet) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			config, err := keeper.LoadConfig()
+			if err == nil {
+				err = fleet.Reload(config)
+			}
+			if err != nil {
+				log.Printf("Config reload failed: %v", err)
+			}
+		}
+	}
+}
+
+// compareDecisions replays a decision log and returns the process exit code
+func compareDecisions(config *keeper.Config, path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Printf("Failed to open decision log: %v", err)
+		return 2
+	}
+	defer file.Close()
+
+	diverged, err := keeper.CompareDecisions(config, file, os.Stdout)
+	if err != nil {
+		log.Printf("Decision replay failed: %v", err)
+		return 2
+	}
+	if diverged > 0 {
+		return 1
 	}
-	return defaultVal
+	return 0
 }
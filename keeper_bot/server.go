@@ -0,0 +1,344 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/veritas/keeper-bot/keeper"
+)
+
+// HealthServer handles HTTP health check endpoints
+type HealthServer struct {
+	fleet *keeper.Fleet
+	// metrics is served on /metrics when metrics share the health port
+	metrics http.Handler
+	// adminToken guards /admin endpoints; empty disables them
+	adminToken string
+	// pprof serves /debug/pprof behind adminToken when EnablePprof is set
+	pprof http.Handler
+}
+
+// ServeHTTP implements http.Handler interface
+func (h *HealthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/health" {
+		healthy := true
+		bodies := make(map[string]interface{})
+		var body map[string]interface{}
+		for _, bot := range h.fleet.Bots() {
+			cycle := bot.CycleHealth()
+			healthy = healthy && cycle.Healthy
+			body = cycleBody(cycle)
+			bodies[bot.Chain()] = body
+		}
+		status, code := "healthy", http.StatusOK
+		if !healthy {
+			status, code = "unhealthy", http.StatusServiceUnavailable
+		}
+		// A multi-chain keeper reports each chain under its name
+		if len(bodies) > 1 {
+			body = map[string]interface{}{"chains": bodies}
+		}
+		body["status"] = status
+		body["time"] = time.Now().Format(time.RFC3339)
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(body)
+		return
+	}
+
+	if r.URL.Path == "/livez" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.URL.Path == "/readyz" {
+		ready := true
+		bodies := make(map[string]interface{})
+		var body map[string]interface{}
+		for _, bot := range h.fleet.Bots() {
+			chainReady, reason := bot.Ready()
+			ready = ready && chainReady
+			body = map[string]interface{}{"ready": chainReady}
+			if !chainReady {
+				body["reason"] = reason
+			}
+			if report := bot.LastHealthReport(); report != nil {
+				body["health"] = report
+			}
+			bodies[bot.Chain()] = body
+		}
+		if len(bodies) > 1 {
+			body = map[string]interface{}{"ready": ready, "chains": bodies}
+		}
+		status := http.StatusOK
+		if !ready {
+			status = http.StatusServiceUnavailable
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(body)
+		return
+	}
+
+	if r.URL.Path == "/status" {
+		if bot, ok := h.chainBot(w, r); ok {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(bot.Status())
+		}
+		return
+	}
+
+	if r.URL.Path == "/history" {
+		if bot, ok := h.chainBot(w, r); ok {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(bot.History())
+		}
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/admin/") && h.adminToken != "" {
+		h.serveAdmin(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/debug/pprof/") && h.pprof != nil {
+		if !h.authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		h.pprof.ServeHTTP(w, r)
+		return
+	}
+
+	if r.URL.Path == "/metrics" && h.metrics != nil {
+		h.metrics.ServeHTTP(w, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+// cycleBody renders one chain's cycle health for /health
+func cycleBody(cycle keeper.CycleHealth) map[string]interface{} {
+	status := "healthy"
+	if !cycle.Healthy {
+		status = "unhealthy"
+	}
+	body := map[string]interface{}{
+		"status":             status,
+		"last_cycle_age_sec": int64(cycle.Age.Seconds()),
+	}
+	if !cycle.LastSuccess.IsZero() {
+		body["last_successful_cycle"] = cycle.LastSuccess.Format(time.RFC3339)
+	}
+	return body
+}
+
+// chainBot returns the bot selected by the ?chain= query, the first chain's
+// when absent, writing a 404 for an unknown chain
+func (h *HealthServer) chainBot(w http.ResponseWriter, r *http.Request) (*keeper.Bot, bool) {
+	chain := r.URL.Query().Get("chain")
+	bot, ok := h.fleet.Bot(chain)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("unknown chain %q", chain)})
+	}
+	return bot, ok
+}
+
+// authorized reports whether r carries the admin bearer token
+func (h *HealthServer) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) == 1
+}
+
+// serveAdmin handles authenticated operator actions
+func (h *HealthServer) serveAdmin(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	bot, ok := h.chainBot(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.URL.Path {
+	case "/admin/rotate-signer":
+		oldAddress, newAddress, err := bot.RotateSigner(r.Context())
+		if err != nil {
+			log.Printf("Signer rotation failed: %v", err)
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		log.Printf("Signer rotated from %s to %s", oldAddress.Hex(), newAddress.Hex())
+		json.NewEncoder(w).Encode(map[string]string{
+			"old_address": oldAddress.Hex(),
+			"new_address": newAddress.Hex(),
+		})
+
+	case "/admin/emergency/clear":
+		cleared, err := bot.ClearEmergencyMode()
+		if err != nil {
+			log.Printf("Clearing emergency mode failed: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"emergency_mode": false,
+			"cleared":        cleared,
+		})
+
+	default:
+		if strings.HasPrefix(r.URL.Path, "/admin/monitors/") {
+			h.serveMonitorToggle(w, r, bot)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/admin/jobs/") {
+			h.serveJobRun(w, r, bot)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// serveMonitorToggle handles /admin/monitors/{name}/pause and /resume
+func (h *HealthServer) serveMonitorToggle(w http.ResponseWriter, r *http.Request, bot *keeper.Bot) {
+	name, op, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/admin/monitors/"), "/")
+
+	var err error
+	switch op {
+	case "pause":
+		err = bot.PauseMonitor(name)
+	case "resume":
+		err = bot.ResumeMonitor(name)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"monitor": name,
+		"paused":  op == "pause",
+	})
+}
+
+// serveJobRun handles /admin/jobs/{name}/run, starting the job's cycle in
+// the background
+func (h *HealthServer) serveJobRun(w http.ResponseWriter, r *http.Request, bot *keeper.Bot) {
+	name, op, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/admin/jobs/"), "/")
+	if op != "run" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err := bot.RunJob(name); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"job":     name,
+		"started": true,
+	})
+}
+
+// pprofHandler routes the net/http/pprof profiles under /debug/pprof
+func pprofHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// MetricsServer serves the Prometheus scrape endpoint
+type MetricsServer struct {
+	fleet *keeper.Fleet
+}
+
+// ServeHTTP implements http.Handler interface
+func (m *MetricsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/metrics" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "# Veritas Keeper Bot Metrics\n")
+	fmt.Fprintf(w, "veritas_keeper_uptime_seconds %d\n", time.Now().Unix())
+	// Chains share one registry, so any bot writes every chain's metrics
+	m.fleet.Bots()[0].Metrics().WritePrometheus(w)
+}
+
+// serveHTTP starts the health server and, depending on config, a separate
+// metrics server. Both shut down when ctx is cancelled; the returned wait
+// function blocks until they have.
+func serveHTTP(ctx context.Context, fleet *keeper.Fleet, config *keeper.Config) (wait func()) {
+	health := &HealthServer{fleet: fleet, adminToken: config.AdminToken}
+	servers := []*http.Server{{Addr: config.HealthListenAddr, Handler: health}}
+
+	// Profiles expose memory contents and can stall the process, so they are
+	// never served without the admin token
+	if config.EnablePprof {
+		if config.AdminToken == "" {
+			log.Printf("ENABLE_PPROF is set but ADMIN_TOKEN is empty; not serving /debug/pprof")
+		} else {
+			health.pprof = pprofHandler()
+		}
+	}
+
+	if config.MetricsEnabled {
+		metrics := &MetricsServer{fleet: fleet}
+		if config.MetricsListenAddr == "" {
+			health.metrics = metrics
+		} else {
+			servers = append(servers, &http.Server{Addr: config.MetricsListenAddr, Handler: metrics})
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		wg.Add(2)
+		go func(server *http.Server) {
+			defer wg.Done()
+			log.Printf("Starting HTTP server on %s", server.Addr)
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("HTTP server %s error: %v", server.Addr, err)
+			}
+		}(server)
+
+		go func(server *http.Server) {
+			defer wg.Done()
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(shutdownCtx)
+		}(server)
+	}
+
+	return wg.Wait
+}
@@ -3,9 +3,10 @@ import pandas as pd
 import torch
 import torch.nn as nn
 from typing import Dict, List, Tuple
-from datetime import datetime, timedelta
+from datetime import datetime, timedelta, timezone
 from flask import Flask, jsonify, request
 import logging
+import os
 
 class RWARiskLSTM(nn.Module):
     """
@@ -148,6 +149,8 @@ class VeritasMLEngine:
     """
     
     MODEL_VERSION = "v1.2.0-lstm"
+    # Request/response schema; bump the major version on renamed or removed fields
+    SCHEMA_VERSION = "1.0"
     
     def __init__(self, model_path: str = None):
         self.logger = logging.getLogger(__name__)
@@ -160,8 +163,10 @@ class VeritasMLEngine:
         )
         
         # Load pre-trained weights if available
+        self.trained_at = None
         if model_path:
             self.model.load_state_dict(torch.load(model_path))
+            self.trained_at = datetime.fromtimestamp(os.path.getmtime(model_path), tz=timezone.utc)
             self.logger.info(f"Loaded model from {model_path}")
         
         self.model.eval()
@@ -242,6 +247,15 @@ def health_check():
         'timestamp': datetime.now().isoformat()
     })
 
+@app.route('/model-info', methods=['GET'])
+def model_info():
+    """Model version and training date, for staleness checks"""
+    return jsonify({
+        'model_version': ml_engine.MODEL_VERSION,
+        'schema_version': ml_engine.SCHEMA_VERSION,
+        'trained_at': ml_engine.trained_at.isoformat() if ml_engine.trained_at else None
+    })
+
 @app.route('/api/v1/risk-assessment', methods=['GET'])
 def risk_assessment():
     """